@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	. "lazychain/models/settings"
+)
+
+// parseOverrides reads the command line and the LAZYCHAIN_* environment.
+// Flags win over environment variables, which win over config.json.
+func parseOverrides(args []string) (Overrides, error) {
+	var o Overrides
+	fs := flag.NewFlagSet("lazychain", flag.ContinueOnError)
+	fs.StringVar(&o.ConfigPath, "config", "", "config file `path` ($"+EnvConfigPath+")")
+	fs.StringVar(&o.Network, "network", "", "network `name` to use ($"+EnvNetwork+")")
+	fs.StringVar(&o.AlgodURL, "algod-url", "", "algod `url`, port included ($"+EnvAlgodURL+")")
+	fs.StringVar(&o.AlgodToken, "algod-token", "", "algod API `token` ($"+EnvAlgodToken+")")
+	fs.StringVar(&o.IndexerURL, "indexer-url", "", "indexer `url`, port included ($"+EnvIndexerURL+")")
+	fs.StringVar(&o.IndexerToken, "indexer-token", "", "indexer API `token` ($"+EnvIndexerToken+")")
+	fs.StringVar(&o.WalletAddr, "wallet", "", "wallet `address` ($"+EnvWalletAddr+")")
+	fs.StringVar(&o.GoalBinary, "goal", "", "goal binary `path` ($"+EnvGoalBinary+")")
+	fs.StringVar(&o.DataDir, "data-dir", "", "node data `dir` passed to goal -d ($"+EnvDataDir+")")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: lazychain [flags]\n\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(fs.Output(), "\nPrecedence: flags > environment > %s > defaults\n", ConfigPath())
+	}
+	if err := fs.Parse(args); err != nil {
+		return Overrides{}, err
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return Overrides{}, fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+	return OverridesFromEnv().Over(o), nil
+}
+
+func mustParseOverrides() Overrides {
+	o, err := parseOverrides(os.Args[1:])
+	if err == flag.ErrHelp {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	return o
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"testing"
+
+	. "lazychain/models/settings"
+)
+
+func TestParseOverrides(t *testing.T) {
+	t.Setenv(EnvNetwork, "testnet")
+	t.Setenv(EnvAlgodURL, "http://env:4001")
+
+	o, err := parseOverrides([]string{"-network", "mainnet", "-wallet", "ADDR"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// flags win over the environment, which fills in the rest
+	if o.Network != "mainnet" || o.AlgodURL != "http://env:4001" || o.WalletAddr != "ADDR" {
+		t.Errorf("parseOverrides = %+v", o)
+	}
+
+	for _, args := range [][]string{{"-nope"}, {"extra"}} {
+		if _, err := parseOverrides(args); err == nil {
+			t.Errorf("parseOverrides(%q): want an error", args)
+		}
+	}
+}
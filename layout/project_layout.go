@@ -13,6 +13,7 @@ type ProjectLayout struct {
 	BaseLayout
 
 	// Menu data
+	title     string
 	menuItems []string
 	cursor    int
 
@@ -43,6 +44,7 @@ func NewProjectLayout(width, height int) *ProjectLayout {
 			Width:  width,
 			Height: height,
 		},
+		title:           "Main Menu",
 		menuItems:       []string{},
 		cursor:          0,
 		allPreviews:     make(map[string]PreviewContent),
@@ -66,6 +68,12 @@ func (l *ProjectLayout) SetMenuItems(items []string, previewGenerator func(strin
 	return l
 }
 
+// SetTitle sets the title of the menu panel
+func (l *ProjectLayout) SetTitle(title string) *ProjectLayout {
+	l.title = title
+	return l
+}
+
 // SetCursor sets the current cursor position
 func (l *ProjectLayout) SetCursor(cursor int) *ProjectLayout {
 	l.cursor = cursor
@@ -100,9 +108,9 @@ func (l *ProjectLayout) calculateMaxDimensions() {
 
 		// Description (needs wrapping calculation)
 		// Estimate: assume 40 chars per line for wrapping
-		descLines := (len(preview.Description) / 40) + 1
-		if len(preview.Description) > 40 {
-			descLines = len(wrapText(preview.Description, 40))
+		descLines := 0
+		for _, para := range strings.Split(preview.Description, "\n") {
+			descLines += len(wrapText(para, 40))
 		}
 
 		// Instructions (count newlines)
@@ -116,9 +124,11 @@ func (l *ProjectLayout) calculateMaxDimensions() {
 
 		// Calculate width: longest line
 		maxLineWidth := titleWidth
-		for _, line := range wrapText(preview.Description, 50) {
-			if len(line) > maxLineWidth {
-				maxLineWidth = len(line)
+		for _, para := range strings.Split(preview.Description, "\n") {
+			for _, line := range wrapText(para, 50) {
+				if len(line) > maxLineWidth {
+					maxLineWidth = len(line)
+				}
 			}
 		}
 		if maxLineWidth > l.maxPreviewWidth {
@@ -232,7 +242,7 @@ func (l *ProjectLayout) renderMenuContent(maxX, maxY int) string {
 	var content []string
 
 	// Section title
-	title := TitleStyle().Render("Main Menu")
+	title := TitleStyle().Render(l.title)
 	content = append(content, title)
 	content = append(content, "")
 
@@ -317,9 +327,11 @@ func (l *ProjectLayout) renderPreviewContent(maxX, maxY int) string {
 			wrapWidth = 20
 		}
 
-		wrappedDesc := wrapText(preview.Description, wrapWidth)
-		for _, line := range wrappedDesc {
-			content = append(content, descStyle.Render(line))
+		// Each line of the description wraps on its own
+		for _, para := range strings.Split(preview.Description, "\n") {
+			for _, line := range wrapText(para, wrapWidth) {
+				content = append(content, descStyle.Render(line))
+			}
 		}
 	}
 
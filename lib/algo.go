@@ -2,14 +2,15 @@ package algo
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"time"
 
-	"github.com/algorand/go-algorand-sdk/client/v2/algod"
-	"github.com/algorand/go-algorand-sdk/client/v2/indexer"
-	"github.com/algorand/go-algorand-sdk/future"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/indexer"
 	"github.com/algorand/go-algorand-sdk/v2/crypto"
 	"github.com/algorand/go-algorand-sdk/v2/mnemonic"
+	"github.com/algorand/go-algorand-sdk/v2/transaction"
 	"github.com/algorand/go-algorand-sdk/v2/types"
 )
 
@@ -18,6 +19,14 @@ type AlgoClient struct {
 	algod   *algod.Client
 	indexer *indexer.Client
 	account *crypto.Account // signer (privato)
+
+	// genesi attesa: se impostata, nessuna transazione viene firmata
+	// per una chain diversa
+	genesisID   string
+	genesisHash string // base64
+
+	// revisione obbligatoria prima della firma, vedi SetConfirm
+	confirm func(txns []types.Transaction) error
 }
 
 // NewClient inizializza il client per algod e indexer
@@ -38,6 +47,23 @@ func NewClient(algodURL, algodToken, indexerURL string) (*AlgoClient, error) {
 	}, nil
 }
 
+// NewClientFrom riusa client gia' connessi, ad esempio quelli della rete
+// attiva, invece di aprirne di nuovi
+func NewClientFrom(algodClient *algod.Client, indexerClient *indexer.Client) *AlgoClient {
+	return &AlgoClient{
+		algod:   algodClient,
+		indexer: indexerClient,
+	}
+}
+
+// Address restituisce l'indirizzo del signer, vuoto se non impostato
+func (c *AlgoClient) Address() string {
+	if c.account == nil {
+		return ""
+	}
+	return c.account.Address.String()
+}
+
 // SetAccountFromMnemonic carica un account dal mnemonic
 func (c *AlgoClient) SetAccountFromMnemonic(mn string) error {
 	k, err := mnemonic.ToPrivateKey(mn)
@@ -60,6 +86,129 @@ func (c *AlgoClient) SetAccountFromMnemonic(mn string) error {
 	return nil
 }
 
+// SetExpectedGenesis vincola il client alla chain indicata: le transazioni
+// costruite con parametri di un'altra genesi vengono rifiutate alla firma.
+func (c *AlgoClient) SetExpectedGenesis(genesisID, genesisHashB64 string) {
+	c.genesisID = genesisID
+	c.genesisHash = genesisHashB64
+}
+
+// SetConfirm imposta il passo di revisione: fn riceve le transazioni
+// prima della firma e le blocca restituendo un errore. Senza di esso il
+// client non firma nulla.
+func (c *AlgoClient) SetConfirm(fn func(txns []types.Transaction) error) {
+	c.confirm = fn
+}
+
+// confirmed fa passare le transazioni dalla revisione.
+func (c *AlgoClient) confirmed(txns ...types.Transaction) error {
+	if c.confirm == nil {
+		return fmt.Errorf("refusing to sign: no review step set")
+	}
+	return c.confirm(txns)
+}
+
+// checkGenesis verifica che la transazione appartenga alla chain attesa.
+func (c *AlgoClient) checkGenesis(txn types.Transaction) error {
+	if c.genesisHash == "" {
+		return nil
+	}
+	got := base64.StdEncoding.EncodeToString(txn.GenesisHash[:])
+	if got != c.genesisHash || (c.genesisID != "" && txn.GenesisID != c.genesisID) {
+		return fmt.Errorf("refusing to sign: transaction is for genesis %s (%s), expected %s (%s)",
+			txn.GenesisID, got, c.genesisID, c.genesisHash)
+	}
+	return nil
+}
+
+// signAndSend controlla la genesi, firma e invia una transazione.
+func (c *AlgoClient) signAndSend(txn types.Transaction) (string, error) {
+	if c.account == nil {
+		return "", fmt.Errorf("signer not set")
+	}
+	if err := c.checkGenesis(txn); err != nil {
+		return "", err
+	}
+	if err := c.confirmed(txn); err != nil {
+		return "", err
+	}
+
+	txID, signedTxn, err := crypto.SignTransaction(c.account.PrivateKey, txn)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = c.algod.SendRawTransaction(signedTxn).Do(context.Background())
+	if err != nil {
+		return "", submitError{err}
+	}
+
+	return txID, nil
+}
+
+// SignAndSend firma e invia le transazioni costruite altrove. Piu'
+// transazioni vengono unite in un gruppo atomico; restituisce l'ID della
+// prima.
+func (c *AlgoClient) SignAndSend(txns ...types.Transaction) (string, error) {
+	if c.account == nil {
+		return "", fmt.Errorf("signer not set")
+	}
+	switch len(txns) {
+	case 0:
+		return "", fmt.Errorf("no transactions to send")
+	case 1:
+		return c.signAndSend(txns[0])
+	}
+	// la revisione vede le transazioni come arrivano: senza group ID,
+	// oppure gia' raggruppate con AssignGroup
+	if err := c.confirmed(txns...); err != nil {
+		return "", err
+	}
+
+	if txns[0].Group == (types.Digest{}) {
+		if err := AssignGroup(txns); err != nil {
+			return "", err
+		}
+	}
+	var raw []byte
+	for i := range txns {
+		if txns[i].Group != txns[0].Group {
+			return "", fmt.Errorf("transactions are not all in the same group")
+		}
+		if err := c.checkGenesis(txns[i]); err != nil {
+			return "", err
+		}
+		_, signed, err := crypto.SignTransaction(c.account.PrivateKey, txns[i])
+		if err != nil {
+			return "", err
+		}
+		raw = append(raw, signed...)
+	}
+	if _, err := c.algod.SendRawTransaction(raw).Do(context.Background()); err != nil {
+		return "", submitError{err}
+	}
+	return crypto.GetTxID(txns[0]), nil
+}
+
+// SuggestedParams restituisce i parametri suggeriti dal nodo
+func (c *AlgoClient) SuggestedParams() (types.SuggestedParams, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return c.algod.SuggestedParams().Do(ctx)
+}
+
+// WaitForConfirmation attende la conferma di una transazione per al
+// massimo rounds round e restituisce il round di conferma
+func (c *AlgoClient) WaitForConfirmation(txID string, rounds uint64) (uint64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(rounds+1)*5*time.Second)
+	defer cancel()
+	res, err := transaction.WaitForConfirmation(c.algod, txID, rounds, ctx)
+	if err != nil {
+		return 0, err
+	}
+	return res.ConfirmedRound, nil
+}
+
 // GetAccountBalance restituisce il saldo in microAlgos
 func (c *AlgoClient) GetAccountBalance(address string) (uint64, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -72,8 +221,58 @@ func (c *AlgoClient) GetAccountBalance(address string) (uint64, error) {
 	return info.Amount, nil
 }
 
-// SendAlgos invia Algos a un destinatario
-func (c *AlgoClient) SendAlgos(to string, amount uint64, note string) (string, error) {
+// SpendableAlgos e' il "max" di un pagamento: saldo meno saldo minimo e
+// fee. Con fee 0 usa la fee minima suggerita dal nodo.
+func (c *AlgoClient) SpendableAlgos(address string, fee uint64) (Amount, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	info, err := c.algod.AccountInformation(address).Do(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if fee == 0 {
+		sp, err := c.algod.SuggestedParams().Do(ctx)
+		if err != nil {
+			return 0, err
+		}
+		fee = sp.MinFee
+	}
+	return MaxSpendable(info.Amount, info.MinBalance, fee)
+}
+
+// AssetUnits restituisce decimali e unit name di un asset
+func (c *AlgoClient) AssetUnits(assetID uint64) (uint32, string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	asset, err := c.algod.GetAssetByID(assetID).Do(ctx)
+	if err != nil {
+		return 0, "", err
+	}
+	return uint32(asset.Params.Decimals), asset.Params.UnitName, nil
+}
+
+// AssetBalance restituisce le unita' base di un asset possedute da address
+func (c *AlgoClient) AssetBalance(address string, assetID uint64) (Amount, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	res, err := c.algod.AccountAssetInformation(address, assetID).Do(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return Amount(res.AssetHolding.Amount), nil
+}
+
+
+// Gli invii qui sotto costruiscono la transazione da se' e passano da
+// signAndSend: senza l'approvazione del passo di revisione (SetConfirm)
+// restituiscono un errore e non firmano nulla.
+
+// SendAlgos invia Algos a un destinatario; la nota si prepara con
+// EncodeNote
+func (c *AlgoClient) SendAlgos(to string, amount uint64, note []byte) (string, error) {
 	if c.account == nil {
 		return "", fmt.Errorf("signer not set")
 	}
@@ -90,74 +289,39 @@ func (c *AlgoClient) SendAlgos(to string, amount uint64, note string) (string, e
 		return "", err
 	}
 
-	txn, err := future.MakePaymentTxn(
+	txn, err := transaction.MakePaymentTxn(
 		fromAddr,
 		to,
 		amount,
-		nil,
 		note,
+		"",
 		params,
 	)
 	if err != nil {
 		return "", err
 	}
 
-	txID, signedTxn, err := crypto.SignTransaction(c.account.PrivateKey, txn)
-	if err != nil {
-		return "", err
-	}
-
-	_, err = c.algod.SendRawTransaction(signedTxn).Do(context.Background())
-	if err != nil {
-		return "", err
-	}
-
-	return txID, nil
+	return c.signAndSend(txn)
 }
 
-func (c *AlgoClient) CreateAsset(name, unitName string, total uint64, decimals uint32) (string, error) {
+// CreateAsset crea l'asset descritto da a, con l'account impostato come
+// creatore
+func (c *AlgoClient) CreateAsset(a NewAsset) (string, error) {
 	if c.account == nil {
 		return "", fmt.Errorf("signer not set")
 	}
 
-	fromAddr := c.account.Address.String()
-
 	params, err := c.algod.SuggestedParams().Do(context.Background())
 	if err != nil {
 		return "", err
 	}
 
-	txn, err := future.MakeAssetCreateTxn(
-		fromAddr,
-		nil,
-		params,
-		total,
-		decimals,
-		false,
-		fromAddr, // Manager
-		fromAddr, // Reserve
-		fromAddr, // Freeze
-		fromAddr, // Clawback
-		unitName,
-		name,
-		"",
-		"",
-	)
+	txn, err := a.Transaction(c.account.Address.String(), params)
 	if err != nil {
 		return "", err
 	}
 
-	txID, signedTxn, err := crypto.SignTransaction(c.account.PrivateKey, txn)
-	if err != nil {
-		return "", err
-	}
-
-	_, err = c.algod.SendRawTransaction(signedTxn).Do(context.Background())
-	if err != nil {
-		return "", err
-	}
-
-	return txID, nil
+	return c.signAndSend(txn)
 }
 
 func (c *AlgoClient) SendAsset(to string, assetID uint64, amount uint64) (string, error) {
@@ -177,32 +341,24 @@ func (c *AlgoClient) SendAsset(to string, assetID uint64, amount uint64) (string
 		return "", err
 	}
 
-	txn, err := future.MakeAssetTransferTxn(
+	txn, err := transaction.MakeAssetTransferTxn(
 		fromAddr,
 		to,
-		0,      // closeRemainderTo
-		nil,    // note
 		amount, // amount
+		nil,    // note
 		params,
+		"", // closeRemainderTo
 		assetID,
 	)
 	if err != nil {
 		return "", err
 	}
 
-	txID, signedTxn, err := crypto.SignTransaction(c.account.PrivateKey, txn)
-	if err != nil {
-		return "", err
-	}
-
-	_, err = c.algod.SendRawTransaction(signedTxn).Do(context.Background())
-	if err != nil {
-		return "", err
-	}
-
-	return txID, nil
+	return c.signAndSend(txn)
 }
 
+// OptInAsset fa opt-in a un asset, dopo aver controllato che esista e
+// che l'account non lo abbia gia' fatto
 func (c *AlgoClient) OptInAsset(assetID uint64) (string, error) {
 	if c.account == nil {
 		return "", fmt.Errorf("signer not set")
@@ -210,12 +366,23 @@ func (c *AlgoClient) OptInAsset(assetID uint64) (string, error) {
 
 	fromAddr := c.account.Address.String()
 
+	if _, _, err := c.AssetUnits(assetID); err != nil {
+		return "", fmt.Errorf("asset %d: %w", assetID, err)
+	}
+	optedIn, err := c.OptedIn(fromAddr, assetID)
+	if err != nil {
+		return "", err
+	}
+	if optedIn {
+		return "", fmt.Errorf("already opted in to asset %d", assetID)
+	}
+
 	params, err := c.algod.SuggestedParams().Do(context.Background())
 	if err != nil {
 		return "", err
 	}
 
-	txn, err := future.MakeAssetAcceptanceTxn(
+	txn, err := transaction.MakeAssetAcceptanceTxn(
 		fromAddr,
 		nil,
 		params,
@@ -225,15 +392,5 @@ func (c *AlgoClient) OptInAsset(assetID uint64) (string, error) {
 		return "", err
 	}
 
-	txID, signedTxn, err := crypto.SignTransaction(c.account.PrivateKey, txn)
-	if err != nil {
-		return "", err
-	}
-
-	_, err = c.algod.SendRawTransaction(signedTxn).Do(context.Background())
-	if err != nil {
-		return "", err
-	}
-
-	return txID, nil
+	return c.signAndSend(txn)
 }
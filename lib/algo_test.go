@@ -0,0 +1,83 @@
+package algo
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/mnemonic"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// fakeAlgod risponde ai parametri suggeriti e conta le transazioni inviate.
+func fakeAlgod(t *testing.T, sent *int) *AlgoClient {
+	t.Helper()
+	genesis := base64.StdEncoding.EncodeToString(make([]byte, 32))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v2/transactions/params":
+			_, _ = w.Write([]byte(`{"consensus-version":"v1","fee":0,"genesis-hash":"` + genesis +
+				`","genesis-id":"testnet-v1.0","last-round":1000,"min-fee":1000}`))
+		case "/v2/transactions":
+			*sent++
+			_, _ = w.Write([]byte(`{"txId":"TX"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	client, err := algod.MakeClient(srv.URL, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewClientFrom(client, nil)
+	mn, err := mnemonic.FromPrivateKey(crypto.GenerateAccount().PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetAccountFromMnemonic(mn); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// TestSendNeedsReview controlla che gli invii costruiti dal client stesso
+// passino dalla revisione: senza approvazione nulla parte.
+func TestSendNeedsReview(t *testing.T) {
+	to := types.Address{2}.String()
+	sends := map[string]func(c *AlgoClient) (string, error){
+		"SendAlgos":   func(c *AlgoClient) (string, error) { return c.SendAlgos(to, 1000, nil) },
+		"SendAsset":   func(c *AlgoClient) (string, error) { return c.SendAsset(to, 7, 1) },
+		"CreateAsset": func(c *AlgoClient) (string, error) { return c.CreateAsset(NewAsset{Total: 1, UnitName: "T", Name: "Test"}) },
+	}
+	for name, send := range sends {
+		sent := 0
+		c := fakeAlgod(t, &sent)
+		if _, err := send(c); err == nil || !strings.Contains(err.Error(), "no review step") {
+			t.Errorf("%s without a review step: error = %v", name, err)
+		}
+
+		c.SetConfirm(func([]types.Transaction) error { return errors.New("refusing to sign: transaction was not reviewed") })
+		if _, err := send(c); err == nil || !Rejected(err) {
+			t.Errorf("%s not approved: error = %v, want one saying nothing was sent", name, err)
+		}
+		if sent != 0 {
+			t.Errorf("%s: %d transactions sent without approval", name, sent)
+		}
+
+		var reviewed []types.Transaction
+		c.SetConfirm(func(txns []types.Transaction) error { reviewed = txns; return nil })
+		if _, err := send(c); err != nil {
+			t.Errorf("%s approved: unexpected error: %v", name, err)
+		}
+		if sent != 1 || len(reviewed) != 1 {
+			t.Errorf("%s approved: sent %d, reviewed %d, want 1 and 1", name, sent, len(reviewed))
+		}
+	}
+}
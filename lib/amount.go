@@ -0,0 +1,153 @@
+package algo
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// AlgoDecimals sono i decimali di ALGO: 1 ALGO = 1.000.000 µAlgos
+const AlgoDecimals = 6
+
+// Amount e' una quantita' esatta in unita' base: microAlgos, oppure le
+// unita' base di un asset. Niente float: ogni conversione e' intera.
+type Amount uint64
+
+// ErrMax indica che e' stato chiesto "max": il valore dipende dal saldo
+// e lo calcola chi conosce l'account (vedi MaxSpendable).
+var ErrMax = errors.New("max amount requested")
+
+// Unita' accettate per gli ALGO interi e per i microAlgos
+var (
+	algoUnits  = []string{"algo", "algos", "a"}
+	microUnits = []string{"µ", "μ", "u", "µalgo", "µalgos", "μalgo", "μalgos", "ualgo", "ualgos", "microalgo", "microalgos"}
+)
+
+// ParseDecimal converte un numero decimale ("1.5", "1_000") in unita'
+// base con i decimali indicati. Rifiuta piu' cifre decimali di quante
+// l'unita' ne ammetta e i valori oltre uint64.
+func ParseDecimal(s string, decimals uint32) (Amount, error) {
+	s = strings.ReplaceAll(strings.TrimSpace(s), "_", "")
+	if s == "" {
+		return 0, errors.New("empty amount")
+	}
+	whole, frac, hasDot := strings.Cut(s, ".")
+	if whole == "" && frac == "" {
+		return 0, fmt.Errorf("%q is not a number", s)
+	}
+	for _, part := range []string{whole, frac} {
+		for _, r := range part {
+			if r < '0' || r > '9' {
+				return 0, fmt.Errorf("%q is not a number", s)
+			}
+		}
+	}
+	if hasDot && uint32(len(frac)) > decimals {
+		if decimals == 0 {
+			return 0, fmt.Errorf("%q: no decimals allowed", s)
+		}
+		return 0, fmt.Errorf("%q: at most %d decimals", s, decimals)
+	}
+	digits := whole + frac + strings.Repeat("0", int(decimals)-len(frac))
+	digits = strings.TrimLeft(digits, "0")
+	if digits == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseUint(digits, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is too large", s)
+	}
+	return Amount(n), nil
+}
+
+// splitUnit separa il numero dall'unita' ("1.5 ALGO" -> "1.5", "algo").
+func splitUnit(s string) (number, unit string) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.' && r != '_'
+	})
+	if i < 0 {
+		return s, ""
+	}
+	return strings.TrimSpace(s[:i]), strings.ToLower(strings.TrimSpace(s[i:]))
+}
+
+func hasUnit(units []string, unit string) bool {
+	for _, u := range units {
+		if u == unit {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseAlgos legge una quantita' di ALGO in microAlgos: "1.5 ALGO",
+// "1500000 µ" oppure "max" (ErrMax). L'unita' e' obbligatoria: uno zero
+// di troppo tra ALGO e µAlgos costa caro.
+func ParseAlgos(s string) (Amount, error) {
+	if strings.EqualFold(strings.TrimSpace(s), "max") {
+		return 0, ErrMax
+	}
+	number, unit := splitUnit(s)
+	switch {
+	case unit == "":
+		return 0, fmt.Errorf("add a unit: %s ALGO or %s µAlgo", number, number)
+	case hasUnit(algoUnits, unit):
+		return ParseDecimal(number, AlgoDecimals)
+	case hasUnit(microUnits, unit):
+		return ParseDecimal(number, 0)
+	}
+	return 0, fmt.Errorf("unknown unit %q, use ALGO or µAlgo", unit)
+}
+
+// ParseAssetAmount legge una quantita' di un asset con i suoi decimali:
+// "2.5" o "2.5 USDC" sono unita' intere, "250 base" unita' base, "max"
+// restituisce ErrMax.
+func ParseAssetAmount(s string, decimals uint32, unitName string) (Amount, error) {
+	if strings.EqualFold(strings.TrimSpace(s), "max") {
+		return 0, ErrMax
+	}
+	number, unit := splitUnit(s)
+	switch {
+	case unit == "" || (unitName != "" && unit == strings.ToLower(unitName)):
+		return ParseDecimal(number, decimals)
+	case unit == "base":
+		return ParseDecimal(number, 0)
+	}
+	if unitName == "" {
+		return 0, fmt.Errorf("unknown unit %q, use whole units or base", unit)
+	}
+	return 0, fmt.Errorf("unknown unit %q, use %s or base", unit, unitName)
+}
+
+// MaxSpendable e' quanto un account puo' inviare restando sopra il
+// saldo minimo dopo aver pagato la fee.
+func MaxSpendable(balance, minBalance, fee uint64) (Amount, error) {
+	if balance < minBalance || balance-minBalance < fee {
+		return 0, fmt.Errorf("balance %s does not cover min balance %s and fee %s",
+			Amount(balance).Algos(), Amount(minBalance).Algos(), Amount(fee).Algos())
+	}
+	return Amount(balance - minBalance - fee), nil
+}
+
+// Decimal scrive la quantita' con i decimali indicati, senza zeri
+// finali inutili ("1.5", "2", "0.000001").
+func (a Amount) Decimal(decimals uint32) string {
+	digits := strconv.FormatUint(uint64(a), 10)
+	if decimals == 0 {
+		return digits
+	}
+	if pad := int(decimals) + 1 - len(digits); pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+	cut := len(digits) - int(decimals)
+	frac := strings.TrimRight(digits[cut:], "0")
+	if frac == "" {
+		return digits[:cut]
+	}
+	return digits[:cut] + "." + frac
+}
+
+// Algos scrive una quantita' di microAlgos in ALGO ("1.5 ALGO").
+func (a Amount) Algos() string { return a.Decimal(AlgoDecimals) + " ALGO" }
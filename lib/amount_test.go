@@ -0,0 +1,160 @@
+package algo
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		in       string
+		decimals uint32
+		want     Amount
+		wantErr  bool
+	}{
+		{"1.5", 6, 1500000, false},
+		{"1_000", 0, 1000, false},
+		{" 42 ", 0, 42, false},
+		{"0", 0, 0, false},
+		{"0.000000", 6, 0, false},
+		{"1.", 6, 1000000, false},
+		{".5", 6, 500000, false},
+		{"0.000001", 6, 1, false},
+		{"18446744073709551615", 0, 18446744073709551615, false},
+		{"18446744073709.551615", 6, 18446744073709551615, false},
+
+		// zero decimals
+		{"1.5", 0, 0, true},
+		{"1.0", 0, 0, true},
+		// more decimals than the unit has
+		{"1.1234567", 6, 0, true},
+		// overflow
+		{"18446744073709551616", 0, 0, true},
+		{"18446744073709.551616", 6, 0, true},
+		{"99999999999999999999999", 2, 0, true},
+
+		{"", 6, 0, true},
+		{".", 6, 0, true},
+		{"-1", 6, 0, true},
+		{"1e3", 0, 0, true},
+		{"1.2.3", 6, 0, true},
+		{"max", 6, 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDecimal(tt.in, tt.decimals)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseDecimal(%q, %d) error = %v, wantErr %v", tt.in, tt.decimals, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseDecimal(%q, %d) = %d, want %d", tt.in, tt.decimals, got, tt.want)
+		}
+	}
+}
+
+func TestParseAlgos(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Amount
+		wantErr error // nil, ErrMax, or errAny for any other error
+	}{
+		{"1.5 ALGO", 1500000, nil},
+		{"1.5ALGO", 1500000, nil},
+		{"2 algos", 2000000, nil},
+		{"3 a", 3000000, nil},
+		{"1500000 µ", 1500000, nil},
+		{"1500000 μAlgos", 1500000, nil},
+		{"7 microalgos", 7, nil},
+		{"0 ALGO", 0, nil},
+		{"max", 0, ErrMax},
+		{" MAX ", 0, ErrMax},
+
+		{"1.5", 0, errAny},
+		{"1 btc", 0, errAny},
+		{"0.0000001 ALGO", 0, errAny},
+		{"1.5 µ", 0, errAny},
+		{"18446744073710 ALGO", 0, errAny},
+	}
+	for _, tt := range tests {
+		got, err := ParseAlgos(tt.in)
+		switch {
+		case tt.wantErr == nil && err != nil:
+			t.Errorf("ParseAlgos(%q) unexpected error: %v", tt.in, err)
+		case tt.wantErr == ErrMax && !errors.Is(err, ErrMax):
+			t.Errorf("ParseAlgos(%q) error = %v, want ErrMax", tt.in, err)
+		case tt.wantErr == errAny && (err == nil || errors.Is(err, ErrMax)):
+			t.Errorf("ParseAlgos(%q) = %d, want an error", tt.in, got)
+		case err == nil && got != tt.want:
+			t.Errorf("ParseAlgos(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseAssetAmount(t *testing.T) {
+	tests := []struct {
+		in       string
+		decimals uint32
+		unit     string
+		want     Amount
+		wantErr  bool
+	}{
+		{"2.5", 2, "USDC", 250, false},
+		{"2.5 usdc", 2, "USDC", 250, false},
+		{"250 base", 2, "USDC", 250, false},
+		{"1", 0, "", 1, false},
+		{"1.5", 0, "NFT", 0, true},
+		{"1 xyz", 2, "USDC", 0, true},
+		{"1 xyz", 2, "", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseAssetAmount(tt.in, tt.decimals, tt.unit)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseAssetAmount(%q, %d, %q) error = %v, wantErr %v", tt.in, tt.decimals, tt.unit, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseAssetAmount(%q, %d, %q) = %d, want %d", tt.in, tt.decimals, tt.unit, got, tt.want)
+		}
+	}
+	if _, err := ParseAssetAmount("max", 2, "USDC"); !errors.Is(err, ErrMax) {
+		t.Errorf("ParseAssetAmount(max) error = %v, want ErrMax", err)
+	}
+}
+
+func TestAmountDecimal(t *testing.T) {
+	tests := []struct {
+		a        Amount
+		decimals uint32
+		want     string
+	}{
+		{1500000, 6, "1.5"},
+		{2000000, 6, "2"},
+		{1, 6, "0.000001"},
+		{0, 6, "0"},
+		{5, 0, "5"},
+		{18446744073709551615, 6, "18446744073709.551615"},
+	}
+	for _, tt := range tests {
+		if got := tt.a.Decimal(tt.decimals); got != tt.want {
+			t.Errorf("Amount(%d).Decimal(%d) = %q, want %q", tt.a, tt.decimals, got, tt.want)
+		}
+		// what is shown must read back as the same amount
+		if back, err := ParseDecimal(tt.want, tt.decimals); err != nil || back != tt.a {
+			t.Errorf("ParseDecimal(%q, %d) = %d, %v, want %d", tt.want, tt.decimals, back, err, tt.a)
+		}
+	}
+}
+
+func TestMaxSpendable(t *testing.T) {
+	if got, err := MaxSpendable(1000000, 100000, 1000); err != nil || got != 899000 {
+		t.Errorf("MaxSpendable = %d, %v, want 899000", got, err)
+	}
+	if _, err := MaxSpendable(100500, 100000, 1000); err == nil {
+		t.Error("MaxSpendable below min balance + fee: want an error")
+	}
+	if _, err := MaxSpendable(50000, 100000, 1000); err == nil {
+		t.Error("MaxSpendable below min balance: want an error")
+	}
+}
+
+var errAny = errors.New("any error")
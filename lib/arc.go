@@ -0,0 +1,158 @@
+package algo
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// ARC3Suffix segnala nell'URL di un asset che punta a metadati ARC-3
+const ARC3Suffix = "#arc3"
+
+// ARC3Metadata sono i campi di un JSON ARC-3 che l'asset deve rispecchiare,
+// con l'hash da mettere nel campo metadata hash.
+type ARC3Metadata struct {
+	Name     string  `json:"name"`
+	UnitName string  `json:"unitName"`
+	Decimals *uint32 `json:"decimals"`
+	Image    string  `json:"image"`
+	// ExtraMetadata e' in base64, entra nell'hash se presente
+	ExtraMetadata string `json:"extra_metadata"`
+
+	Hash [32]byte `json:"-"`
+}
+
+// ParseARC3 legge un JSON di metadati ARC-3 e ne calcola l'hash: lo
+// SHA-256 del file, oppure, con extra_metadata, lo SHA-512/256 di
+// "arc0003/am" seguito dall'hash del JSON e dai byte extra.
+func ParseARC3(data []byte) (ARC3Metadata, error) {
+	var m ARC3Metadata
+	if err := json.Unmarshal(data, &m); err != nil {
+		return m, fmt.Errorf("invalid metadata JSON: %w", err)
+	}
+	if m.ExtraMetadata == "" {
+		m.Hash = sha256.Sum256(data)
+		return m, nil
+	}
+	extra, err := base64.StdEncoding.DecodeString(m.ExtraMetadata)
+	if err != nil {
+		return m, fmt.Errorf("extra_metadata is not base64: %w", err)
+	}
+	amj := sha512.Sum512_256(append([]byte("arc0003/amj"), data...))
+	am := append([]byte("arc0003/am"), amj[:]...)
+	m.Hash = sha512.Sum512_256(append(am, extra...))
+	return m, nil
+}
+
+// ARC19 e' un CID IPFS tradotto per ARC-19: l'URL template da mettere
+// nell'asset e l'indirizzo reserve che ne contiene il digest. Aggiornare
+// la reserve aggiorna i metadati senza toccare l'URL.
+type ARC19 struct {
+	URL     string
+	Reserve string
+}
+
+// codec multiformat accettati da ARC-19
+var cidCodecs = map[uint64]string{0x55: "raw", 0x70: "dag-pb"}
+
+// ParseARC19 decodifica un CID v0 ("Qm...") o v1 in base32 ("b...") con
+// hash sha2-256.
+func ParseARC19(cid string) (ARC19, error) {
+	cid = strings.TrimPrefix(strings.TrimSpace(cid), "ipfs://")
+	var version, codec uint64
+	var mh []byte
+	switch {
+	case strings.HasPrefix(cid, "Qm"):
+		b, err := decodeBase58(cid)
+		if err != nil {
+			return ARC19{}, err
+		}
+		version, codec, mh = 0, 0x70, b
+	case strings.HasPrefix(cid, "b"):
+		b, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.ToUpper(cid[1:]))
+		if err != nil {
+			return ARC19{}, errors.New("CID v1 is not valid base32")
+		}
+		var n, m int
+		version, n = binary.Uvarint(b)
+		if n <= 0 || version != 1 {
+			return ARC19{}, errors.New("CID is not version 1")
+		}
+		codec, m = binary.Uvarint(b[n:])
+		if m <= 0 {
+			return ARC19{}, errors.New("CID has no codec")
+		}
+		mh = b[n+m:]
+	default:
+		return ARC19{}, errors.New("CID must be v0 (Qm...) or base32 v1 (b...)")
+	}
+	name, ok := cidCodecs[codec]
+	if !ok {
+		return ARC19{}, fmt.Errorf("CID codec 0x%x is not raw or dag-pb", codec)
+	}
+	if len(mh) != 34 || mh[0] != 0x12 || mh[1] != 0x20 {
+		return ARC19{}, errors.New("CID hash must be sha2-256")
+	}
+	var addr types.Address
+	copy(addr[:], mh[2:])
+	return ARC19{
+		URL:     fmt.Sprintf("template-ipfs://{ipfscid:%d:%s:reserve:sha2-256}", version, name),
+		Reserve: addr.String(),
+	}, nil
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// decodeBase58 decodifica l'alfabeto base58 di Bitcoin, usato dai CID v0
+func decodeBase58(s string) ([]byte, error) {
+	n := new(big.Int)
+	radix := big.NewInt(58)
+	for _, r := range s {
+		i := strings.IndexRune(base58Alphabet, r)
+		if i < 0 {
+			return nil, fmt.Errorf("CID has invalid base58 character %q", r)
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(i)))
+	}
+	b := n.Bytes()
+	for _, r := range s {
+		if r != '1' {
+			break
+		}
+		b = append([]byte{0}, b...)
+	}
+	return b, nil
+}
+
+// ARC69Note compatta un JSON ARC-69 per la nota della creazione: deve
+// dichiarare "standard": "arc69" e stare nel limite della nota.
+func ARC69Note(data []byte) ([]byte, error) {
+	var m struct {
+		Standard string `json:"standard"`
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("invalid metadata JSON: %w", err)
+	}
+	if m.Standard != "arc69" {
+		return nil, errors.New(`ARC-69 metadata must have "standard": "arc69"`)
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return nil, fmt.Errorf("invalid metadata JSON: %w", err)
+	}
+	if buf.Len() > MaxNoteBytes {
+		return nil, fmt.Errorf("ARC-69 metadata is %d bytes, at most %d", buf.Len(), MaxNoteBytes)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,109 @@
+package algo
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// Gli hash attesi sono calcolati a parte (sha256sum e SHA-512/256 di
+// Python) seguendo ARC-3, non con il codice sotto test.
+func TestParseARC3(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		hash string
+	}{
+		{
+			"plain file hash",
+			`{"name":"My NFT","decimals":0,"image":"ipfs://QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG"}`,
+			"7d2300dbca53d6155eeee8e9aa783ad2991904f2d3a5e9352ae31f90c5e48edd",
+		},
+		{
+			"extra_metadata",
+			`{"name":"My NFT","extra_metadata":"bGF6eWNoYWlu"}`,
+			"eb2baa01a6391fca2d77198e51148217b2f400eb57b3db75ac43e97d664188fa",
+		},
+	}
+	for _, tt := range tests {
+		m, err := ParseARC3([]byte(tt.json))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if got := hex.EncodeToString(m.Hash[:]); got != tt.hash {
+			t.Errorf("%s: hash = %s, want %s", tt.name, got, tt.hash)
+		}
+		if m.Name != "My NFT" {
+			t.Errorf("%s: name = %q", tt.name, m.Name)
+		}
+	}
+
+	for _, bad := range []string{`{`, `{"extra_metadata":"%%"}`} {
+		if _, err := ParseARC3([]byte(bad)); err == nil {
+			t.Errorf("ParseARC3(%s): want an error", bad)
+		}
+	}
+}
+
+// Gli indirizzi reserve attesi sono il digest sha2-256 del CID con il
+// checksum degli indirizzi Algorand, calcolati a parte.
+func TestParseARC19(t *testing.T) {
+	tests := []struct {
+		cid     string
+		url     string
+		reserve string
+	}{
+		{
+			"QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG",
+			"template-ipfs://{ipfscid:0:dag-pb:reserve:sha2-256}",
+			"TVWCXZIPOBUVGR42XHPSZY7NZKILNACTYAFTABFX6CWMXYPI53PZ7JBOHE",
+		},
+		{
+			"ipfs://QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG",
+			"template-ipfs://{ipfscid:0:dag-pb:reserve:sha2-256}",
+			"TVWCXZIPOBUVGR42XHPSZY7NZKILNACTYAFTABFX6CWMXYPI53PZ7JBOHE",
+		},
+		{
+			"bafybeie5gq4jxvzmsym6hjlwxej4rwdoxt7wadqvmmwbqi7r27fclha2va",
+			"template-ipfs://{ipfscid:1:dag-pb:reserve:sha2-256}",
+			"TU2DRG6XFSLBTY5FO24RHSGYN26P6YAOCVRSYGBD6HL4UJM4DKUBIJ63DY",
+		},
+		{
+			"bafkreibme22gw2h7y2h7tg2fhqotaqjucnbc24deqo72b6mkl2egezxhvy",
+			"template-ipfs://{ipfscid:1:raw:reserve:sha2-256}",
+			"FQTLI23I77DI76M3IU6B2MCBGQJUELLQMSB37IHZRJPIQYTG46XE5FTWXQ",
+		},
+	}
+	for _, tt := range tests {
+		got, err := ParseARC19(tt.cid)
+		if err != nil {
+			t.Errorf("ParseARC19(%q) unexpected error: %v", tt.cid, err)
+			continue
+		}
+		if got.URL != tt.url || got.Reserve != tt.reserve {
+			t.Errorf("ParseARC19(%q) = %+v, want {URL:%s Reserve:%s}", tt.cid, got, tt.url, tt.reserve)
+		}
+	}
+
+	for _, bad := range []string{
+		"",
+		"zdj7W", // base58btc v1, not supported
+		"Qm0OIl",
+		"bafy!!",
+		"bafkqaaa", // identity hash
+	} {
+		if got, err := ParseARC19(bad); err == nil {
+			t.Errorf("ParseARC19(%q) = %+v, want an error", bad, got)
+		}
+	}
+}
+
+func TestARC69Note(t *testing.T) {
+	note, err := ARC69Note([]byte("{\n  \"standard\": \"arc69\",\n  \"description\": \"x\"\n}"))
+	if err != nil || string(note) != `{"standard":"arc69","description":"x"}` {
+		t.Errorf("ARC69Note = %q, %v", note, err)
+	}
+	if _, err := ARC69Note([]byte(`{"standard":"arc3"}`)); err == nil {
+		t.Error("ARC69Note without arc69 standard: want an error")
+	}
+}
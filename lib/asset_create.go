@@ -0,0 +1,68 @@
+package algo
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/algorand/go-algorand-sdk/v2/transaction"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// NewAsset descrive un asset da creare. Total e' in unita' base; un ruolo
+// vuoto non viene assegnato e non potra' piu' esserlo.
+type NewAsset struct {
+	Name          string
+	UnitName      string
+	URL           string
+	Total         uint64
+	Decimals      uint32
+	DefaultFrozen bool
+	// MetadataHash e' vuoto oppure di 32 byte
+	MetadataHash []byte
+
+	Manager  string
+	Reserve  string
+	Freeze   string
+	Clawback string
+
+	Note []byte
+}
+
+// Check controlla i limiti del protocollo prima di costruire la
+// transazione, con messaggi leggibili.
+func (a NewAsset) Check() error {
+	switch {
+	case a.Total == 0:
+		return errors.New("total supply must be more than 0")
+	case a.Decimals > types.AssetMaxNumberOfDecimals:
+		return fmt.Errorf("at most %d decimals", types.AssetMaxNumberOfDecimals)
+	case len(a.Name) > types.AssetNameMaxLen:
+		return fmt.Errorf("name is %d bytes, at most %d", len(a.Name), types.AssetNameMaxLen)
+	case len(a.UnitName) > types.AssetUnitNameMaxLen:
+		return fmt.Errorf("unit name is %d bytes, at most %d", len(a.UnitName), types.AssetUnitNameMaxLen)
+	case len(a.URL) > types.AssetURLMaxLen:
+		return fmt.Errorf("URL is %d bytes, at most %d", len(a.URL), types.AssetURLMaxLen)
+	case len(a.MetadataHash) != 0 && len(a.MetadataHash) != types.AssetMetadataHashLen:
+		return fmt.Errorf("metadata hash must be %d bytes", types.AssetMetadataHashLen)
+	case len(a.Note) > MaxNoteBytes:
+		return fmt.Errorf("note is %d bytes, at most %d", len(a.Note), MaxNoteBytes)
+	}
+	for _, role := range []string{a.Manager, a.Reserve, a.Freeze, a.Clawback} {
+		if role == "" {
+			continue
+		}
+		if _, err := types.DecodeAddress(role); err != nil {
+			return fmt.Errorf("role %q is not a valid address", role)
+		}
+	}
+	return nil
+}
+
+// Transaction costruisce la creazione dell'asset inviata da creator.
+func (a NewAsset) Transaction(creator string, sp types.SuggestedParams) (types.Transaction, error) {
+	if err := a.Check(); err != nil {
+		return types.Transaction{}, err
+	}
+	return transaction.MakeAssetCreateTxn(creator, a.Note, sp, a.Total, a.Decimals, a.DefaultFrozen,
+		a.Manager, a.Reserve, a.Freeze, a.Clawback, a.UnitName, a.Name, a.URL, string(a.MetadataHash))
+}
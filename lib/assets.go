@@ -0,0 +1,74 @@
+package algo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// AssetMinBalance e' la parte di saldo minimo bloccata da ogni opt-in
+const AssetMinBalance = 100_000
+
+// assetLookups limita le letture di asset in parallelo
+const assetLookups = 8
+
+// Holding e' un asset a cui un account ha fatto opt-in, con quanto serve
+// per decidere se uscirne. Deleted indica un asset distrutto, di cui non
+// si conosce piu' il creatore. Created indica un asset creato dall'account
+// stesso, da cui non si puo' uscire finche' esiste.
+type Holding struct {
+	AssetID  uint64
+	Amount   Amount
+	Frozen   bool
+	Name     string
+	UnitName string
+	Decimals uint32
+	Creator  string
+	Created  bool
+	Deleted  bool
+}
+
+// Holdings elenca gli opt-in di address in ordine di ID, con nome,
+// decimali e creatore di ogni asset.
+func (c *AlgoClient) Holdings(address string) ([]Holding, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	info, err := c.algod.AccountInformation(address).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	holdings := make([]Holding, len(info.Assets))
+	errs := make([]error, len(info.Assets))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, assetLookups)
+	for i, a := range info.Assets {
+		holdings[i] = Holding{AssetID: a.AssetId, Amount: Amount(a.Amount), Frozen: a.IsFrozen}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(h *Holding, err *error) {
+			defer func() { <-sem; wg.Done() }()
+			asset, e := c.algod.GetAssetByID(h.AssetID).Do(ctx)
+			switch {
+			case notFound(e):
+				h.Deleted = true
+			case e != nil:
+				*err = fmt.Errorf("asset %d: %w", h.AssetID, e)
+			default:
+				p := asset.Params
+				h.Name, h.UnitName, h.Decimals, h.Creator = p.Name, p.UnitName, uint32(p.Decimals), p.Creator
+				h.Created = p.Creator == address
+			}
+		}(&holdings[i], &errs[i])
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(holdings, func(i, j int) bool { return holdings[i].AssetID < holdings[j].AssetID })
+	return holdings, nil
+}
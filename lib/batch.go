@@ -0,0 +1,144 @@
+package algo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// MaxGroupSize e' il numero massimo di transazioni in un gruppo atomico
+const MaxGroupSize = 16
+
+// MinAccountBalance e' il saldo minimo di un account senza asset ne' app:
+// un pagamento minore verso un account vuoto viene rifiutato
+const MinAccountBalance = 100_000
+
+// ErrNotFound indica che il nodo non conosce l'oggetto richiesto
+var ErrNotFound = errors.New("not found")
+
+// notFound riconosce i 404 del client, che non hanno un tipo proprio
+func notFound(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "HTTP 404")
+}
+
+// submitError e' l'errore del nodo all'invio di transazioni gia' firmate.
+type submitError struct{ error }
+
+func (e submitError) Unwrap() error { return e.error }
+
+// Rejected dice se un errore di SignAndSend esclude che le transazioni
+// siano in rete: non sono partite, o il nodo le ha rifiutate con un 4xx.
+// Un timeout, una connessione caduta o un 5xx lasciano il dubbio, il nodo
+// puo' averle accettate; "already in ledger" vuol dire che ci sono gia'.
+func Rejected(err error) bool {
+	if err == nil {
+		return false
+	}
+	var se submitError
+	if !errors.As(err, &se) {
+		return true
+	}
+	msg := se.error.Error()
+	return strings.HasPrefix(msg, "HTTP 4") && !strings.Contains(msg, "already in ledger")
+}
+
+// AssignGroup unisce txns in un gruppo atomico: gli ID delle transazioni
+// cambiano, ed e' con questi che vanno registrate prima dell'invio.
+func AssignGroup(txns []types.Transaction) error {
+	gid, err := crypto.ComputeGroupID(txns)
+	if err != nil {
+		return err
+	}
+	for i := range txns {
+		txns[i].Group = gid
+	}
+	return nil
+}
+
+// AccountState e' quanto serve per validare un pagamento da o verso un
+// account, in microAlgos.
+type AccountState struct {
+	Balance    uint64
+	MinBalance uint64
+}
+
+// AccountState legge saldo e saldo minimo di address, senza asset e app.
+func (c *AlgoClient) AccountState(address string) (AccountState, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	info, err := c.algod.AccountInformation(address).Exclude("all").Do(ctx)
+	if err != nil {
+		return AccountState{}, err
+	}
+	return AccountState{Balance: info.Amount, MinBalance: info.MinBalance}, nil
+}
+
+// OptedIn dice se address puo' ricevere l'asset.
+func (c *AlgoClient) OptedIn(address string, assetID uint64) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := c.algod.AccountAssetInformation(address, assetID).Do(ctx)
+	if notFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// ConfirmedRound cerca una transazione gia' inviata: prima nel pool del
+// nodo, che conosce quelle recenti, poi nell'indexer. ErrNotFound se
+// nessuno dei due la conosce.
+func (c *AlgoClient) ConfirmedRound(txID string) (uint64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	res, _, err := c.algod.PendingTransactionInformation(txID).Do(ctx)
+	switch {
+	case err == nil && res.ConfirmedRound > 0:
+		return res.ConfirmedRound, nil
+	case err == nil && res.PoolError != "":
+		return 0, fmt.Errorf("rejected: %s", res.PoolError)
+	case err == nil:
+		return 0, nil // ancora in attesa
+	case !notFound(err):
+		return 0, err
+	}
+	if c.indexer == nil {
+		return 0, ErrNotFound
+	}
+	found, err := c.indexer.LookupTransaction(txID).Do(ctx)
+	if notFound(err) {
+		return 0, ErrNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	return found.Transaction.ConfirmedRound, nil
+}
+
+// KnownRound e' l'ultimo round fino al quale ConfirmedRound sa rispondere:
+// quello del nodo, o quello dell'indexer se e' rimasto indietro. Una
+// transazione non trovata con LastValid sotto questo round e' scaduta.
+func (c *AlgoClient) KnownRound() (uint64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	status, err := c.algod.Status().Do(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if c.indexer == nil {
+		return status.LastRound, nil
+	}
+	health, err := c.indexer.HealthCheck().Do(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("indexer: %w", err)
+	}
+	return min(status.LastRound, health.Round), nil
+}
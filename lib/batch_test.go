@@ -0,0 +1,67 @@
+package algo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/transaction"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+func TestAssignGroup(t *testing.T) {
+	sender := types.Address{1}.String()
+	sp := types.SuggestedParams{Fee: 1000, FlatFee: true, FirstRoundValid: 1, LastRoundValid: 1000,
+		GenesisID: "testnet-v1.0", GenesisHash: make([]byte, 32)}
+	var txns []types.Transaction
+	for i := uint64(1); i <= 3; i++ {
+		txn, err := transaction.MakePaymentTxn(sender, types.Address{2}.String(), i, nil, "", sp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		txns = append(txns, txn)
+	}
+	want, err := crypto.ComputeGroupID(txns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := crypto.GetTxID(txns[0])
+
+	if err := AssignGroup(txns); err != nil {
+		t.Fatalf("AssignGroup: %v", err)
+	}
+	for i, txn := range txns {
+		if txn.Group != want {
+			t.Errorf("txn %d group = %x, want %x", i, txn.Group, want)
+		}
+	}
+	if crypto.GetTxID(txns[0]) == before {
+		t.Error("the txID must change with the group")
+	}
+	// a group already set cannot be computed again
+	if err := AssignGroup(txns); err == nil {
+		t.Error("AssignGroup on grouped txns: want an error")
+	}
+}
+
+func TestRejected(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{errors.New("refusing to sign: transaction not approved"), true},
+		{submitError{errors.New("HTTP 400: TransactionPool.Remember: transaction rejected")}, true},
+		{submitError{errors.New("HTTP 401: invalid token")}, true},
+		{submitError{errors.New("HTTP 400: transaction already in ledger: TXID")}, false},
+		{submitError{errors.New("HTTP 500: internal error")}, false},
+		{submitError{context.DeadlineExceeded}, false},
+		{submitError{errors.New("connection reset by peer")}, false},
+	}
+	for _, tt := range tests {
+		if got := Rejected(tt.err); got != tt.want {
+			t.Errorf("Rejected(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
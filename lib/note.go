@@ -0,0 +1,230 @@
+package algo
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
+)
+
+// MaxNoteBytes e' il limite del campo note di una transazione
+const MaxNoteBytes = 1024
+
+// Formati di inserimento di una nota
+const (
+	NoteText   = "text"
+	NoteHex    = "hex"
+	NoteBase64 = "base64"
+	NoteJSON   = "json"
+	NoteARC2   = "arc2"
+)
+
+// NoteFormats elenca i formati accettati da EncodeNote, il primo e' il
+// predefinito.
+var NoteFormats = []string{NoteText, NoteHex, NoteBase64, NoteJSON, NoteARC2}
+
+// arc2Prefix riconosce "<dapp-name>:<formato>" come da ARC-2
+var arc2Prefix = regexp.MustCompile(`^([a-zA-Z0-9][a-zA-Z0-9_/@.-]{4,31}):([mjbu])`)
+
+// EncodeNote converte quanto inserito nel formato indicato nei byte della
+// nota, controllando il limite di 1KB. Per ARC-2 l'input e'
+// "dapp:<m|j|b|u><dati>": j e u sono testo, m e' JSON convertito in
+// MsgPack, b sono byte in base64.
+func EncodeNote(format, input string) ([]byte, error) {
+	var note []byte
+	switch format {
+	case NoteText, "":
+		note = []byte(input)
+	case NoteHex:
+		b, err := hex.DecodeString(strings.Join(strings.Fields(strings.TrimPrefix(input, "0x")), ""))
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex: %w", err)
+		}
+		note = b
+	case NoteBase64:
+		b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(input))
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64: %w", err)
+		}
+		note = b
+	case NoteJSON:
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, []byte(input)); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		note = buf.Bytes()
+	case NoteARC2:
+		b, err := encodeARC2(input)
+		if err != nil {
+			return nil, err
+		}
+		note = b
+	default:
+		return nil, fmt.Errorf("unknown note format %q", format)
+	}
+	if len(note) > MaxNoteBytes {
+		return nil, fmt.Errorf("note is %d bytes, at most %d", len(note), MaxNoteBytes)
+	}
+	return note, nil
+}
+
+func encodeARC2(input string) ([]byte, error) {
+	m := arc2Prefix.FindStringSubmatch(input)
+	if m == nil {
+		return nil, fmt.Errorf("ARC-2 notes are dapp:<m|j|b|u>data, dapp being 5-32 letters, digits or _/@.-")
+	}
+	prefix, data := input[:len(m[0])], input[len(m[0]):]
+	switch m[2] {
+	case "j":
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, []byte(data)); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		return append([]byte(prefix), buf.Bytes()...), nil
+	case "m":
+		var v interface{}
+		dec := json.NewDecoder(strings.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(&v); err != nil {
+			return nil, fmt.Errorf("msgpack data is entered as JSON: %w", err)
+		}
+		return append([]byte(prefix), msgpack.Encode(integers(v))...), nil
+	case "b":
+		b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(data))
+		if err != nil {
+			return nil, fmt.Errorf("bytes are entered as base64: %w", err)
+		}
+		return append([]byte(prefix), b...), nil
+	}
+	return []byte(input), nil
+}
+
+// integers mantiene interi i numeri interi del JSON, che altrimenti in
+// MsgPack diventerebbero float64
+func integers(v interface{}) interface{} {
+	switch t := v.(type) {
+	case json.Number:
+		if n, err := t.Int64(); err == nil {
+			return n
+		}
+		f, _ := t.Float64()
+		return f
+	case map[string]interface{}:
+		for k, val := range t {
+			t[k] = integers(val)
+		}
+	case []interface{}:
+		for i := range t {
+			t[i] = integers(t[i])
+		}
+	}
+	return v
+}
+
+// DecodedNote e' una nota resa leggibile: Format dice come e' stata
+// interpretata ("text", "json", "arc2 dapp/j", "msgpack", "binary").
+type DecodedNote struct {
+	Format string
+	Text   string
+}
+
+// DecodeNote riconosce il formato di una nota: ARC-2 (anche MsgPack),
+// JSON, testo, altrimenti la mostra in base64 con l'hex se e' corta. Il
+// prefisso ARC-2 vale solo se i dati sono nel formato che dichiara:
+// "Invoice:june" e' testo, non JSON.
+func DecodeNote(note []byte) DecodedNote {
+	if len(note) == 0 {
+		return DecodedNote{Format: NoteText}
+	}
+	if m := arc2Prefix.FindSubmatch(note); m != nil && arc2Valid(note[len(m[0]):], string(m[2])) {
+		d := decodeBody(note[len(m[0]):], string(m[2]))
+		d.Format = fmt.Sprintf("arc2 %s/%s", m[1], m[2])
+		return d
+	}
+	return decodeBody(note, "")
+}
+
+// arc2Valid controlla che i dati di una nota ARC-2 siano nel formato f.
+func arc2Valid(b []byte, f string) bool {
+	switch f {
+	case "j":
+		return json.Valid(b)
+	case "m":
+		// un solo valore, senza byte in avanzo
+		var v interface{}
+		dec := msgpack.NewDecoder(bytes.NewReader(b))
+		return len(b) > 0 && dec.Decode(&v) == nil && dec.Decode(&v) == io.EOF
+	case "u":
+		return printable(b)
+	}
+	return true
+}
+
+// decodeBody interpreta i dati di una nota; hint e' il formato ARC-2.
+func decodeBody(b []byte, hint string) DecodedNote {
+	if hint == "j" || hint == "" {
+		if json.Valid(b) && (hint == "j" || bytes.HasPrefix(bytes.TrimSpace(b), []byte("{")) || bytes.HasPrefix(bytes.TrimSpace(b), []byte("["))) {
+			return DecodedNote{Format: NoteJSON, Text: string(b)}
+		}
+	}
+	if (hint == "u" || hint == "j" || hint == "") && printable(b) {
+		return DecodedNote{Format: NoteText, Text: string(b)}
+	}
+	if hint == "m" {
+		var v interface{}
+		if err := msgpack.Decode(b, &v); err == nil {
+			if js, err := json.Marshal(jsonable(v)); err == nil {
+				return DecodedNote{Format: "msgpack", Text: string(js)}
+			}
+		}
+	}
+	text := base64.StdEncoding.EncodeToString(b)
+	if len(b) <= 32 {
+		text += " (hex " + hex.EncodeToString(b) + ")"
+	}
+	return DecodedNote{Format: "binary", Text: text}
+}
+
+// printable: UTF-8 valido senza caratteri di controllo oltre agli a capo
+func printable(b []byte) bool {
+	if !utf8.Valid(b) {
+		return false
+	}
+	for _, r := range string(b) {
+		if unicode.IsControl(r) && r != '\n' && r != '\t' && r != '\r' {
+			return false
+		}
+	}
+	return true
+}
+
+// jsonable converte le mappe con chiavi interface{} del decoder MsgPack
+func jsonable(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for k, val := range t {
+			out[fmt.Sprint(k)] = jsonable(val)
+		}
+		return out
+	case []interface{}:
+		for i := range t {
+			t[i] = jsonable(t[i])
+		}
+		return t
+	case []byte:
+		if printable(t) {
+			return string(t)
+		}
+		return base64.StdEncoding.EncodeToString(t)
+	}
+	return v
+}
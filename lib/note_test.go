@@ -0,0 +1,85 @@
+package algo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNoteRoundTrip(t *testing.T) {
+	tests := []struct {
+		format     string
+		input      string
+		wantNote   string
+		wantFormat string
+		wantText   string
+	}{
+		{NoteText, "hello", "hello", NoteText, "hello"},
+		{"", "line\nbreak", "line\nbreak", NoteText, "line\nbreak"},
+		{NoteHex, "0x68 69", "hi", NoteText, "hi"},
+		{NoteBase64, "AAEC", "\x00\x01\x02", "binary", "AAEC (hex 000102)"},
+		{NoteJSON, `{ "a" : 1 }`, `{"a":1}`, NoteJSON, `{"a":1}`},
+		{NoteJSON, `[1, 2]`, `[1,2]`, NoteJSON, `[1,2]`},
+		{NoteARC2, `mydapp:j{ "a": 1 }`, `mydapp:j{"a":1}`, "arc2 mydapp/j", `{"a":1}`},
+		{NoteARC2, `mydapp:m{"a":1}`, "mydapp:m\x81\xa1a\x01", "arc2 mydapp/m", `{"a":1}`},
+		{NoteARC2, "my.dapp@v2:uhello", "my.dapp@v2:uhello", "arc2 my.dapp@v2/u", "hello"},
+		{NoteARC2, "mydapp:bAAE=", "mydapp:b\x00\x01", "arc2 mydapp/b", "AAE= (hex 0001)"},
+
+		// a prefix alone does not make ARC-2: the data must match
+		{NoteText, "Invoice:june", "Invoice:june", NoteText, "Invoice:june"},
+		{NoteText, "mydapp:mxyz", "mydapp:mxyz", NoteText, "mydapp:mxyz"},
+		{NoteText, "abcd:j{}", "abcd:j{}", NoteText, "abcd:j{}"},
+	}
+	for _, tt := range tests {
+		note, err := EncodeNote(tt.format, tt.input)
+		if err != nil {
+			t.Errorf("EncodeNote(%q, %q) unexpected error: %v", tt.format, tt.input, err)
+			continue
+		}
+		if string(note) != tt.wantNote {
+			t.Errorf("EncodeNote(%q, %q) = %q, want %q", tt.format, tt.input, note, tt.wantNote)
+		}
+		got := DecodeNote(note)
+		if got.Format != tt.wantFormat || got.Text != tt.wantText {
+			t.Errorf("DecodeNote(%q) = %+v, want {Format:%s Text:%s}", note, got, tt.wantFormat, tt.wantText)
+		}
+	}
+}
+
+func TestEncodeNoteErrors(t *testing.T) {
+	tests := []struct {
+		format string
+		input  string
+	}{
+		{NoteHex, "zz"},
+		{NoteBase64, "not base64!"},
+		{NoteJSON, "{"},
+		{NoteARC2, "abc:jx"}, // dapp name shorter than 5
+		{NoteARC2, "mydapp:x{}"},
+		{NoteARC2, "mydapp:j{"},
+		{NoteARC2, "mydapp:m[1,"},
+		{NoteARC2, "mydapp:b%%"},
+		{NoteText, strings.Repeat("x", MaxNoteBytes+1)},
+		{"yaml", "a: 1"},
+	}
+	for _, tt := range tests {
+		if note, err := EncodeNote(tt.format, tt.input); err == nil {
+			t.Errorf("EncodeNote(%q, %q) = %q, want an error", tt.format, tt.input, note)
+		}
+	}
+	if _, err := EncodeNote(NoteText, strings.Repeat("x", MaxNoteBytes)); err != nil {
+		t.Errorf("EncodeNote at the limit: %v", err)
+	}
+}
+
+func TestDecodeNoteBinary(t *testing.T) {
+	if got := DecodeNote(nil); got.Format != NoteText || got.Text != "" {
+		t.Errorf("DecodeNote(nil) = %+v", got)
+	}
+	long := make([]byte, 40)
+	if got := DecodeNote(long); got.Format != "binary" || strings.Contains(got.Text, "hex") {
+		t.Errorf("DecodeNote of 40 zero bytes = %+v, want base64 without hex", got)
+	}
+	if got := DecodeNote([]byte("\xff\xfe")); got.Format != "binary" {
+		t.Errorf("DecodeNote of invalid UTF-8 = %+v, want binary", got)
+	}
+}
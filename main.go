@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"lazychain/layout" // Layout package
 	. "lazychain/models"
+	"lazychain/models/appctx"
+	"lazychain/models/dashboard"
 	. "lazychain/models/goal"
 	. "lazychain/models/settings"
 
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -34,13 +37,25 @@ type MainModel struct {
 	ApplicationsModel *ApplicationsModel
 	CmdGoalsModel     *GOALModel
 	ExploreModel      *ExploreModel
+	NodeModel         *NodeModel
+	DashboardModel    *dashboard.DashboardModel
+
+	// Shared by every screen: connection, config, account, event bus
+	App *appctx.Context
+
+	// Startup flags and environment, they win over config and profile
+	overrides Overrides
 }
 
-func NewMainModel() *MainModel {
+func NewMainModel(overrides Overrides) *MainModel {
 	// Initialize with default dimensions
 	initialLayout := layout.NewLayoutContainer(80, 24)
 
-	return &MainModel{
+	settingsModel := NewSettingsModel([]string{"localnet", "testnet", "mainnet"}, overrides)
+	app := appctx.New(settingsModel)
+	cmdGoals := NewGOALModel(app)
+
+	m := &MainModel{
 		layoutContainer:   initialLayout,
 		mainLayout:        nil, // Will be initialized on first WindowSizeMsg
 		projectLayout:     nil, // Will be initialized on first WindowSizeMsg
@@ -48,19 +63,42 @@ func NewMainModel() *MainModel {
 		height:            24,
 		CurrentState:      MainView,
 		ProjectModel:      NewProjectModel(),
-		SettingsModel:     NewSettingsModel([]string{"localnet", "testnet", "mainnet"}),
-		ApplicationsModel: NewApplicationsModel(),
-		CmdGoalsModel:     NewGOALModel(),
-		ExploreModel:      NewExploreModel(),
+		SettingsModel:     settingsModel,
+		ApplicationsModel: NewApplicationsModel(app),
+		CmdGoalsModel:     cmdGoals,
+		ExploreModel:      NewExploreModel(app),
+		NodeModel:         NewNodeModel(cmdGoals.Runner()),
+		DashboardModel:    dashboard.NewDashboardModel(app),
+		App:               app,
+		overrides:         overrides,
 	}
+
+	m.configureRunner()
+	m.syncProfiles()
+	return m
 }
 
 func (m *MainModel) Init() tea.Cmd {
-	return tea.SetWindowTitle("LAZYCHAIN - your friendly TUI ≧ ﹏ ≦")
+	return tea.Batch(
+		tea.SetWindowTitle("LAZYCHAIN - your friendly TUI ≧ ﹏ ≦"),
+		// Other screens need clients, connect without waiting for Settings
+		m.SettingsModel.AutoConnect(),
+	)
 }
 
 func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := m.update(msg)
+	// Tell every screen what this message changed
+	return model, tea.Batch(cmd, m.App.Sync())
+}
+
+func (m *MainModel) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case appctx.Event:
+		if msg.Kind == appctx.NetworkConnected || msg.Kind == appctx.ProfileSwitched {
+			m.configureRunner()
+		}
+		return m, m.broadcast(msg)
 	case tea.KeyMsg:
 		switch m.CurrentState {
 		case MainView:
@@ -79,6 +117,10 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case ProjectView:
 			switch msg.String() {
 			case "esc":
+				if m.ProjectModel.IsPicking() {
+					// Close the profile switcher first
+					break
+				}
 				// Back to main view - completely reset selection state
 				m.CurrentState = MainView
 				m.ProjectModel.Selected = make(map[int]struct{})
@@ -103,6 +145,15 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 							m.CurrentState = CmdGoalsView
 						case "Explore":
 							m.CurrentState = ExploreView
+							cmd = tea.Batch(cmd, m.ExploreModel.Init())
+						case "Dashboard":
+							m.CurrentState = DashboardView
+						case "Node":
+							m.CurrentState = NodeView
+							cmd = tea.Batch(cmd, m.NodeModel.Start())
+						case "Profiles":
+							m.syncProfiles()
+							m.ProjectModel.OpenProfiles()
 						}
 						// Clear selection after state change to prevent re-triggering
 						m.ProjectModel.Selected = make(map[int]struct{})
@@ -126,6 +177,8 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if msg.String() == "esc" && !wasEditingAddr {
 				// Reset editing state when leaving settings
 				m.SettingsModel.ResetEditingState()
+				// Networks may have been edited
+				m.configureRunner()
 				m.CurrentState = ProjectView
 				return m, nil
 			}
@@ -144,8 +197,10 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, cmd
 		case CmdGoalsView:
-			switch msg.String() {
-			case "esc":
+			// ESC closes a review or prompt first, then leaves the view
+			// keeping what was typed as a draft
+			if msg.String() == "esc" && !m.CmdGoalsModel.IsEditing() {
+				m.CmdGoalsModel.SaveDraft()
 				m.CurrentState = ProjectView
 				return m, nil
 			}
@@ -156,8 +211,8 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, cmd
 		case ExploreView:
-			switch msg.String() {
-			case "esc":
+			// ESC closes the search first, then leaves the view
+			if msg.String() == "esc" && !m.ExploreModel.IsEditing() {
 				m.CurrentState = ProjectView
 				return m, nil
 			}
@@ -167,7 +222,61 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.ExploreModel = updatedExploreModel
 			}
 			return m, cmd
+		case NodeView:
+			// ESC cancels an inline input first, then leaves the view
+			if msg.String() == "esc" && !m.NodeModel.IsEditing() {
+				m.NodeModel.Stop()
+				m.rememberNode()
+				m.CurrentState = ProjectView
+				return m, nil
+			}
+			updatedModel, cmd := m.NodeModel.Update(msg)
+			if updatedNodeModel, ok := updatedModel.(*NodeModel); ok {
+				m.NodeModel = updatedNodeModel
+			}
+			return m, cmd
+		case DashboardView:
+			if msg.String() == "esc" {
+				m.CurrentState = ProjectView
+				return m, nil
+			}
+			updatedModel, cmd := m.DashboardModel.Update(msg)
+			if updatedDashboardModel, ok := updatedModel.(*dashboard.DashboardModel); ok {
+				m.DashboardModel = updatedDashboardModel
+			}
+			return m, cmd
+		}
+	case ProfileSwitchMsg:
+		return m, m.switchProfile(msg.Name)
+	case ProfileCreateMsg:
+		m.createProfile(msg.Name)
+		return m, nil
+	case ProfileDeleteMsg:
+		m.deleteProfile(msg.Name)
+		return m, nil
+	case EndpointsProbedMsg, NetworkOpMsg, NetworkSurveyMsg, ReconnectMsg, spinner.TickMsg:
+		// Background network work started in Settings
+		_, cmd := m.SettingsModel.Update(msg)
+		return m, cmd
+	case BlockMsg:
+		// Settings owns the stream and schedules the next wait,
+		// the dashboard only records the samples.
+		m.DashboardModel.Update(msg)
+		_, cmd := m.SettingsModel.Update(msg)
+		return m, cmd
+	case BatchSentMsg, RunDoneMsg, CheckedMsg, LoadedMsg:
+		// Batches, runs, checks and loads go on while other screens are open
+		_, cmd := m.CmdGoalsModel.Update(msg)
+		return m, cmd
+	case TxnsLoadedMsg:
+		_, cmd := m.ExploreModel.Update(msg)
+		return m, cmd
+	case NodeStatusMsg, NodeActionMsg, NodeTickMsg:
+		updatedModel, cmd := m.NodeModel.Update(msg)
+		if updatedNodeModel, ok := updatedModel.(*NodeModel); ok {
+			m.NodeModel = updatedNodeModel
 		}
+		return m, cmd
 	case tea.WindowSizeMsg:
 		// Update dimensions
 		m.width = msg.Width
@@ -189,6 +298,17 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// broadcast hands an application event to every screen.
+func (m *MainModel) broadcast(e appctx.Event) tea.Cmd {
+	screens := []tea.Model{m.ApplicationsModel, m.CmdGoalsModel, m.ExploreModel, m.NodeModel, m.DashboardModel}
+	cmds := make([]tea.Cmd, 0, len(screens))
+	for _, s := range screens {
+		_, cmd := s.Update(e)
+		cmds = append(cmds, cmd)
+	}
+	return tea.Batch(cmds...)
+}
+
 func (m *MainModel) View() string {
 	switch m.CurrentState {
 	case MainView:
@@ -207,7 +327,7 @@ func (m *MainModel) View() string {
 
 		// Build and render FlexBox only if dimensions OK
 		flexBox := m.mainLayout.Build()
-		return flexBox.Render()
+		return m.withHeader(flexBox.Render())
 
 	case ProjectView:
 		// Use FlexBox for ProjectView
@@ -215,19 +335,14 @@ func (m *MainModel) View() string {
 			m.projectLayout = layout.NewProjectLayout(m.width, m.height)
 		}
 
-		// Create preview generator function
-		previewGenerator := func(option string) layout.PreviewContent {
-			return layout.PreviewContent{
-				Title:        option,
-				Description:  subtitleFor(option),
-				Instructions: "Press ENTER to select\nPress ESC to go back",
-			}
-		}
+		// Menu options, or profiles while the switcher is open
+		items, cursor, title, previewGenerator := m.menuState()
 
 		// Configure layout with all menu items (pre-calculates all previews)
 		m.projectLayout.
-			SetMenuItems(m.ProjectModel.Options, previewGenerator).
-			SetCursor(m.ProjectModel.Cursor)
+			SetTitle(title).
+			SetMenuItems(items, previewGenerator).
+			SetCursor(cursor)
 
 		// Check dimensions
 		if !m.projectLayout.IsValid() {
@@ -235,19 +350,25 @@ func (m *MainModel) View() string {
 		}
 
 		// Render (includes centering with external padding)
-		return m.projectLayout.Render()
+		return m.withHeader(m.projectLayout.Render())
 
 	case SettingsView:
-		return m.layoutContainer.Render(m.SettingsModel.View())
+		return m.withHeader(m.layoutContainer.Render(m.SettingsModel.View()))
 
 	case ApplicationsView:
-		return m.layoutContainer.Render(m.ApplicationsModel.View())
+		return m.withHeader(m.layoutContainer.Render(m.ApplicationsModel.View()))
 
 	case CmdGoalsView:
-		return m.layoutContainer.Render(m.CmdGoalsModel.View())
+		return m.withHeader(m.layoutContainer.Render(m.CmdGoalsModel.View()))
 
 	case ExploreView:
-		return m.layoutContainer.Render(m.ExploreModel.View())
+		return m.withHeader(m.layoutContainer.Render(m.ExploreModel.View()))
+
+	case NodeView:
+		return m.withHeader(m.layoutContainer.Render(m.NodeModel.View()))
+
+	case DashboardView:
+		return m.withHeader(m.layoutContainer.Render(m.DashboardModel.View()))
 
 	default:
 		return ""
@@ -265,13 +386,23 @@ func subtitleFor(option string) string {
 		return "Why CLI when you can TUI? Build transactions easily"
 	case "Explore":
 		return "Explore blockchain data and resources"
+	case "Node":
+		return "Start, stop and catch up your own node with goal"
+	case "Dashboard":
+		return "Live network metrics, refreshed on every block"
+	case "Profiles":
+		return "Switch between named contexts: network, account, node and address book"
 	default:
 		return ""
 	}
 }
 
 func main() {
-	p = tea.NewProgram(NewMainModel(), tea.WithAltScreen())
+	overrides := mustParseOverrides()
+	if overrides.ConfigPath != "" {
+		SetConfigPath(overrides.ConfigPath)
+	}
+	p = tea.NewProgram(NewMainModel(overrides), tea.WithAltScreen())
 	if err := p.Start(); err != nil {
 		fmt.Printf("Error starting program: %v\n", err)
 	}
@@ -4,20 +4,45 @@ import (
 	"encoding/json"
 	"fmt"
 	"os/exec"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+
+	algo "lazychain/lib"
 )
 
 // Account type represents a single Algorand account entry
 type Account struct {
-	Address string  `json:"address"`
-	ALGO    float64 `json:"amount,string"`
+	Address string `json:"address"`
+	// Balance in microAlgos, exact
+	Balance algo.Amount `json:"-"`
 	// Additional fields can be added here as needed
 	// such as account name, creation date, assets, etc.
 }
 
+// UnmarshalJSON reads goal's amount, a decimal ALGO figure, without going
+// through a float.
+func (a *Account) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Address string          `json:"address"`
+		Amount  json.RawMessage `json:"amount"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	a.Address = raw.Address
+	if amount := strings.Trim(string(raw.Amount), `"`); amount != "" {
+		balance, err := algo.ParseDecimal(amount, algo.AlgoDecimals)
+		if err != nil {
+			return fmt.Errorf("account %s: %w", raw.Address, err)
+		}
+		a.Balance = balance
+	}
+	return nil
+}
+
 // Messages
 // AccountFetchedMsg carries the fetched account slice or an error
 type AccountFetchedMsg struct {
@@ -124,7 +149,7 @@ func (a Account) Title() string {
 }
 
 func (a Account) Description() string {
-	return fmt.Sprintf("Balance: %.6f ALGO", a.ALGO)
+	return "Balance: " + a.Balance.Algos()
 }
 
 // TODO: implement sorting, export, txn history, asset indicators, etc.
@@ -0,0 +1,187 @@
+// Package appctx holds the state every screen shares: the live network
+// connection, the configuration, the active account and its signer, and
+// the event bus screens use to learn what changed.
+package appctx
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/indexer"
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+	tea "github.com/charmbracelet/bubbletea"
+
+	algo "lazychain/lib"
+	"lazychain/models/settings"
+)
+
+// Context is created once by MainModel and handed to every screen. The
+// network manager and configuration stay owned by Settings, which edits
+// them; the context is the read side plus the signer and the bus.
+type Context struct {
+	settings *settings.SettingsModel
+	bus      *Bus
+
+	// Signer cache, rebuilt when the connection or account changes
+	signer      *algo.AlgoClient
+	signerEpoch uint64
+	signerAddr  string
+	// IDs of transactions approved on a review screen, see Approve; a
+	// batch checks them from its own goroutine
+	approvedMu sync.Mutex
+	approved   map[string]bool
+
+	// Last state seen by Sync
+	seenEpoch     uint64
+	seenConnected bool
+	seenAccount   string
+	seenProfile   string
+}
+
+// New builds the context around the settings that own the connection and
+// the configuration.
+func New(s *settings.SettingsModel) *Context {
+	c := &Context{settings: s, bus: NewBus(), approved: map[string]bool{}}
+	c.seenAccount = s.WalletAddr()
+	if p, ok := s.ActiveProfile(); ok {
+		c.seenProfile = p.Name
+	}
+	return c
+}
+
+// Network is the manager of the live connection.
+func (c *Context) Network() *settings.NetworkManager { return c.settings.GetNetworkManager() }
+
+// Algod is the connected algod client, nil while offline.
+func (c *Context) Algod() *algod.Client { return c.Network().GetAlgodClient() }
+
+// Indexer is the connected indexer client, nil while offline or when the
+// network has no indexer.
+func (c *Context) Indexer() *indexer.Client { return c.Network().GetIndexerClient() }
+
+// Config is a snapshot of the configuration in use, overrides included.
+func (c *Context) Config() settings.Config { return c.settings.Config() }
+
+// Profile is the active profile, ok is false without profiles.
+func (c *Context) Profile() (settings.Profile, bool) { return c.settings.ActiveProfile() }
+
+// UpdateProfile edits and saves the active profile.
+func (c *Context) UpdateProfile(edit func(*settings.Profile)) error {
+	return c.settings.UpdateActiveProfile(edit)
+}
+
+// Account is the active account address, "" when none is configured.
+func (c *Context) Account() string { return c.settings.WalletAddr() }
+
+// Vault gives access to imported accounts and their keys.
+func (c *Context) Vault() *settings.Vault { return c.settings.Vault() }
+
+// Bus is the application event bus.
+func (c *Context) Bus() *Bus { return c.bus }
+
+// Signer returns a client for the connected network that signs as the
+// active account. It refuses transactions for another chain, and needs
+// the account's mnemonic in the unlocked vault.
+func (c *Context) Signer() (*algo.AlgoClient, error) {
+	nm := c.Network()
+	if !nm.IsConnected() {
+		return nil, fmt.Errorf("not connected to any network")
+	}
+	addr := c.Account()
+	if addr == "" {
+		return nil, fmt.Errorf("no active account, set a wallet in Settings")
+	}
+	vault := c.Vault()
+	if !vault.IsUnlocked() {
+		// the cached key goes with the lock
+		c.signer = nil
+		return nil, fmt.Errorf("unlock the vault in Settings (v) to sign as %s", short(addr))
+	}
+	if c.signer != nil && c.signerEpoch == nm.Epoch() && c.signerAddr == addr {
+		return c.signer, nil
+	}
+	words, err := vault.MnemonicFor(addr)
+	if err != nil {
+		return nil, fmt.Errorf("no key for %s in the vault: %w", short(addr), err)
+	}
+
+	client := algo.NewClientFrom(nm.GetAlgodClient(), nm.GetIndexerClient())
+	if err := client.SetAccountFromMnemonic(words); err != nil {
+		return nil, err
+	}
+	// Sign only for the chain the network is pinned to; the node's own
+	// identity was checked against the pin when connecting.
+	pinned := nm.GetCurrentNetwork()
+	if pinned.GenesisHash == "" {
+		return nil, fmt.Errorf("%s is not pinned to a genesis, reconnect to it in Settings", pinned.Name)
+	}
+	client.SetExpectedGenesis(pinned.GenesisID, pinned.GenesisHash)
+	client.SetConfirm(c.checkApproved)
+
+	c.signer, c.signerEpoch, c.signerAddr = client, nm.Epoch(), addr
+	return client, nil
+}
+
+// Approve lets the signer send txns, once, after the user confirmed them
+// on a review screen.
+func (c *Context) Approve(txns []types.Transaction) {
+	c.approvedMu.Lock()
+	defer c.approvedMu.Unlock()
+	for _, txn := range txns {
+		c.approved[crypto.GetTxID(txn)] = true
+	}
+}
+
+// checkApproved is the signer's review gate: every transaction must have
+// been approved, and each approval is used up.
+func (c *Context) checkApproved(txns []types.Transaction) error {
+	c.approvedMu.Lock()
+	defer c.approvedMu.Unlock()
+	for _, txn := range txns {
+		if !c.approved[crypto.GetTxID(txn)] {
+			return fmt.Errorf("refusing to sign: transaction was not reviewed")
+		}
+	}
+	for _, txn := range txns {
+		delete(c.approved, crypto.GetTxID(txn))
+	}
+	return nil
+}
+
+// Sync compares the shared state with what it was at the last call and
+// publishes an event for every change. MainModel calls it after each
+// update, so screens never poll.
+func (c *Context) Sync() tea.Cmd {
+	var cmds []tea.Cmd
+	nm := c.Network()
+
+	connected := nm.IsConnected()
+	if connected != c.seenConnected || (connected && nm.Epoch() != c.seenEpoch) {
+		if connected {
+			cmds = append(cmds, c.bus.Publish(Event{Kind: NetworkConnected, Network: nm.GetCurrentNetwork().Name}))
+		} else {
+			cmds = append(cmds, c.bus.Publish(Event{Kind: NetworkDisconnected}))
+		}
+		c.seenConnected, c.seenEpoch = connected, nm.Epoch()
+	}
+
+	if p, ok := c.Profile(); ok && p.Name != c.seenProfile {
+		c.seenProfile = p.Name
+		cmds = append(cmds, c.bus.Publish(Event{Kind: ProfileSwitched, Profile: p.Name}))
+	}
+
+	if addr := c.Account(); addr != c.seenAccount {
+		c.seenAccount = addr
+		cmds = append(cmds, c.bus.Publish(Event{Kind: AccountChanged, Account: addr}))
+	}
+	return tea.Batch(cmds...)
+}
+
+func short(addr string) string {
+	if len(addr) > 12 {
+		return addr[:6] + "..." + addr[len(addr)-4:]
+	}
+	return addr
+}
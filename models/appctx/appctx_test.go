@@ -0,0 +1,42 @@
+package appctx
+
+import (
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/v2/transaction"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+func TestCheckApproved(t *testing.T) {
+	sp := types.SuggestedParams{Fee: 1000, FlatFee: true, FirstRoundValid: 1, LastRoundValid: 1000,
+		GenesisID: "testnet-v1.0", GenesisHash: make([]byte, 32)}
+	pay := func(amount uint64) types.Transaction {
+		txn, err := transaction.MakePaymentTxn(types.Address{1}.String(), types.Address{2}.String(), amount, nil, "", sp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return txn
+	}
+	a, b, c := pay(1), pay(2), pay(3)
+
+	ctx := &Context{approved: map[string]bool{}}
+	ctx.Approve([]types.Transaction{a, b})
+
+	tests := []struct {
+		name    string
+		txns    []types.Transaction
+		wantErr bool
+	}{
+		{"never approved", []types.Transaction{c}, true},
+		{"group with one unapproved", []types.Transaction{a, c}, true},
+		// a refused group uses up no approval
+		{"approved group", []types.Transaction{a, b}, false},
+		// each approval is good once
+		{"approved again", []types.Transaction{a}, true},
+	}
+	for _, tt := range tests {
+		if err := ctx.checkApproved(tt.txns); (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package appctx
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// EventKind tells what an Event is about.
+type EventKind int
+
+const (
+	// NetworkConnected: a connection was made, Network names it.
+	NetworkConnected EventKind = iota
+	// NetworkDisconnected: the live connection was dropped.
+	NetworkDisconnected
+	// AccountChanged: the active account is now Account ("" for none).
+	AccountChanged
+	// ProfileSwitched: Profile is now the active profile.
+	ProfileSwitched
+	// TxSubmitted: Account sent a transaction, or a group whose first
+	// transaction is TxID.
+	TxSubmitted
+)
+
+// Event is something screens may want to react to. Events travel as tea
+// messages: MainModel hands every Event to every screen.
+type Event struct {
+	Kind    EventKind
+	Network string
+	Account string
+	Profile string
+	TxID    string
+}
+
+// Bus delivers events to the screens as tea messages; a screen subscribes
+// by handling Event in its Update.
+type Bus struct{}
+
+func NewBus() *Bus { return &Bus{} }
+
+// Publish returns the command that delivers e to the screens.
+func (b *Bus) Publish(e Event) tea.Cmd {
+	return func() tea.Msg { return e }
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"lazychain/models/appctx"
+
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -8,11 +10,13 @@ import (
 
 type ApplicationsModel struct {
 	CurrentState SessionState
+	app          *appctx.Context
 }
 
-func NewApplicationsModel() *ApplicationsModel {
+func NewApplicationsModel(app *appctx.Context) *ApplicationsModel {
 	return &ApplicationsModel{
 		CurrentState: ApplicationsView,
+		app:          app,
 	}
 }
 
@@ -0,0 +1,270 @@
+package dashboard
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"lazychain/layout"
+	"lazychain/models/appctx"
+	"lazychain/models/settings"
+)
+
+// maxSamples bounds the history kept for the sparklines.
+const maxSamples = 60
+
+const sparkWidth = 36
+
+// DashboardModel shows live network metrics fed by the settings block
+// stream (settings.BlockMsg). It never queries the node itself.
+type DashboardModel struct {
+	nm *settings.NetworkManager
+
+	epoch   uint64
+	samples []settings.BlockSample
+}
+
+func NewDashboardModel(app *appctx.Context) *DashboardModel {
+	return &DashboardModel{nm: app.Network()}
+}
+
+func (m *DashboardModel) Init() tea.Cmd { return nil }
+
+func (m *DashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case appctx.Event:
+		if msg.Kind == appctx.NetworkDisconnected {
+			m.samples = nil
+		}
+	case settings.BlockMsg:
+		if msg.Err != nil || msg.Epoch != m.nm.Epoch() {
+			return m, nil
+		}
+		if msg.Epoch != m.epoch {
+			// new connection: old history belongs to another network
+			m.epoch = msg.Epoch
+			m.samples = nil
+		}
+		if n := len(m.samples); n > 0 && m.samples[n-1].Round >= msg.Sample.Round {
+			return m, nil
+		}
+		m.samples = append(m.samples, msg.Sample)
+		if len(m.samples) > maxSamples {
+			m.samples = m.samples[len(m.samples)-maxSamples:]
+		}
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "x":
+			m.samples = nil
+		}
+	}
+	return m, nil
+}
+
+// series derives per-block metrics from consecutive samples. Gaps of more
+// than one round are averaged over the rounds they span.
+func (m *DashboardModel) series() (blockTimes, txns, pending []float64) {
+	for i, s := range m.samples {
+		pending = append(pending, float64(s.PendingTxns))
+		if i == 0 {
+			continue
+		}
+		prev := m.samples[i-1]
+		rounds := float64(s.Round - prev.Round)
+		if rounds <= 0 || s.Timestamp.IsZero() || prev.Timestamp.IsZero() {
+			continue
+		}
+		blockTimes = append(blockTimes, s.Timestamp.Sub(prev.Timestamp).Seconds()/rounds)
+		if s.TxnCounter >= prev.TxnCounter {
+			txns = append(txns, float64(s.TxnCounter-prev.TxnCounter)/rounds)
+		}
+	}
+	return blockTimes, txns, pending
+}
+
+// averageBlockTime uses the first and last header timestamps of the window,
+// which smooths out the one-second resolution of block timestamps.
+func (m *DashboardModel) averageBlockTime() (time.Duration, bool) {
+	var first, last *settings.BlockSample
+	for i := range m.samples {
+		if m.samples[i].Timestamp.IsZero() {
+			continue
+		}
+		if first == nil {
+			first = &m.samples[i]
+		}
+		last = &m.samples[i]
+	}
+	if first == nil || last.Round <= first.Round {
+		return 0, false
+	}
+	span := last.Timestamp.Sub(first.Timestamp)
+	return span / time.Duration(last.Round-first.Round), true
+}
+
+func (m *DashboardModel) View() string {
+	if !m.nm.IsConnected() {
+		msg := lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086")).Italic(true).
+			Render("Not connected. Connect to a network from Settings to see live data.")
+		return lipgloss.JoinVertical(lipgloss.Left, m.renderTitle(), "", msg, "", m.renderFooter())
+	}
+
+	left := m.renderChainPanel()
+	right := m.renderChartsPanel()
+	return lipgloss.JoinVertical(lipgloss.Left,
+		m.renderTitle(),
+		"",
+		lipgloss.JoinHorizontal(lipgloss.Top, left, "  ", right),
+		"",
+		m.renderFooter(),
+	)
+}
+
+func (m *DashboardModel) renderTitle() string {
+	return lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#cba6f7")).
+		Render("Network Dashboard")
+}
+
+func (m *DashboardModel) renderChainPanel() string {
+	label := lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086"))
+	value := lipgloss.NewStyle().Foreground(lipgloss.Color("#cdd6f4")).Bold(true)
+	row := func(k, v string) string { return label.Render(k+": ") + value.Render(v) }
+
+	content := []string{
+		lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#89b4fa")).Render(m.nm.GetCurrentNetwork().Name),
+		"",
+	}
+
+	if len(m.samples) == 0 {
+		content = append(content, label.Italic(true).Render("Waiting for the next block..."))
+	} else {
+		last := m.samples[len(m.samples)-1]
+		content = append(content, row("Round", fmt.Sprintf("%d", last.Round)))
+
+		if avg, ok := m.averageBlockTime(); ok {
+			content = append(content, row("Avg block time", fmt.Sprintf("%.2fs", avg.Seconds())))
+		} else {
+			content = append(content, row("Avg block time", "-"))
+		}
+		content = append(content,
+			row("Pending pool", fmt.Sprintf("%d", last.PendingTxns)),
+			row("Since last", time.Since(last.ReceivedAt).Round(time.Second).String()),
+			"",
+			row("Protocol", shortProtocol(last.Status.LastVersion)),
+		)
+		content = append(content, upgradeLines(last, label, value)...)
+	}
+
+	if err := m.nm.StreamErr(); err != nil {
+		content = append(content, "", lipgloss.NewStyle().Foreground(lipgloss.Color("#f9e2af")).
+			Width(26).Render("Stream error, retrying: "+err.Error()))
+	}
+
+	for len(content) < 14 {
+		content = append(content, "")
+	}
+	return lipgloss.NewStyle().
+		Width(30).
+		Padding(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#89b4fa")).
+		Render(strings.Join(content, "\n"))
+}
+
+// upgradeLines describes a pending consensus upgrade, if any.
+func upgradeLines(s settings.BlockSample, label, value lipgloss.Style) []string {
+	st := s.Status
+	var lines []string
+	if st.NextVersion != "" && st.NextVersion != st.LastVersion {
+		lines = append(lines,
+			label.Render("Upgrade to: ")+value.Render(shortProtocol(st.NextVersion)),
+			label.Render("  at round: ")+value.Render(fmt.Sprintf("%d", st.NextVersionRound)))
+		if !st.NextVersionSupported {
+			lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("#f38ba8")).
+				Render("  node does NOT support it"))
+		}
+		return lines
+	}
+	if st.UpgradeVotesRequired > 0 {
+		vote := "no"
+		if st.UpgradeNodeVote {
+			vote = "yes"
+		}
+		lines = append(lines,
+			label.Render("Upgrade vote: ")+value.Render(fmt.Sprintf("%d/%d yes", st.UpgradeYesVotes, st.UpgradeVotesRequired)),
+			label.Render("  no votes: ")+value.Render(fmt.Sprintf("%d", st.UpgradeNoVotes)),
+			label.Render("  ends round: ")+value.Render(fmt.Sprintf("%d", st.UpgradeNextProtocolVoteBefore)),
+			label.Render("  this node: ")+value.Render(vote))
+		return lines
+	}
+	return append(lines, label.Render("Upgrade: ")+value.Render("none pending"))
+}
+
+func (m *DashboardModel) renderChartsPanel() string {
+	blockTimes, txns, pending := m.series()
+	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#a6e3a1"))
+	spark := lipgloss.NewStyle().Foreground(lipgloss.Color("#ef9f76"))
+	faint := lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086"))
+
+	chart := func(name string, values []float64, format string) []string {
+		header := title.Render(name)
+		if n := len(values); n > 0 {
+			lo, hi := minMax(values)
+			header += faint.Render(fmt.Sprintf("  now "+format+"  min "+format+"  max "+format, values[n-1], lo, hi))
+		}
+		return []string{header, spark.Render(layout.Sparkline(values, sparkWidth)), ""}
+	}
+
+	var content []string
+	content = append(content, chart("Block time (s)", blockTimes, "%.1f")...)
+	content = append(content, chart("Txns per block", txns, "%.0f")...)
+	content = append(content, chart("Pending pool", pending, "%.0f")...)
+	content = append(content, faint.Italic(true).Render(fmt.Sprintf("last %d blocks", len(m.samples))))
+
+	for len(content) < 14 {
+		content = append(content, "")
+	}
+	return lipgloss.NewStyle().
+		Width(45).
+		Padding(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#a6e3a1")).
+		Render(strings.Join(content, "\n"))
+}
+
+func (m *DashboardModel) renderFooter() string {
+	instructions := []string{"Live updates on every block", "x: Clear history", "ESC: Back"}
+	return lipgloss.NewStyle().
+		Width(77).
+		Align(lipgloss.Center).
+		Foreground(lipgloss.Color("#6c7086")).
+		Italic(true).
+		Render(strings.Join(instructions, " | "))
+}
+
+func minMax(values []float64) (lo, hi float64) {
+	lo, hi = values[0], values[0]
+	for _, v := range values {
+		if v < lo {
+			lo = v
+		}
+		if v > hi {
+			hi = v
+		}
+	}
+	return lo, hi
+}
+
+// shortProtocol trims consensus protocol URLs down to their version tag.
+func shortProtocol(v string) string {
+	if v == "" {
+		return "-"
+	}
+	return path.Base(v)
+}
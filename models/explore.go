@@ -1,36 +1,252 @@
 package models
 
 import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	idx "github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	algo "lazychain/lib"
+	"lazychain/models/appctx"
+	"lazychain/models/goal/components"
 )
 
-// ExploreModel isn't ready yet, but it's a placeholder for now.
+// historyLimit is how many of the account's transactions are listed.
+const historyLimit = 25
+
+// TxnsLoadedMsg delivers the result of an explorer query.
+type TxnsLoadedMsg struct {
+	Query string
+	Txns  []idx.Transaction
+	Err   error
+}
 
+// ExploreModel lists the active account's transactions, or looks one up
+// by ID, with notes decoded.
 type ExploreModel struct {
 	CurrentState SessionState
+	app          *appctx.Context
+
+	// query is "" for the account's history, a transaction ID, or
+	// "note:<prefix>" for the account's transactions with that note
+	query   string
+	txns    []idx.Transaction
+	cursor  int
+	loading bool
+	err     error
+
+	searching bool
+	input     components.Field
 }
 
-func NewExploreModel() *ExploreModel {
+func NewExploreModel(app *appctx.Context) *ExploreModel {
 	return &ExploreModel{
 		CurrentState: ExploreView,
+		app:          app,
 	}
 }
 
 func (m *ExploreModel) Init() tea.Cmd {
-	return nil
+	return m.load()
+}
+
+// IsEditing reports whether the search input has the keyboard, so ESC
+// closes it instead of leaving the view.
+func (m *ExploreModel) IsEditing() bool { return m.searching }
+
+// load runs the current query against the indexer in the background.
+func (m *ExploreModel) load() tea.Cmd {
+	query, client, account := m.query, m.app.Indexer(), m.app.Account()
+	m.loading, m.err = true, nil
+	return func() tea.Msg {
+		msg := TxnsLoadedMsg{Query: query}
+		if client == nil {
+			msg.Err = fmt.Errorf("no indexer for this network")
+			return msg
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if query != "" && !strings.HasPrefix(query, "note:") {
+			res, err := client.LookupTransaction(query).Do(ctx)
+			if err != nil {
+				msg.Err = err
+			} else {
+				msg.Txns = []idx.Transaction{res.Transaction}
+			}
+			return msg
+		}
+		if account == "" {
+			msg.Err = fmt.Errorf("no active account, set a wallet in Settings")
+			return msg
+		}
+		search := client.SearchForTransactions().AddressString(account).Limit(historyLimit)
+		if prefix, ok := strings.CutPrefix(query, "note:"); ok {
+			search = search.NotePrefix([]byte(prefix))
+		}
+		res, err := search.Do(ctx)
+		msg.Txns, msg.Err = res.Transactions, err
+		return msg
+	}
 }
 
 func (m *ExploreModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case appctx.Event:
+		switch msg.Kind {
+		case appctx.NetworkConnected, appctx.AccountChanged:
+			m.query = ""
+			return m, m.load()
+		case appctx.TxSubmitted:
+			// the history gains the transaction, a search stays as is
+			if m.query == "" && msg.Account == m.app.Account() {
+				return m, m.load()
+			}
+		}
+	case TxnsLoadedMsg:
+		if msg.Query != m.query {
+			return m, nil // superseded
+		}
+		m.loading = false
+		m.txns, m.err, m.cursor = msg.Txns, msg.Err, 0
 	case tea.KeyMsg:
+		if m.searching {
+			return m.handleSearch(msg)
+		}
 		switch msg.String() {
 		case "ctrl+c", "q":
 			return m, tea.Quit
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.txns)-1 {
+				m.cursor++
+			}
+		case "/":
+			m.searching = true
+			m.input = components.Field{Label: "Transaction ID or note:<prefix>", Active: true}
+		case "h":
+			m.query = ""
+			return m, m.load()
+		case "r":
+			return m, m.load()
 		}
 	}
 	return m, nil
 }
 
+func (m *ExploreModel) handleSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.searching = false
+	case "enter":
+		m.searching = false
+		m.query = strings.TrimSpace(m.input.Value)
+		return m, m.load()
+	default:
+		m.input.HandleKey(msg)
+	}
+	return m, nil
+}
+
 func (m *ExploreModel) View() string {
-	return "Explore View\n\nPress ESC to go back to the main view.\n"
+	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#cba6f7"))
+	faint := lipgloss.NewStyle().Faint(true)
+
+	heading := "History of the active account"
+	switch {
+	case strings.HasPrefix(m.query, "note:"):
+		heading = "Notes starting with " + strings.TrimPrefix(m.query, "note:")
+	case m.query != "":
+		heading = "Transaction " + shortID(m.query)
+	}
+	left := []string{title.Render(heading), ""}
+	switch {
+	case m.loading:
+		left = append(left, faint.Render("Loading..."))
+	case m.err != nil:
+		left = append(left, lipgloss.NewStyle().Foreground(lipgloss.Color("#f38ba8")).Render("Error: "+m.err.Error()))
+	case len(m.txns) == 0:
+		left = append(left, faint.Render("No transactions"))
+	}
+	for i, txn := range m.txns {
+		line := fmt.Sprintf("%-9d %-5s %s", txn.ConfirmedRound, txn.Type, shortID(txn.Id))
+		if len(txn.Note) > 0 {
+			line += " ✎"
+		}
+		if i == m.cursor {
+			line = lipgloss.NewStyle().Foreground(lipgloss.Color("#ef9f76")).Render("> " + line)
+		} else {
+			line = "  " + line
+		}
+		left = append(left, line)
+	}
+	if m.searching {
+		left = append(left, "", m.input.Render(38))
+	}
+	panel := func(color string, lines []string) string {
+		return lipgloss.NewStyle().
+			Width(45).
+			Padding(1).
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color(color)).
+			Render(strings.Join(lines, "\n"))
+	}
+
+	footer := faint.Render(strings.Join([]string{
+		"↑/↓: Select", "/: Find ID or note:<prefix>", "h: History", "r: Refresh", "ESC: Back",
+	}, " | "))
+	return lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.JoinHorizontal(lipgloss.Top, panel("#89b4fa", left), "  ", panel("#a6e3a1", m.details())),
+		"",
+		footer,
+	)
+}
+
+// details describes the selected transaction, its note decoded.
+func (m *ExploreModel) details() []string {
+	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#a6e3a1"))
+	key := lipgloss.NewStyle().Bold(true).Width(10)
+	lines := []string{title.Render("Details"), ""}
+	if m.cursor >= len(m.txns) {
+		return lines
+	}
+	txn := m.txns[m.cursor]
+	row := func(k, v string) { lines = append(lines, key.Render(k)+v) }
+
+	row("ID", shortID(txn.Id))
+	row("Type", txn.Type)
+	row("Round", fmt.Sprint(txn.ConfirmedRound))
+	if txn.RoundTime > 0 {
+		row("Time", time.Unix(int64(txn.RoundTime), 0).Format("2006-01-02 15:04"))
+	}
+	row("From", shortID(txn.Sender))
+	switch txn.Type {
+	case "pay":
+		row("To", shortID(txn.PaymentTransaction.Receiver))
+		row("Amount", algo.Amount(txn.PaymentTransaction.Amount).Algos())
+	case "axfer":
+		row("To", shortID(txn.AssetTransferTransaction.Receiver))
+		row("Amount", fmt.Sprintf("%d of asset %d", txn.AssetTransferTransaction.Amount, txn.AssetTransferTransaction.AssetId))
+	}
+	row("Fee", algo.Amount(txn.Fee).Algos())
+
+	if len(txn.Note) > 0 {
+		note := algo.DecodeNote(txn.Note)
+		lines = append(lines, "", title.Render(fmt.Sprintf("Note (%s, %d bytes)", note.Format, len(txn.Note))), note.Text)
+	}
+	return lines
+}
+
+func shortID(s string) string {
+	if len(s) > 16 {
+		return s[:8] + "..." + s[len(s)-6:]
+	}
+	return s
 }
@@ -23,4 +23,6 @@ import (
 
 
 type Builder = iface.Builder
-type RunFunc = iface.RunFunc
\ No newline at end of file
+type RunFunc = iface.RunFunc
+type CheckedMsg = iface.CheckedMsg
+type LoadedMsg = iface.LoadedMsg
@@ -0,0 +1,29 @@
+package builders
+
+import (
+	"errors"
+	"fmt"
+
+	algo "lazychain/lib"
+)
+
+// validAlgos accepts what algo.ParseAlgos reads, "max" included.
+func validAlgos(v string) error {
+	if _, err := algo.ParseAlgos(v); err != nil && !errors.Is(err, algo.ErrMax) {
+		return err
+	}
+	return nil
+}
+
+// previewAlgos spells an ALGO amount out both ways, so a wrong number of
+// zeros stands out before anything is sent.
+func previewAlgos(v string) string {
+	a, err := algo.ParseAlgos(v)
+	switch {
+	case errors.Is(err, algo.ErrMax):
+		return "balance - min balance - fee"
+	case err != nil:
+		return ""
+	}
+	return fmt.Sprintf("%s (%d µAlgo)", a.Algos(), uint64(a))
+}
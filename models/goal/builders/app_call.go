@@ -15,6 +15,7 @@ type AppCallBuilder struct {
 
 func NewAppCallBuilder() *AppCallBuilder { return &AppCallBuilder{} }
 func (b *AppCallBuilder) Title() string { return "App Call (goal app call/method)" }
+func (b *AppCallBuilder) Backends() []goal.Backend { return []goal.Backend{goal.BackendGoal} }
 func (b *AppCallBuilder) Init() tea.Cmd { return nil }
 func (b *AppCallBuilder) Validate() error { return nil }
 func (b *AppCallBuilder) Args() []string { return []string{"app","call","--app-id","0"} }
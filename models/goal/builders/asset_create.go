@@ -0,0 +1,566 @@
+package builders
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/algorand/go-algorand-sdk/v2/types"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	algo "lazychain/lib"
+	"lazychain/models/goal/components"
+	goal "lazychain/models/goal/iface"
+)
+
+// Standards the wizard mints.
+const (
+	standardFungible = "fungible token"
+	standardARC3     = "ARC-3 NFT"
+	standardARC19    = "ARC-19 NFT"
+	standardARC69    = "ARC-69 NFT"
+)
+
+// roleNone leaves a role unset, for good.
+const roleNone = "none"
+
+// wizardSteps are the pages of the wizard, in order.
+var wizardSteps = []string{"Asset", "Supply", "Metadata", "Roles"}
+
+// assetRoles are the role fields with their labels.
+var assetRoles = []struct{ key, label string }{
+	{"manager", "Manager"}, {"reserve", "Reserve"}, {"freeze", "Freeze"}, {"clawback", "Clawback"},
+}
+
+// AssetCreateBuilder walks through creating an asset one page at a time,
+// with a live summary of what holders will see. NFTs follow ARC-3 (hash
+// of the metadata JSON), ARC-19 (metadata CID in the reserve address) or
+// ARC-69 (metadata JSON in the note).
+type AssetCreateBuilder struct {
+	formBuilder
+
+	step int
+	// meta is the metadata file as last read
+	meta metadataFile
+}
+
+// metadataFile is a metadata JSON read from disk once per path.
+type metadataFile struct {
+	path string
+	data []byte
+	err  error
+}
+
+func NewAssetCreateBuilder() *AssetCreateBuilder {
+	b := &AssetCreateBuilder{}
+	on := func(step int, standards ...string) func(f *components.Form) bool {
+		return func(f *components.Form) bool {
+			if b.step != step {
+				return false
+			}
+			for _, s := range standards {
+				if b.value("standard") == s {
+					return true
+				}
+			}
+			return len(standards) == 0
+		}
+	}
+	fields := []*components.FormField{
+		&components.FormField{Key: "standard", Kind: components.KindEnum, VisibleIf: on(0),
+			Options: []string{standardFungible, standardARC3, standardARC19, standardARC69},
+			Field:   components.Field{Label: "Kind of asset"}},
+		&components.FormField{Key: "name", Required: true, VisibleIf: on(0),
+			Validators: []components.Validator{components.MaxBytes(types.AssetNameMaxLen)},
+			Field:      components.Field{Label: "Asset name", Hint: "up to 32 bytes"}},
+		&components.FormField{Key: "unit", VisibleIf: on(0),
+			Validators: []components.Validator{components.MaxBytes(types.AssetUnitNameMaxLen)},
+			Field:      components.Field{Label: "Unit name", Hint: "ticker, up to 8 bytes"}},
+
+		&components.FormField{Key: "decimals", Kind: components.KindUint, VisibleIf: on(1),
+			Validators: []components.Validator{validDecimals},
+			Field:      components.Field{Label: "Decimals", Hint: "0 to 19, empty for 0"}},
+		&components.FormField{Key: "total", Required: true, VisibleIf: on(1),
+			Validators: []components.Validator{b.validTotal}, Preview: b.previewTotal,
+			Field: components.Field{Label: "Total supply", Hint: "in whole units, e.g. 1_000_000; 1 for an NFT"}},
+
+		&components.FormField{Key: "url", VisibleIf: on(2, standardFungible, standardARC3, standardARC69),
+			Validators: []components.Validator{components.MaxBytes(types.AssetURLMaxLen)},
+			Field:      components.Field{Label: "URL", Hint: "ARC-3: the metadata JSON; ARC-69: the media"}},
+		&components.FormField{Key: "cid", Required: true, VisibleIf: on(2, standardARC19),
+			Validators: []components.Validator{validCID},
+			Field:      components.Field{Label: "Metadata CID", Hint: "IPFS CID, Qm... or b..."}},
+		&components.FormField{Key: "metadata", Kind: components.KindPath, VisibleIf: on(2),
+			Validators: []components.Validator{components.FileExists, b.validMetadata},
+			Field:      components.Field{Label: "Metadata JSON file", Hint: "local file; hashed, or the note for ARC-69"}},
+
+		&components.FormField{Key: "manager", VisibleIf: on(3), Validators: []components.Validator{validRole},
+			Field: components.Field{Label: "Manager", Hint: "empty for you, none for no one"}},
+		&components.FormField{Key: "reserve", VisibleIf: on(3, standardFungible, standardARC3, standardARC69),
+			Validators: []components.Validator{validRole},
+			Field:      components.Field{Label: "Reserve", Hint: "empty for you, none for no one"}},
+		&components.FormField{Key: "freeze", VisibleIf: on(3), Validators: []components.Validator{validRole},
+			Field: components.Field{Label: "Freeze", Hint: "empty for you, none for no one"}},
+		&components.FormField{Key: "clawback", VisibleIf: on(3), Validators: []components.Validator{validRole},
+			Field: components.Field{Label: "Clawback", Hint: "empty for you, none for no one"}},
+		&components.FormField{Key: "frozen", Kind: components.KindBool, VisibleIf: on(3),
+			Field: components.Field{Label: "Default frozen", Hint: "holdings start frozen"}},
+		&components.FormField{Key: "fee", Kind: components.KindUint, Unit: "μAlgos", VisibleIf: on(3),
+			Field: components.Field{Label: "Fee", Hint: "optional; empty for suggested"}},
+	}
+	b.formBuilder = newFormBuilder("Create Asset (wizard)", nil, fields...)
+	b.check = b.validate
+	return b
+}
+
+func (b *AssetCreateBuilder) Backends() []goal.Backend { return []goal.Backend{goal.BackendNative} }
+
+// value is the trimmed value of key whatever the page shown; Form.Get
+// only sees the fields of the current page.
+func (b *AssetCreateBuilder) value(key string) string {
+	if fd := b.form.Field(key); fd != nil {
+		return strings.TrimSpace(fd.Value)
+	}
+	return ""
+}
+
+func validDecimals(v string) error {
+	if n, err := strconv.ParseUint(v, 10, 32); err == nil && n > types.AssetMaxNumberOfDecimals {
+		return fmt.Errorf("at most %d decimals", types.AssetMaxNumberOfDecimals)
+	}
+	return nil
+}
+
+func validCID(v string) error {
+	_, err := algo.ParseARC19(v)
+	return err
+}
+
+func validRole(v string) error {
+	if v == roleNone {
+		return nil
+	}
+	return components.ValidAddress(v)
+}
+
+func (b *AssetCreateBuilder) decimals() uint32 {
+	n, _ := strconv.ParseUint(b.value("decimals"), 10, 32)
+	return uint32(min(n, types.AssetMaxNumberOfDecimals))
+}
+
+// total is the supply in base units.
+func (b *AssetCreateBuilder) total() (algo.Amount, error) {
+	a, err := algo.ParseDecimal(b.value("total"), b.decimals())
+	if err == nil && a == 0 {
+		err = errors.New("must be more than 0")
+	}
+	return a, err
+}
+
+func (b *AssetCreateBuilder) validTotal(string) error {
+	_, err := b.total()
+	return err
+}
+
+func (b *AssetCreateBuilder) previewTotal(string) string {
+	a, err := b.total()
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%d base units", uint64(a))
+}
+
+// readMetadata reads the metadata file, again only when the path changed
+// or reload is set.
+func (b *AssetCreateBuilder) readMetadata(reload bool) metadataFile {
+	path := b.value("metadata")
+	if path == "" {
+		return metadataFile{}
+	}
+	if reload || b.meta.path != path {
+		data, err := os.ReadFile(components.ExpandHome(path))
+		b.meta = metadataFile{path: path, data: data, err: err}
+	}
+	return b.meta
+}
+
+func (b *AssetCreateBuilder) validMetadata(string) error {
+	m := b.readMetadata(true)
+	if m.err != nil {
+		return m.err
+	}
+	if b.value("standard") == standardARC69 {
+		_, err := algo.ARC69Note(m.data)
+		return err
+	}
+	_, err := algo.ParseARC3(m.data)
+	return err
+}
+
+// arc3 is the metadata file read as ARC-3, nil when there is none or it
+// is not valid.
+func (b *AssetCreateBuilder) arc3() *algo.ARC3Metadata {
+	m := b.readMetadata(false)
+	if m.data == nil || b.value("standard") == standardARC69 {
+		return nil
+	}
+	meta, err := algo.ParseARC3(m.data)
+	if err != nil {
+		return nil
+	}
+	return &meta
+}
+
+// url is the asset URL as stored: the ARC-19 template, and ARC-3 URLs
+// marked with #arc3 unless the name already says so.
+func (b *AssetCreateBuilder) url() string {
+	switch b.value("standard") {
+	case standardARC19:
+		arc19, err := algo.ParseARC19(b.value("cid"))
+		if err != nil {
+			return ""
+		}
+		if b.arc3() != nil {
+			return arc19.URL + algo.ARC3Suffix
+		}
+		return arc19.URL
+	case standardARC3:
+		url, name := b.value("url"), b.value("name")
+		if url == "" || strings.HasSuffix(url, algo.ARC3Suffix) || name == "arc3" || strings.HasSuffix(name, "@arc3") {
+			return url
+		}
+		return url + algo.ARC3Suffix
+	}
+	return b.value("url")
+}
+
+// role is the address of a role: the creator when empty, none when
+// unset; the ARC-19 reserve comes from the CID.
+func (b *AssetCreateBuilder) role(key, creator string) string {
+	if key == "reserve" && b.value("standard") == standardARC19 {
+		arc19, _ := algo.ParseARC19(b.value("cid"))
+		return arc19.Reserve
+	}
+	switch v := b.value(key); v {
+	case "":
+		return creator
+	case roleNone:
+		return ""
+	default:
+		return v
+	}
+}
+
+// asset puts the pages together into what is created by creator.
+func (b *AssetCreateBuilder) asset(creator string) (algo.NewAsset, error) {
+	total, totalErr := b.total()
+	a := algo.NewAsset{
+		Name:          b.value("name"),
+		UnitName:      b.value("unit"),
+		URL:           b.url(),
+		Total:         uint64(total),
+		Decimals:      b.decimals(),
+		DefaultFrozen: b.value("frozen") == "true",
+		Manager:       b.role("manager", creator),
+		Reserve:       b.role("reserve", creator),
+		Freeze:        b.role("freeze", creator),
+		Clawback:      b.role("clawback", creator),
+	}
+	if totalErr != nil {
+		return a, fmt.Errorf("total supply: %w", totalErr)
+	}
+	if m := b.readMetadata(false); m.data != nil {
+		var err error
+		switch b.value("standard") {
+		case standardARC69:
+			if a.Note, err = algo.ARC69Note(m.data); err != nil {
+				return a, err
+			}
+		case standardFungible, standardARC3:
+			meta, err := algo.ParseARC3(m.data)
+			if err != nil {
+				return a, err
+			}
+			a.MetadataHash = meta.Hash[:]
+		}
+	}
+	return a, a.Check()
+}
+
+// validate holds the rules across pages, and those of each standard.
+func (b *AssetCreateBuilder) validate(*components.Form) error {
+	// any address stands for the creator here
+	a, err := b.asset(types.Address{}.String())
+	if err != nil {
+		return err
+	}
+	switch b.value("standard") {
+	case standardARC3:
+		if b.value("url") == "" {
+			return errors.New("ARC-3 needs the URL of the metadata JSON")
+		}
+		if b.value("metadata") == "" {
+			return errors.New("ARC-3 needs the metadata JSON file for its hash")
+		}
+		if !pureOrFractional(a) {
+			return errors.New("ARC-3 NFTs have a total of 1, or 10^decimals when fractional")
+		}
+		if meta := b.arc3(); meta != nil && meta.Decimals != nil && *meta.Decimals != a.Decimals {
+			return fmt.Errorf("the metadata says %d decimals, the asset has %d", *meta.Decimals, a.Decimals)
+		}
+	case standardARC19:
+		if a.Manager == "" {
+			return errors.New("ARC-19 needs a manager to update the reserve, and so the metadata")
+		}
+	case standardARC69:
+		if b.value("metadata") == "" {
+			return errors.New("ARC-69 needs the metadata JSON file for the note")
+		}
+	}
+	if a.DefaultFrozen && a.Freeze == "" {
+		return errors.New("default frozen needs a freeze address, or holdings can never move")
+	}
+	return nil
+}
+
+// pureOrFractional reports whether a is a pure NFT or a fractional one,
+// as ARC-3 defines them.
+func pureOrFractional(a algo.NewAsset) bool {
+	whole := uint64(1)
+	for i := uint32(0); i < a.Decimals; i++ {
+		whole *= 10
+	}
+	return a.Total == whole
+}
+
+// Validate checks every page, then the rules across them; it stops on
+// the first page in error.
+func (b *AssetCreateBuilder) Validate() error {
+	current := b.step
+	for step := range wizardSteps {
+		b.step = step
+		if err := b.form.Validate(); err != nil {
+			b.form.FocusFirst()
+			return err
+		}
+	}
+	b.step = current
+	return b.check(b.form)
+}
+
+func (b *AssetCreateBuilder) SetValues(values map[string]string) {
+	b.formBuilder.SetValues(values)
+	b.goTo(0)
+}
+
+// goTo shows the page step.
+func (b *AssetCreateBuilder) goTo(step int) {
+	b.step = max(0, min(step, len(wizardSteps)-1))
+	b.form.FocusFirst()
+}
+
+// Transactions creates the asset with the active account as creator.
+func (b *AssetCreateBuilder) Transactions(sender string, sp types.SuggestedParams) ([]types.Transaction, error) {
+	a, err := b.asset(sender)
+	if err != nil {
+		return nil, err
+	}
+	if fee, err := strconv.ParseUint(b.value("fee"), 10, 64); err == nil {
+		sp.FlatFee = true
+		sp.Fee = types.MicroAlgos(fee)
+	}
+	txn, err := a.Transaction(sender, sp)
+	if err != nil {
+		return nil, err
+	}
+	return []types.Transaction{txn}, nil
+}
+
+// Update moves between pages: enter or pgdown goes on once the page is
+// valid, pgup goes back, enter on the last page creates the asset.
+func (b *AssetCreateBuilder) Update(msg tea.Msg) (goal.Builder, tea.Cmd) {
+	km, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return b, nil
+	}
+	switch km.String() {
+	case "pgup":
+		b.goTo(b.step - 1)
+		return b, nil
+	case "enter", "pgdown":
+		if b.step < len(wizardSteps)-1 {
+			if err := b.form.Validate(); err != nil {
+				b.status = "Validation: " + err.Error()
+				return b, nil
+			}
+			b.status = ""
+			b.goTo(b.step + 1)
+			return b, nil
+		}
+		if km.String() != "enter" {
+			return b, nil
+		}
+		if err := b.Validate(); err != nil {
+			b.status = "Validation: " + err.Error()
+			return b, nil
+		}
+		if b.RunWith != nil {
+			b.RunWith(b.Args())
+		}
+		return b, nil
+	}
+	return b, b.update(msg)
+}
+
+func (b *AssetCreateBuilder) View() string {
+	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#cba6f7"))
+	faint := lipgloss.NewStyle().Faint(true)
+
+	var pages []string
+	for i, name := range wizardSteps {
+		if i == b.step {
+			name = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#ef9f76")).Render(name)
+		} else {
+			name = faint.Render(name)
+		}
+		pages = append(pages, name)
+	}
+	next := "Enter: Next page"
+	if b.step == len(wizardSteps)-1 {
+		next = "Enter: Review and create"
+	}
+	left := []string{
+		title.Render(b.title),
+		strings.Join(pages, faint.Render(" › ")),
+		"",
+		b.form.Render(36),
+		faint.Render(next + " | PgUp: Back"),
+	}
+	leftPanel := lipgloss.NewStyle().
+		Width(44).
+		Padding(1).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#89b4fa")).
+		Render(stringsJoin(left))
+
+	rightPanel := lipgloss.NewStyle().
+		Width(44).
+		Padding(1).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#a6e3a1")).
+		Render(stringsJoin(b.renderSummary()))
+
+	return lipgloss.JoinHorizontal(lipgloss.Top, leftPanel, "  ", rightPanel)
+}
+
+// renderSummary shows the asset as wallets and explorers will, with what
+// deserves a second look.
+func (b *AssetCreateBuilder) renderSummary() []string {
+	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#a6e3a1"))
+	key := lipgloss.NewStyle().Bold(true).Width(10)
+	faint := lipgloss.NewStyle().Faint(true)
+	warn := lipgloss.NewStyle().Foreground(lipgloss.Color("#f9e2af"))
+
+	lines := []string{title.Render("What holders will see"), ""}
+	row := func(k, v string) { lines = append(lines, key.Render(k)+v) }
+	orDash := func(v string) string {
+		if v == "" {
+			return faint.Render("—")
+		}
+		return v
+	}
+
+	a, _ := b.asset("")
+	unit := a.UnitName
+	row("Name", orDash(a.Name))
+	row("Unit", orDash(unit))
+	row("Standard", b.value("standard"))
+	if total, err := b.total(); err == nil {
+		row("Supply", strings.TrimSpace(total.Decimal(a.Decimals)+" "+unit))
+		if a.Decimals > 0 {
+			row("Smallest", strings.TrimSpace(algo.Amount(1).Decimal(a.Decimals)+" "+unit))
+		}
+	} else {
+		row("Supply", faint.Render("—"))
+	}
+	row("URL", orDash(a.URL))
+	if len(a.MetadataHash) > 0 {
+		row("Hash", base64.StdEncoding.EncodeToString(a.MetadataHash))
+	}
+	if len(a.Note) > 0 {
+		row("Note", fmt.Sprintf("ARC-69 metadata, %d bytes", len(a.Note)))
+	}
+	lines = append(lines, "")
+	for _, r := range assetRoles {
+		row(r.label, b.roleLabel(r.key, a))
+	}
+	if a.DefaultFrozen {
+		row("Frozen", "holdings start frozen")
+	}
+	lines = append(lines, "", faint.Render("Creating locks "+algo.Amount(algo.AssetMinBalance).Algos()+" of min balance"))
+
+	if w := b.warnings(a); len(w) > 0 {
+		lines = append(lines, "")
+		for _, s := range w {
+			lines = append(lines, warn.Render("⚠ "+s))
+		}
+	}
+	if status := strings.TrimSpace(b.status); status != "" {
+		lines = append(lines, "", title.Render("Output"), status)
+	}
+	return lines
+}
+
+// roleLabel names who holds a role of a, created by "".
+func (b *AssetCreateBuilder) roleLabel(key string, a algo.NewAsset) string {
+	addr := map[string]string{"manager": a.Manager, "reserve": a.Reserve, "freeze": a.Freeze, "clawback": a.Clawback}[key]
+	switch {
+	case key == "reserve" && b.value("standard") == standardARC19:
+		return "from the CID " + short(addr)
+	case addr == "" && b.value(key) == roleNone:
+		return "none, for good"
+	case addr == "":
+		return "you (the creator)"
+	}
+	return short(addr)
+}
+
+// warnings are what holders or the creator may not expect; nothing here
+// stops the asset from being created.
+func (b *AssetCreateBuilder) warnings(a algo.NewAsset) []string {
+	var w []string
+	standard := b.value("standard")
+	if standard == standardARC3 && a.URL != "" && a.URL != b.value("url") {
+		w = append(w, "#arc3 is added to the URL")
+	}
+	if meta := b.arc3(); meta != nil && standard != standardFungible {
+		if meta.Name != "" && meta.Name != a.Name {
+			w = append(w, fmt.Sprintf("The metadata names it %q", meta.Name))
+		}
+		if meta.UnitName != "" && meta.UnitName != a.UnitName {
+			w = append(w, fmt.Sprintf("The metadata unit name is %q", meta.UnitName))
+		}
+	}
+	if (standard == standardARC19 || standard == standardARC69) && !pureOrFractional(a) && a.Total != 0 {
+		w = append(w, "NFTs usually have a total of 1, or 10^decimals")
+	}
+	if b.value("manager") == roleNone {
+		switch standard {
+		case standardARC69:
+			w = append(w, "No manager: the metadata can never be updated")
+		default:
+			w = append(w, "No manager: the roles can never change")
+		}
+	}
+	if b.value("clawback") != roleNone {
+		w = append(w, "Clawback can take the asset from any holder")
+	}
+	if a.DefaultFrozen {
+		w = append(w, "Holders cannot move it until the freeze address unfreezes them")
+	}
+	return w
+}
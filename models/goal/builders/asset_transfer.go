@@ -2,28 +2,167 @@ package builders
 
 import (
 	"errors"
-	"strings"
-
-	goal "lazychain/models/goal/iface"
+	"fmt"
+	"strconv"
 
+	"github.com/algorand/go-algorand-sdk/v2/transaction"
+	"github.com/algorand/go-algorand-sdk/v2/types"
 	tea "github.com/charmbracelet/bubbletea"
+
+	algo "lazychain/lib"
+	"lazychain/models/goal/components"
+	goal "lazychain/models/goal/iface"
 )
 
+// AssetTransferBuilder maps to `goal asset send`.
 // Docs: https://developer.algorand.org/docs/clis/goal/asset/send/
 type AssetTransferBuilder struct {
-	// TODO: fields for: asset-id (-a), amount (-o? differs), sender (-f), receiver (-t), close-to, revocation-target, note, out, sign, etc.
-	status string
-	RunWith func(argv []string)
-}
-
-func NewAssetTransferBuilder() *AssetTransferBuilder { return &AssetTransferBuilder{} }
-func (b *AssetTransferBuilder) Title() string { return "ASA Transfer (goal asset send)" }
-func (b *AssetTransferBuilder) Init() tea.Cmd { return nil }
-func (b *AssetTransferBuilder) Validate() error { return errors.New("not implemented yet") }
-func (b *AssetTransferBuilder) Args() []string { return []string{"asset","send"} }
-func (b *AssetTransferBuilder) AfterRun(stdout, stderr string, runErr error) {
-	if runErr != nil { b.status = strings.TrimSpace(stderr); return }
-	b.status = strings.TrimSpace(stdout)
-}
-func (b *AssetTransferBuilder) Update(msg tea.Msg) (goal.Builder, tea.Cmd) { return b, nil }
-func (b *AssetTransferBuilder) View() string { return b.Title()+"\n\n"+b.status+"\n\n(coming soon)" }
+	formBuilder
+
+	// AssetUnits and AssetBalance read decimals, unit name and holdings
+	// from the network, plumbed in by host model
+	AssetUnits   func(assetID uint64) (decimals uint32, unitName string, err error)
+	AssetBalance func(address string, assetID uint64) (algo.Amount, error)
+
+	units map[uint64]assetUnits
+}
+
+type assetUnits struct {
+	decimals uint32
+	name     string
+}
+
+func NewAssetTransferBuilder() *AssetTransferBuilder {
+	b := &AssetTransferBuilder{units: map[uint64]assetUnits{}}
+	fields := []*components.FormField{
+		&components.FormField{Key: "asset", Kind: components.KindUint, Flag: "--assetid", Required: true,
+			Validators: []components.Validator{components.NonZero},
+			Field:      components.Field{Label: "Asset ID (--assetid)"}},
+		&components.FormField{Key: "from", Kind: components.KindAddress, Flag: "-f",
+			Field: components.Field{Label: "From (-f)", Hint: "address (or default)"}},
+		&components.FormField{Key: "to", Kind: components.KindAddress, Flag: "-t", Required: true,
+			Field: components.Field{Label: "To (-t)", Hint: "recipient address"}},
+		&components.FormField{Key: "amount", Flag: "-a", Required: true, Arg: b.amountArg, Preview: b.preview,
+			Field: components.Field{Label: "Amount (-a)", Hint: "2.5 (whole units), 250 base or max; 0 to yourself opts in"}},
+		&components.FormField{Key: "closeto", Kind: components.KindAddress, Flag: "--close-to",
+			Field: components.Field{Label: "Close to (--close-to)", Hint: "optional; sends the rest and opts out"}},
+		&components.FormField{Key: "clawback", Kind: components.KindAddress, Flag: "--clawback",
+			Field: components.Field{Label: "Clawback from (--clawback)", Hint: "optional; needs the clawback role"}},
+		&components.FormField{Key: "fee", Kind: components.KindUint, Flag: "--fee", Unit: "μAlgos",
+			Field: components.Field{Label: "Fee (--fee)", Hint: "optional; empty for suggested"}},
+	}
+	fields = append(fields, b.noteFields()...)
+	fields = append(fields,
+		&components.FormField{Key: "out", Kind: components.KindPath, Flag: "-o",
+			Field: components.Field{Label: "Out file (-o)", Hint: "write txn to file (optional)"}},
+		&components.FormField{Key: "sign", Kind: components.KindBool, Flag: "-s", VisibleIf: components.IsSet("out"),
+			Field: components.Field{Label: "Sign (-s)", Hint: "sign the txn written to -o"}},
+		&components.FormField{Key: "nowait", Kind: components.KindBool, Flag: "-N",
+			Field: components.Field{Label: "No Wait (-N)"}},
+	)
+	b.formBuilder = newFormBuilder("ASA Transfer (goal asset send)", []string{"asset", "send"}, fields...)
+	b.check = func(f *components.Form) error {
+		if f.Get("closeto") != "" && f.Get("clawback") != "" {
+			return errors.New("close to and clawback cannot be combined")
+		}
+		return nil
+	}
+	return b
+}
+
+// assetUnits looks the asset's decimals up once.
+func (b *AssetTransferBuilder) assetUnits(id uint64) (assetUnits, error) {
+	if u, ok := b.units[id]; ok {
+		return u, nil
+	}
+	if b.AssetUnits == nil {
+		return assetUnits{}, errors.New("asset decimals need a connected network")
+	}
+	decimals, name, err := b.AssetUnits(id)
+	if err != nil {
+		return assetUnits{}, fmt.Errorf("asset %d: %w", id, err)
+	}
+	b.units[id] = assetUnits{decimals, name}
+	return b.units[id], nil
+}
+
+// amount scales the amount field by the asset's decimals; "max" is the
+// sender's whole holding.
+func (b *AssetTransferBuilder) amount(v string) (algo.Amount, error) {
+	id, _, err := b.form.Uint("asset")
+	if err != nil || id == 0 {
+		return 0, errors.New("set the asset ID first")
+	}
+	u, err := b.assetUnits(id)
+	if err != nil {
+		return 0, err
+	}
+	a, err := algo.ParseAssetAmount(v, u.decimals, u.name)
+	if !errors.Is(err, algo.ErrMax) {
+		return a, err
+	}
+	if b.AssetBalance == nil {
+		return 0, errors.New("max needs a connected network")
+	}
+	sender := b.form.Get("clawback")
+	if sender == "" {
+		sender = b.form.Get("from")
+	}
+	return b.AssetBalance(sender, id)
+}
+
+// amountArg gives goal the amount in base units.
+func (b *AssetTransferBuilder) amountArg(v string) (string, error) {
+	a, err := b.amount(v)
+	return strconv.FormatUint(uint64(a), 10), err
+}
+
+// preview spells the amount out once the asset's decimals are known.
+func (b *AssetTransferBuilder) preview(v string) string {
+	id, _, _ := b.form.Uint("asset")
+	u, ok := b.units[id]
+	if !ok {
+		return ""
+	}
+	a, err := algo.ParseAssetAmount(v, u.decimals, u.name)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%s %s (%d base)", a.Decimal(u.decimals), u.name, uint64(a))
+}
+
+// Review builds the transfer as sent by sender, a clawback when the
+// clawback field is set.
+func (b *AssetTransferBuilder) Review(sender string, sp types.SuggestedParams) ([]types.Transaction, error) {
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+	f := b.form
+	id, _, _ := f.Uint("asset")
+	amount, err := b.amount(f.Get("amount"))
+	if err != nil {
+		return nil, err
+	}
+	note, err := b.note()
+	if err != nil {
+		return nil, err
+	}
+	sp = withParams(f, sp)
+
+	var txn types.Transaction
+	if target := f.Get("clawback"); target != "" {
+		txn, err = transaction.MakeAssetRevocationTxn(sender, target, uint64(amount), f.Get("to"), note, sp, id)
+	} else {
+		txn, err = transaction.MakeAssetTransferTxn(sender, f.Get("to"), uint64(amount), note, sp, f.Get("closeto"), id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []types.Transaction{txn}, nil
+}
+
+func (b *AssetTransferBuilder) Backends() []goal.Backend { return []goal.Backend{goal.BackendGoal} }
+
+func (b *AssetTransferBuilder) Update(msg tea.Msg) (goal.Builder, tea.Cmd) {
+	return b, b.update(msg)
+}
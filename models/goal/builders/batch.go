@@ -0,0 +1,720 @@
+package builders
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/algorand/go-algorand-sdk/v2/transaction"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	algo "lazychain/lib"
+	"lazychain/models/goal/components"
+	goal "lazychain/models/goal/iface"
+)
+
+// Batch modes: atomic groups of up to algo.MaxGroupSize, or one
+// transaction at a time.
+const (
+	batchGroups = "groups of 16"
+	batchSingle = "one by one"
+)
+
+// batchChecks bounds the account lookups run at once while validating.
+const batchChecks = 8
+
+// batchRows is how many rows the table shows at once.
+const batchRows = 10
+
+// Row statuses, as written to the result file.
+const (
+	rowPending   = ""
+	rowSent      = "sent"
+	rowConfirmed = "confirmed"
+	rowFailed    = "failed"
+	// rowPaid is a row an earlier run confirmed, skipped
+	rowPaid = "paid"
+)
+
+// batchRow is one line of the CSV.
+type batchRow struct {
+	line     int
+	receiver string
+	amount   string // as written
+	asset    uint64 // 0 for ALGO
+	note     string
+
+	base algo.Amount // in μAlgos or the asset's base units
+	err  string      // why the row cannot be sent
+
+	status  string
+	txID    string
+	round   uint64
+	failure string
+	// lastValid is the last round the row's transaction can be confirmed in
+	lastValid uint64
+}
+
+// key identifies a payment across runs, to resume from the result file:
+// what is paid to whom, not where the line is or how the amount is
+// written. Identical payments are refused, so keys are unique.
+func (r *batchRow) key() string {
+	return strings.Join([]string{r.receiver, strconv.FormatUint(r.asset, 10), strconv.FormatUint(uint64(r.base), 10), r.note}, "\x00")
+}
+
+// BatchPaymentBuilder sends ALGO and asset payments listed in a CSV of
+// recipient, amount, asset ID and note, natively in atomic groups or one
+// by one. Every row is checked first, and the outcome of each is kept in
+// a result file a later run resumes from.
+type BatchPaymentBuilder struct {
+	formBuilder
+
+	// plumbed in by host model; address "" is the active account
+	AssetUnits     func(assetID uint64) (decimals uint32, unitName string, err error)
+	AssetBalance   func(address string, assetID uint64) (algo.Amount, error)
+	AccountState   func(address string) (algo.AccountState, error)
+	OptedIn        func(address string, assetID uint64) (bool, error)
+	ConfirmedRound func(txID string) (uint64, error)
+	KnownRound     func() (uint64, error)
+
+	rows    []*batchRow
+	units   map[uint64]assetUnits
+	summary []string
+	// problem is what stops the whole batch, e.g. the sender's balance
+	problem string
+	// sending are the rows of the reviewed transactions, in order
+	sending []*batchRow
+	offset  int
+}
+
+func NewBatchPaymentBuilder() *BatchPaymentBuilder {
+	b := &BatchPaymentBuilder{units: map[uint64]assetUnits{}}
+	b.formBuilder = newFormBuilder("Batch Payments (CSV)", nil,
+		&components.FormField{Key: "csv", Kind: components.KindPath, Required: true,
+			Validators: []components.Validator{components.FileExists},
+			Field:      components.Field{Label: "CSV file", Hint: "recipient,amount,asset,note"}},
+		&components.FormField{Key: "mode", Kind: components.KindEnum, Options: []string{batchGroups, batchSingle},
+			Field: components.Field{Label: "Send as"}},
+		&components.FormField{Key: "result", Kind: components.KindPath,
+			Field: components.Field{Label: "Result file", Hint: "default <csv>.result.csv, resumed from"}},
+		&components.FormField{Key: "fee", Kind: components.KindUint, Unit: "μAlgos",
+			Field: components.Field{Label: "Fee per transaction", Hint: "optional; empty for suggested"}},
+	)
+	b.checkAsync = b.checkRows
+	return b
+}
+
+func (b *BatchPaymentBuilder) Backends() []goal.Backend { return []goal.Backend{goal.BackendNative} }
+
+// resultPath is where row outcomes are kept.
+func (b *BatchPaymentBuilder) resultPath() string {
+	if p := b.form.Get("result"); p != "" {
+		return components.ExpandHome(p)
+	}
+	return strings.TrimSuffix(components.ExpandHome(b.form.Get("csv")), filepath.Ext(b.form.Get("csv"))) + ".result.csv"
+}
+
+// checkRows reads the CSV, resumes from the result file and checks every
+// row against the network in the background, on a copy of the builder so
+// the form can change meanwhile.
+func (b *BatchPaymentBuilder) checkRows(f *components.Form) checkWork {
+	c := NewBatchPaymentBuilder()
+	c.form.SetValues(f.Values())
+	c.AssetUnits, c.AssetBalance, c.AccountState = b.AssetUnits, b.AssetBalance, b.AccountState
+	c.OptedIn, c.ConfirmedRound, c.KnownRound = b.OptedIn, b.ConfirmedRound, b.KnownRound
+	for id, u := range b.units {
+		c.units[id] = u
+	}
+	return func() func() error {
+		err := c.load()
+		if err == nil {
+			c.resume()
+			c.lookup()
+		}
+		return func() error {
+			b.offset = 0
+			if err != nil {
+				b.rows, b.summary = nil, nil
+				return err
+			}
+			b.rows, b.units, b.problem = c.rows, c.units, c.problem
+			b.summarize()
+			return b.verdict()
+		}
+	}
+}
+
+// verdict is what stops the checked rows from being sent, if anything.
+func (b *BatchPaymentBuilder) verdict() error {
+	bad := 0
+	for _, r := range b.rows {
+		if r.err != "" {
+			bad++
+		}
+	}
+	switch {
+	case b.problem != "":
+		return errors.New(b.problem)
+	case bad > 0:
+		return fmt.Errorf("%d of %d rows have problems, see the table", bad, len(b.rows))
+	case len(b.pending()) == 0:
+		return errors.New("every row was paid already, see " + b.resultPath())
+	}
+	return nil
+}
+
+// load parses the CSV; a first line starting with "recipient" is a header.
+func (b *BatchPaymentBuilder) load() error {
+	f, err := os.Open(components.ExpandHome(b.form.Get("csv")))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	rd := csv.NewReader(f)
+	rd.FieldsPerRecord = -1
+	rd.TrimLeadingSpace = true
+	rd.Comment = '#'
+
+	b.rows, b.offset = nil, 0
+	seen := map[string]int{}
+	for {
+		rec, err := rd.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("CSV: %w", err)
+		}
+		line, _ := rd.FieldPos(0)
+		if len(b.rows) == 0 && isHeader(rec) {
+			continue
+		}
+		for len(rec) < 4 {
+			rec = append(rec, "")
+		}
+		r := &batchRow{line: line, receiver: strings.TrimSpace(rec[0]), amount: strings.TrimSpace(rec[1]), note: rec[3]}
+		b.parse(r, strings.TrimSpace(rec[2]))
+		if r.err == "" {
+			if prev, ok := seen[r.key()]; ok {
+				r.err = fmt.Sprintf("same as line %d: identical payments get the same ID, tell them apart with a note", prev)
+			}
+			seen[r.key()] = r.line
+		}
+		b.rows = append(b.rows, r)
+	}
+	if len(b.rows) == 0 {
+		return errors.New("the CSV has no rows")
+	}
+	return nil
+}
+
+func isHeader(rec []string) bool {
+	switch strings.ToLower(strings.TrimSpace(rec[0])) {
+	case "recipient", "receiver", "address", "to":
+		return true
+	}
+	return false
+}
+
+// parse checks one row on its own: address checksum, asset, amount and
+// note size.
+func (b *BatchPaymentBuilder) parse(r *batchRow, asset string) {
+	if err := components.ValidAddress(r.receiver); err != nil {
+		r.err = "recipient: " + err.Error()
+		return
+	}
+	if asset != "" {
+		id, err := strconv.ParseUint(asset, 10, 64)
+		if err != nil {
+			r.err = "asset ID must be a whole number"
+			return
+		}
+		r.asset = id
+	}
+	if strings.EqualFold(r.amount, "max") {
+		r.err = "max is not supported in a batch"
+		return
+	}
+	var err error
+	if r.asset == 0 {
+		r.base, err = algo.ParseAlgos(r.amount)
+	} else {
+		var u assetUnits
+		if u, err = b.assetUnits(r.asset); err == nil {
+			r.base, err = algo.ParseAssetAmount(r.amount, u.decimals, u.name)
+		}
+	}
+	switch {
+	case err != nil:
+		r.err = err.Error()
+	case r.base == 0:
+		r.err = "amount is zero"
+	case len(r.note) > algo.MaxNoteBytes:
+		r.err = fmt.Sprintf("note is %d bytes, at most %d", len(r.note), algo.MaxNoteBytes)
+	}
+}
+
+func (b *BatchPaymentBuilder) assetUnits(id uint64) (assetUnits, error) {
+	if u, ok := b.units[id]; ok {
+		return u, nil
+	}
+	if b.AssetUnits == nil {
+		return assetUnits{}, errors.New("asset decimals need a connected network")
+	}
+	decimals, name, err := b.AssetUnits(id)
+	if err != nil {
+		return assetUnits{}, fmt.Errorf("asset %d: %w", id, err)
+	}
+	b.units[id] = assetUnits{decimals, name}
+	return b.units[id], nil
+}
+
+// resume marks the rows an earlier run confirmed as paid, and looks up
+// the ones it sent without seeing them confirmed.
+func (b *BatchPaymentBuilder) resume() {
+	prev, err := readResults(b.resultPath())
+	if err != nil {
+		return
+	}
+	for _, r := range b.rows {
+		p, ok := prev[r.key()]
+		if !ok || r.err != "" {
+			continue
+		}
+		switch p.status {
+		case rowConfirmed, rowPaid:
+			r.status, r.txID, r.round = rowPaid, p.txID, p.round
+		case rowSent:
+			r.txID = p.txID
+			round, err := uint64(0), errors.New("needs a connected network")
+			if b.ConfirmedRound != nil {
+				round, err = b.ConfirmedRound(p.txID)
+			}
+			switch {
+			case err == nil && round > 0:
+				r.status, r.round = rowPaid, round
+			case err == nil:
+				r.err = "sent earlier as " + short(p.txID) + ", still pending: validate again later"
+			case errors.Is(err, algo.ErrNotFound):
+				b.expired(r, p)
+			default:
+				r.err = "sent earlier as " + short(p.txID) + ": " + err.Error()
+			}
+		}
+	}
+}
+
+// expired decides on a row sent earlier as p that is not on chain: once
+// the chain is past its last valid round it can never be, and the row is
+// sent again; until then it may still be confirmed.
+func (b *BatchPaymentBuilder) expired(r, p *batchRow) {
+	sent := "sent earlier as " + short(p.txID)
+	if p.lastValid == 0 {
+		r.err = sent + " but not on chain, and the result file does not say until when it is valid"
+		return
+	}
+	round, err := uint64(0), errors.New("needs a connected network")
+	if b.KnownRound != nil {
+		round, err = b.KnownRound()
+	}
+	switch {
+	case err != nil:
+		r.err = sent + ": " + err.Error()
+	case round > p.lastValid:
+		r.txID = ""
+	default:
+		r.err = fmt.Sprintf("%s, not on chain yet: valid until round %d, now %d, validate again later", sent, p.lastValid, round)
+	}
+}
+
+// pending are the rows still to send.
+func (b *BatchPaymentBuilder) pending() []*batchRow {
+	var out []*batchRow
+	for _, r := range b.rows {
+		if r.err == "" && r.status != rowPaid {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// fee is the fee per transaction assumed by the checks.
+func (b *BatchPaymentBuilder) fee() uint64 {
+	if fee, ok, _ := b.form.Uint("fee"); ok {
+		return fee
+	}
+	return transaction.MinTxnFee
+}
+
+// lookup checks every pending row on the network: receivers opted in
+// to their asset, new accounts funded with at least the min balance, and
+// the sender holding enough of everything.
+func (b *BatchPaymentBuilder) lookup() {
+	b.problem = ""
+	if b.AccountState == nil || b.OptedIn == nil || b.AssetBalance == nil {
+		b.problem = "batch checks need a connected network"
+		return
+	}
+	rows := b.pending()
+
+	// what each receiver gets in ALGO, a new account needs the min balance
+	funding := map[string]uint64{}
+	for _, r := range rows {
+		if r.asset == 0 {
+			funding[r.receiver] += uint64(r.base)
+		}
+	}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, batchChecks)
+	for _, r := range rows {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(r *batchRow) {
+			defer func() { <-sem; wg.Done() }()
+			var msg string
+			if r.asset != 0 {
+				ok, err := b.OptedIn(r.receiver, r.asset)
+				switch {
+				case err != nil:
+					msg = err.Error()
+				case !ok:
+					msg = fmt.Sprintf("recipient is not opted in to asset %d", r.asset)
+				}
+			} else {
+				st, err := b.AccountState(r.receiver)
+				switch {
+				case err != nil:
+					msg = err.Error()
+				case st.Balance+funding[r.receiver] < algo.MinAccountBalance:
+					msg = "new account: send at least " + algo.Amount(algo.MinAccountBalance).Algos()
+				}
+			}
+			mu.Lock()
+			r.err = msg
+			mu.Unlock()
+		}(r)
+	}
+	wg.Wait()
+
+	algos, assets := totals(rows)
+	fees := b.fee() * uint64(len(rows))
+	st, err := b.AccountState("")
+	if err != nil {
+		b.problem = "sender: " + err.Error()
+		return
+	}
+	if _, err := algo.MaxSpendable(st.Balance, st.MinBalance, uint64(algos)+fees); err != nil {
+		b.problem = fmt.Sprintf("sender cannot pay %s plus %s of fees: %v", algos.Algos(), algo.Amount(fees).Algos(), err)
+		return
+	}
+	for id, total := range assets {
+		held, err := b.AssetBalance("", id)
+		if err != nil {
+			b.problem = fmt.Sprintf("sender, asset %d: %v", id, err)
+			return
+		}
+		if held < total {
+			u := b.units[id]
+			b.problem = fmt.Sprintf("sender holds %s %s of asset %d, the batch sends %s",
+				held.Decimal(u.decimals), u.name, id, total.Decimal(u.decimals))
+			return
+		}
+	}
+}
+
+// totals sums the ALGO and, by asset, the base units of rows.
+func totals(rows []*batchRow) (algos algo.Amount, assets map[uint64]algo.Amount) {
+	assets = map[uint64]algo.Amount{}
+	for _, r := range rows {
+		if r.asset == 0 {
+			algos += r.base
+		} else {
+			assets[r.asset] += r.base
+		}
+	}
+	return algos, assets
+}
+
+// summarize counts the rows and totals what is left to send.
+func (b *BatchPaymentBuilder) summarize() {
+	var bad, paid int
+	for _, r := range b.rows {
+		switch {
+		case r.err != "":
+			bad++
+		case r.status == rowPaid:
+			paid++
+		}
+	}
+	rows := b.pending()
+	algos, assets := totals(rows)
+	b.summary = []string{
+		fmt.Sprintf("%d rows: %d to send, %d paid earlier, %d with problems", len(b.rows), len(rows), paid, bad),
+		"",
+		"Total ALGO   " + algos.Algos(),
+	}
+	ids := make([]uint64, 0, len(assets))
+	for id := range assets {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, id := range ids {
+		u, total := b.units[id], assets[id]
+		b.summary = append(b.summary, fmt.Sprintf("Asset %-6d %s %s", id, total.Decimal(u.decimals), u.name))
+	}
+	b.summary = append(b.summary, fmt.Sprintf("Fees         ~%s", algo.Amount(b.fee()*uint64(len(rows))).Algos()))
+	if b.form.Get("mode") == batchSingle {
+		b.summary = append(b.summary, fmt.Sprintf("%d transactions one by one", len(rows)))
+	} else {
+		groups := (len(rows) + algo.MaxGroupSize - 1) / algo.MaxGroupSize
+		b.summary = append(b.summary, fmt.Sprintf("%d atomic group(s) of up to %d", groups, algo.MaxGroupSize))
+	}
+}
+
+// Transactions builds one payment or asset transfer per pending row,
+// sent by sender, as last validated.
+func (b *BatchPaymentBuilder) Transactions(sender string, sp types.SuggestedParams) ([]types.Transaction, error) {
+	rows := b.pending()
+	if len(b.rows) == 0 || len(rows) == 0 {
+		return nil, errors.New("nothing to send, validate the CSV first")
+	}
+	if fee, ok, _ := b.form.Uint("fee"); ok {
+		sp.FlatFee = true
+		sp.Fee = types.MicroAlgos(fee)
+	}
+	txns := make([]types.Transaction, 0, len(rows))
+	for _, r := range rows {
+		var txn types.Transaction
+		var err error
+		if r.asset == 0 {
+			txn, err = transaction.MakePaymentTxn(sender, r.receiver, uint64(r.base), []byte(r.note), "", sp)
+		} else {
+			txn, err = transaction.MakeAssetTransferTxn(sender, r.receiver, uint64(r.base), []byte(r.note), sp, "", r.asset)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", r.line, err)
+		}
+		r.lastValid = uint64(txn.LastValid)
+		txns = append(txns, txn)
+	}
+	b.sending = rows
+	return txns, nil
+}
+
+// batchSize is how many transactions go out together.
+func (b *BatchPaymentBuilder) batchSize() int {
+	if b.form.Get("mode") == batchSingle {
+		return 1
+	}
+	return algo.MaxGroupSize
+}
+
+// Batches groups the transactions by 16, or sends them one by one.
+func (b *BatchPaymentBuilder) Batches(txns []types.Transaction) [][]types.Transaction {
+	size := b.batchSize()
+	var out [][]types.Transaction
+	for len(txns) > size {
+		out = append(out, txns[:size:size])
+		txns = txns[size:]
+	}
+	return append(out, txns)
+}
+
+// BatchSending writes the rows of batch i to the result file as sent,
+// with the IDs they are about to have: should the app stop before the
+// confirmation, a later run looks them up instead of paying again.
+func (b *BatchPaymentBuilder) BatchSending(i int, txIDs []string) error {
+	size := b.batchSize()
+	for j := 0; j < size && i*size+j < len(b.sending); j++ {
+		r := b.sending[i*size+j]
+		r.status, r.txID, r.failure = rowSent, txIDs[j], ""
+	}
+	return writeResults(b.resultPath(), b.rows)
+}
+
+// BatchDone records how batch i went and rewrites the result file, so a
+// run cut short resumes where it stopped. Rows stay sent unless the node
+// rejected them: a later run looks them up on chain before paying again.
+func (b *BatchPaymentBuilder) BatchDone(i int, txIDs []string, round uint64, rejected bool, err error) tea.Cmd {
+	size := b.batchSize()
+	for j := 0; j < size && i*size+j < len(b.sending); j++ {
+		r := b.sending[i*size+j]
+		r.failure = ""
+		switch {
+		case rejected:
+			r.status, r.txID = rowFailed, ""
+			if err != nil {
+				r.failure = err.Error()
+			}
+		case err != nil:
+			r.status, r.txID, r.failure = rowSent, txIDs[j], err.Error()
+		default:
+			r.status, r.txID, r.round = rowConfirmed, txIDs[j], round
+		}
+	}
+	if werr := writeResults(b.resultPath(), b.rows); werr != nil {
+		b.status = "Error: " + werr.Error()
+		return nil
+	}
+	var done, failed int
+	for _, r := range b.sending {
+		switch r.status {
+		case rowConfirmed, rowSent:
+			done++
+		case rowFailed:
+			failed++
+		}
+	}
+	b.status = fmt.Sprintf("%d of %d sent, %d failed\nResults in %s", done, len(b.sending), failed, b.resultPath())
+	return nil
+}
+
+// resultHeader are the columns of the result file; last_valid came later
+// and may be missing.
+var resultHeader = []string{"line", "recipient", "amount", "base", "asset", "note", "status", "txid", "round", "error", "last_valid"}
+
+// writeResults replaces path with the outcome of every row.
+func writeResults(path string, rows []*batchRow) error {
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(f)
+	_ = w.Write(resultHeader)
+	for _, r := range rows {
+		status, failure, lastValid := r.status, r.failure, ""
+		if r.err != "" {
+			status, failure = rowFailed, r.err
+		}
+		if r.txID != "" {
+			lastValid = strconv.FormatUint(r.lastValid, 10)
+		}
+		_ = w.Write([]string{strconv.Itoa(r.line), r.receiver, r.amount, strconv.FormatUint(uint64(r.base), 10), strconv.FormatUint(r.asset, 10), r.note,
+			status, r.txID, strconv.FormatUint(r.round, 10), failure, lastValid})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// readResults reads a result file by row key.
+func readResults(path string) (map[string]*batchRow, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	recs, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]*batchRow{}
+	for i, rec := range recs {
+		if i == 0 || len(rec) < len(resultHeader)-1 {
+			continue
+		}
+		line, _ := strconv.Atoi(rec[0])
+		base, _ := strconv.ParseUint(rec[3], 10, 64)
+		asset, _ := strconv.ParseUint(rec[4], 10, 64)
+		round, _ := strconv.ParseUint(rec[8], 10, 64)
+		r := &batchRow{line: line, receiver: rec[1], amount: rec[2], base: algo.Amount(base), asset: asset, note: rec[5],
+			status: rec[6], txID: rec[7], round: round}
+		if len(rec) >= len(resultHeader) {
+			r.lastValid, _ = strconv.ParseUint(rec[10], 10, 64)
+		}
+		out[r.key()] = r
+	}
+	return out, nil
+}
+
+func short(s string) string {
+	if len(s) > 12 {
+		return s[:6] + "..." + s[len(s)-4:]
+	}
+	return s
+}
+
+func (b *BatchPaymentBuilder) Update(msg tea.Msg) (goal.Builder, tea.Cmd) {
+	if km, ok := msg.(tea.KeyMsg); ok {
+		switch km.String() {
+		case "pgup":
+			b.offset = max(0, b.offset-batchRows)
+			return b, nil
+		case "pgdown":
+			if b.offset+batchRows < len(b.rows) {
+				b.offset += batchRows
+			}
+			return b, nil
+		}
+	}
+	return b, b.update(msg)
+}
+
+func (b *BatchPaymentBuilder) View() string {
+	top := b.formBuilder.View()
+	if len(b.rows) == 0 {
+		return top
+	}
+	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#cba6f7"))
+	faint := lipgloss.NewStyle().Faint(true)
+	bad := lipgloss.NewStyle().Foreground(lipgloss.Color("#f38ba8"))
+	good := lipgloss.NewStyle().Foreground(lipgloss.Color("#a6e3a1"))
+
+	lines := []string{title.Render("Preview"), ""}
+	lines = append(lines, b.summary...)
+	lines = append(lines, "", faint.Render(fmt.Sprintf("%-5s %-14s %-22s %s", "Line", "Recipient", "Amount", "Status")))
+	end := min(b.offset+batchRows, len(b.rows))
+	for _, r := range b.rows[b.offset:end] {
+		amount := r.amount
+		if r.err == "" {
+			if r.asset == 0 {
+				amount = r.base.Algos()
+			} else {
+				u := b.units[r.asset]
+				amount = r.base.Decimal(u.decimals) + " " + u.name
+			}
+		}
+		status := r.status
+		switch {
+		case r.err != "":
+			status = bad.Render("✗ " + r.err)
+		case r.status == rowFailed:
+			status = bad.Render("failed: " + r.failure)
+		case r.status == rowConfirmed || r.status == rowPaid:
+			status = good.Render(fmt.Sprintf("%s in round %d", r.status, r.round))
+		case r.status == rowSent:
+			status = "sent " + short(r.txID) + ", " + r.failure
+		case status == rowPending:
+			status = "ready"
+		}
+		lines = append(lines, fmt.Sprintf("%-5d %-14s %-22s %s", r.line, short(r.receiver), amount, status))
+	}
+	if len(b.rows) > batchRows {
+		lines = append(lines, "", faint.Render(fmt.Sprintf("Rows %d-%d of %d | PgUp/PgDn: Scroll", b.offset+1, end, len(b.rows))))
+	}
+	table := lipgloss.NewStyle().
+		Width(92).
+		Padding(1).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#f9e2af")).
+		Render(stringsJoin(lines))
+	return lipgloss.JoinVertical(lipgloss.Left, top, table)
+}
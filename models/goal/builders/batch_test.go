@@ -0,0 +1,210 @@
+package builders
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/v2/types"
+
+	algo "lazychain/lib"
+)
+
+// batchFromCSV loads content as the CSV of a new batch builder.
+func batchFromCSV(t *testing.T, content string) *BatchPaymentBuilder {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "pay.csv")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	b := NewBatchPaymentBuilder()
+	b.form.SetValues(map[string]string{"csv": path})
+	b.AssetUnits = func(id uint64) (uint32, string, error) {
+		if id == 31566704 {
+			return 6, "USDC", nil
+		}
+		return 0, "", errors.New("not found")
+	}
+	return b
+}
+
+func TestBatchLoad(t *testing.T) {
+	alice := types.Address{1}.String()
+	bob := types.Address{2}.String()
+	bad := alice[:len(alice)-1] + "A"
+	if bad == alice {
+		bad = alice[:len(alice)-1] + "B"
+	}
+
+	b := batchFromCSV(t, strings.Join([]string{
+		"recipient,amount,asset,note",
+		"# comment",
+		alice + ",1.5 ALGO,,rent",
+		bob + ", 2.5 ,31566704,",
+		bob + ",2.5 USDC,31566704,",
+		bad + ",1 ALGO,,",
+		alice + ",1.5,,",
+		alice + ",max,,",
+		alice + ",0 ALGO,,",
+		alice + ",1 ALGO,x,",
+		alice + ",1 ALGO,42,",
+		alice + ",\"1,5 ALGO\",,",
+		bob + ",1500000 µ,,\"quoted, note\"",
+	}, "\n"))
+	if err := b.load(); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	want := []struct {
+		line int
+		base uint64
+		err  string // substring, "" for a valid row
+	}{
+		{3, 1500000, ""},
+		{4, 2500000, ""},
+		{5, 2500000, "same as line 4"},
+		{6, 0, "recipient"},
+		{7, 0, "add a unit"},
+		{8, 0, "max is not supported"},
+		{9, 0, "amount is zero"},
+		{10, 0, "whole number"},
+		{11, 0, "asset 42"},
+		{12, 0, "unknown unit"},
+		{13, 1500000, ""},
+	}
+	if len(b.rows) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(b.rows), len(want))
+	}
+	for i, w := range want {
+		r := b.rows[i]
+		if r.line != w.line {
+			t.Errorf("row %d: line = %d, want %d", i, r.line, w.line)
+		}
+		switch {
+		case w.err == "" && r.err != "":
+			t.Errorf("line %d: unexpected error %q", r.line, r.err)
+		case w.err != "" && !strings.Contains(r.err, w.err):
+			t.Errorf("line %d: error = %q, want %q", r.line, r.err, w.err)
+		case w.err == "" && uint64(r.base) != w.base:
+			t.Errorf("line %d: base = %d, want %d", r.line, r.base, w.base)
+		}
+	}
+	if b.rows[10].note != "quoted, note" {
+		t.Errorf("note = %q", b.rows[10].note)
+	}
+}
+
+func TestBatchLoadEmpty(t *testing.T) {
+	b := batchFromCSV(t, "recipient,amount,asset,note\n")
+	if err := b.load(); err == nil {
+		t.Error("load of a CSV without rows: want an error")
+	}
+}
+
+// TestBatchResume checks a later run finds the rows an earlier one paid
+// by payment, even once the CSV lines moved and the amounts are written
+// in another unit.
+func TestBatchResume(t *testing.T) {
+	alice := types.Address{1}.String()
+	bob := types.Address{2}.String()
+
+	first := batchFromCSV(t, alice+",1.5 ALGO,,rent\n"+bob+",2 ALGO,,\n")
+	if err := first.load(); err != nil {
+		t.Fatal(err)
+	}
+	first.rows[0].status, first.rows[0].txID, first.rows[0].round = rowConfirmed, "TXA", 100
+	first.rows[1].status, first.rows[1].txID = rowSent, "TXB"
+	if err := writeResults(first.resultPath(), first.rows); err != nil {
+		t.Fatal(err)
+	}
+
+	second := batchFromCSV(t, "# moved\n"+bob+",2000000 µ,,\n"+alice+",1.5 ALGO,,rent\n"+alice+",1.5 ALGO,,other\n")
+	second.form.SetValues(map[string]string{"csv": second.form.Get("csv"), "result": first.resultPath()})
+	second.ConfirmedRound = func(txID string) (uint64, error) {
+		if txID == "TXB" {
+			return 101, nil
+		}
+		return 0, errors.New("unexpected lookup of " + txID)
+	}
+	if err := second.load(); err != nil {
+		t.Fatal(err)
+	}
+	second.resume()
+
+	for _, r := range second.rows {
+		if r.err != "" {
+			t.Errorf("line %d: unexpected error %q", r.line, r.err)
+		}
+	}
+	if r := second.rows[0]; r.status != rowPaid || r.txID != "TXB" || r.round != 101 {
+		t.Errorf("bob: %+v, want paid by TXB in round 101", *r)
+	}
+	if r := second.rows[1]; r.status != rowPaid || r.txID != "TXA" || r.round != 100 {
+		t.Errorf("alice rent: %+v, want paid by TXA in round 100", *r)
+	}
+	if r := second.rows[2]; r.status != rowPending {
+		t.Errorf("alice other: status %q, want pending", r.status)
+	}
+	if p := second.pending(); len(p) != 1 || p[0] != second.rows[2] {
+		t.Errorf("pending = %d rows, want only the new payment", len(p))
+	}
+}
+
+// TestBatchResumeExpired checks a row sent earlier but not on chain is
+// sent again only once the chain is past its last valid round.
+func TestBatchResumeExpired(t *testing.T) {
+	alice := types.Address{1}.String()
+	bob := types.Address{2}.String()
+	csv := alice + ",1 ALGO,,\n" + bob + ",2 ALGO,,\n"
+
+	first := batchFromCSV(t, csv)
+	if err := first.load(); err != nil {
+		t.Fatal(err)
+	}
+	first.rows[0].status, first.rows[0].txID, first.rows[0].lastValid = rowSent, "TXA", 1000
+	first.rows[1].status, first.rows[1].txID, first.rows[1].lastValid = rowSent, "TXB", 2000
+	if err := writeResults(first.resultPath(), first.rows); err != nil {
+		t.Fatal(err)
+	}
+
+	second := batchFromCSV(t, csv)
+	second.form.SetValues(map[string]string{"csv": second.form.Get("csv"), "result": first.resultPath()})
+	second.ConfirmedRound = func(string) (uint64, error) { return 0, algo.ErrNotFound }
+	second.KnownRound = func() (uint64, error) { return 1500, nil }
+	if err := second.load(); err != nil {
+		t.Fatal(err)
+	}
+	second.resume()
+
+	if r := second.rows[0]; r.err != "" || r.status != rowPending || r.txID != "" {
+		t.Errorf("alice, expired at 1000: %+v, want pending again", *r)
+	}
+	if r := second.rows[1]; !strings.Contains(r.err, "valid until round 2000") {
+		t.Errorf("bob, valid until 2000: error = %q", r.err)
+	}
+}
+
+// TestBatchCheckRows checks the lookups leave the builder alone until
+// their outcome is applied on the update goroutine.
+func TestBatchCheckRows(t *testing.T) {
+	alice := types.Address{1}.String()
+	b := batchFromCSV(t, alice+",1 ALGO,,\n")
+	b.AccountState = func(string) (algo.AccountState, error) {
+		return algo.AccountState{Balance: 10_000_000, MinBalance: 100_000}, nil
+	}
+	b.OptedIn = func(string, uint64) (bool, error) { return true, nil }
+	b.AssetBalance = func(string, uint64) (algo.Amount, error) { return 0, nil }
+
+	apply := b.checkRows(b.form)()
+	if b.rows != nil {
+		t.Fatal("rows set before the outcome was applied")
+	}
+	if err := apply(); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if len(b.rows) != 1 || len(b.pending()) != 1 || b.summary == nil {
+		t.Errorf("rows = %d, pending = %d, summary = %q", len(b.rows), len(b.pending()), b.summary)
+	}
+}
@@ -0,0 +1,181 @@
+package builders
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/algorand/go-algorand-sdk/v2/types"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"lazychain/models/goal/components"
+	goal "lazychain/models/goal/iface"
+)
+
+// formBuilder implements the Builder plumbing for builders declared as a
+// components.Form: embedding it leaves a builder with its fields, Title
+// and Backends, plus Transactions for the native backend.
+type formBuilder struct {
+	title  string
+	form   *components.Form
+	status string
+
+	// check adds cross-field rules to the fields' own validation
+	check func(f *components.Form) error
+	// checkAsync is for rules that need the network: it reads the form on
+	// the update goroutine and returns the lookups to run in the
+	// background, whose outcome comes back as goal.CheckedMsg
+	checkAsync func(f *components.Form) checkWork
+	checking   bool
+
+	// plumbed in by host model:
+	RunWith func(argv []string)
+}
+
+// checkWork runs in the background and returns apply, which stores its
+// outcome on the update goroutine and returns the validation error.
+type checkWork func() (apply func() error)
+
+func newFormBuilder(title string, command []string, fields ...*components.FormField) formBuilder {
+	return formBuilder{title: title, form: components.NewForm(command, fields...)}
+}
+
+func (b *formBuilder) Title() string  { return b.title }
+func (b *formBuilder) Init() tea.Cmd  { return nil }
+func (b *formBuilder) Args() []string { return b.form.Args() }
+
+func (b *formBuilder) Values() map[string]string { return b.form.Values() }
+
+func (b *formBuilder) SetValues(values map[string]string) {
+	b.form.SetValues(values)
+	b.status = ""
+}
+
+func (b *formBuilder) Validate() error {
+	if err := b.form.Validate(); err != nil {
+		return err
+	}
+	if b.check != nil {
+		return b.check(b.form)
+	}
+	return nil
+}
+
+func (b *formBuilder) AfterRun(stdout, stderr string, runErr error) {
+	if runErr != nil {
+		b.status = fmt.Sprintf("Error: %v\n%s", runErr, strings.TrimSpace(stderr))
+		return
+	}
+	b.status = strings.TrimSpace(stdout)
+}
+
+// update feeds keys to the form and runs the command on enter, once
+// checkAsync is done when set. Builders call it from Update, which must
+// return the embedding builder.
+func (b *formBuilder) update(msg tea.Msg) tea.Cmd {
+	switch t := msg.(type) {
+	case goal.CheckedMsg:
+		b.checked(t)
+		return nil
+	case goal.LoadedMsg:
+		if t.Owner == b {
+			t.Apply()
+		}
+		return nil
+	}
+	km, ok := msg.(tea.KeyMsg)
+	if !ok || b.checking || !b.form.Update(km) {
+		return nil
+	}
+	if err := b.Validate(); err != nil {
+		b.status = "Validation: " + err.Error()
+		return nil
+	}
+	if b.checkAsync != nil {
+		work := b.checkAsync(b.form)
+		b.checking, b.status = true, "Checking..."
+		return func() tea.Msg { return goal.CheckedMsg{Owner: b, Apply: work()} }
+	}
+	b.run()
+	return nil
+}
+
+// checked applies the outcome of checkAsync, and runs the command when
+// the builder is still the one on screen.
+func (b *formBuilder) checked(msg goal.CheckedMsg) {
+	if msg.Owner != b {
+		return
+	}
+	b.checking = false
+	if err := msg.Apply(); err != nil {
+		b.status = "Validation: " + err.Error()
+		return
+	}
+	if !msg.Current {
+		b.status = "Checked, press enter to check again and send"
+		return
+	}
+	b.status = ""
+	b.run()
+}
+
+func (b *formBuilder) run() {
+	if b.RunWith != nil {
+		b.RunWith(b.Args())
+	}
+}
+
+func (b *formBuilder) View() string {
+	left := []string{
+		lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#cba6f7")).Render(b.title),
+		"",
+		b.form.Render(36),
+	}
+	leftPanel := lipgloss.NewStyle().
+		Width(44).
+		Padding(1).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#89b4fa")).
+		Render(stringsJoin(left))
+
+	right := []string{
+		lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#a6e3a1")).Render("Output"),
+		"",
+		strings.TrimSpace(b.status),
+	}
+	rightPanel := lipgloss.NewStyle().
+		Width(44).
+		Padding(1).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#a6e3a1")).
+		Render(stringsJoin(right))
+
+	return lipgloss.JoinHorizontal(lipgloss.Top, leftPanel, "  ", rightPanel)
+}
+
+// withParams applies the fee and validity fields a form may have to the
+// suggested params.
+func withParams(f *components.Form, sp types.SuggestedParams) types.SuggestedParams {
+	if fee, ok, _ := f.Uint("fee"); ok {
+		sp.FlatFee = true
+		sp.Fee = types.MicroAlgos(fee)
+	}
+	if r, ok, _ := f.Uint("firstvalid"); ok {
+		sp.FirstRoundValid = types.Round(r)
+	}
+	if r, ok, _ := f.Uint("lastvalid"); ok {
+		sp.LastRoundValid = types.Round(r)
+	}
+	return sp
+}
+
+func stringsJoin(ss []string) string {
+	var b strings.Builder
+	for i, s := range ss {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(s)
+	}
+	return b.String()
+}
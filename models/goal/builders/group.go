@@ -16,6 +16,7 @@ type GroupBuilder struct {
 
 func NewGroupBuilder() *GroupBuilder { return &GroupBuilder{} }
 func (b *GroupBuilder) Title() string { return "Atomic Group (goal clerk group)" }
+func (b *GroupBuilder) Backends() []goal.Backend { return []goal.Backend{goal.BackendGoal} }
 func (b *GroupBuilder) Init() tea.Cmd { return nil }
 func (b *GroupBuilder) Validate() error { return nil }
 func (b *GroupBuilder) Args() []string { return []string{"clerk","group","-i","group.json","-o","group.txn"} }
@@ -18,6 +18,7 @@ type InspectSimBuilder struct {
 
 func NewInspectSimBuilder() *InspectSimBuilder { return &InspectSimBuilder{} }
 func (b *InspectSimBuilder) Title() string { return "Inspect / Simulate" }
+func (b *InspectSimBuilder) Backends() []goal.Backend { return []goal.Backend{goal.BackendGoal} }
 func (b *InspectSimBuilder) Init() tea.Cmd { return nil }
 func (b *InspectSimBuilder) Validate() error { return nil }
 func (b *InspectSimBuilder) Args() []string { return []string{"clerk","inspect","out.stxn"} }
@@ -0,0 +1,48 @@
+package builders
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	algo "lazychain/lib"
+	"lazychain/models/goal/components"
+)
+
+// noteFields declares a note with its input format. goal gets it as
+// --noteb64, so every format reaches the chain byte for byte.
+func (b *formBuilder) noteFields() []*components.FormField {
+	return []*components.FormField{
+		{Key: "noteformat", Kind: components.KindEnum, Options: algo.NoteFormats,
+			Field: components.Field{Label: "Note format", Hint: "arc2 is dapp:<m|j|b|u>data"}},
+		{Key: "note", Kind: components.KindNote, Flag: "--noteb64",
+			Validators: []components.Validator{b.validNote}, Arg: b.noteArg, Preview: b.previewNote,
+			Field: components.Field{Label: "Note (--noteb64)", Hint: "optional, up to 1KB"}},
+	}
+}
+
+// note is the encoded note, nil when empty.
+func (b *formBuilder) note() ([]byte, error) {
+	v := b.form.Get("note")
+	if v == "" {
+		return nil, nil
+	}
+	return algo.EncodeNote(b.form.Get("noteformat"), v)
+}
+
+func (b *formBuilder) validNote(string) error {
+	_, err := b.note()
+	return err
+}
+
+func (b *formBuilder) noteArg(string) (string, error) {
+	note, err := b.note()
+	return base64.StdEncoding.EncodeToString(note), err
+}
+
+func (b *formBuilder) previewNote(string) string {
+	note, err := b.note()
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d bytes", len(note), algo.MaxNoteBytes)
+}
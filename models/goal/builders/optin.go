@@ -0,0 +1,469 @@
+package builders
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/algorand/go-algorand-sdk/v2/transaction"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	algo "lazychain/lib"
+	"lazychain/models/goal/components"
+	goal "lazychain/models/goal/iface"
+)
+
+// Actions of the opt-in builder.
+const (
+	actionOptOut = "opt out of selected"
+	actionOptIn  = "opt in to a list"
+)
+
+// optInItem is an asset of a pasted list.
+type optInItem struct {
+	id   uint64
+	unit string
+	err  string
+}
+
+// AssetOptBuilder shows the active account's opt-ins with the min
+// balance each one locks, and opts out of many at once, closing what is
+// left back to each creator, or into a pasted list of asset IDs. Both go
+// out in atomic groups.
+type AssetOptBuilder struct {
+	formBuilder
+
+	// plumbed in by host model; address "" is the active account
+	Holdings     func(address string) ([]algo.Holding, error)
+	AssetUnits   func(assetID uint64) (decimals uint32, unitName string, err error)
+	AccountState func(address string) (algo.AccountState, error)
+
+	holdings []algo.Holding
+	loadErr  string
+	loading  bool
+	selected map[uint64]bool
+	cursor   int
+	offset   int
+
+	optIns []optInItem
+	// sending are the asset IDs of the reviewed transactions, in order
+	sending []uint64
+	// outcome of the last run, by asset ID
+	outcome map[uint64]string
+}
+
+func NewAssetOptBuilder() *AssetOptBuilder {
+	b := &AssetOptBuilder{selected: map[uint64]bool{}, outcome: map[uint64]string{}}
+	optIn := func(f *components.Form) bool { return f.Get("action") == actionOptIn }
+	b.formBuilder = newFormBuilder("Asset Opt-in / Opt-out", nil,
+		&components.FormField{Key: "action", Kind: components.KindEnum, Options: []string{actionOptOut, actionOptIn},
+			Field: components.Field{Label: "Action"}},
+		&components.FormField{Key: "ids", Kind: components.KindNote, Required: true, VisibleIf: optIn,
+			Validators: []components.Validator{validAssetIDs},
+			Field:      components.Field{Label: "Asset IDs", Hint: "paste IDs separated by spaces, commas or lines"}},
+	)
+	b.checkAsync = b.checkAssets
+	return b
+}
+
+func (b *AssetOptBuilder) Backends() []goal.Backend { return []goal.Backend{goal.BackendNative} }
+
+// parseAssetIDs reads a pasted list of asset IDs, each once.
+func parseAssetIDs(v string) ([]uint64, error) {
+	var ids []uint64
+	seen := map[uint64]bool{}
+	for _, s := range strings.FieldsFunc(v, func(r rune) bool {
+		return r == ',' || r == ';' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+	}) {
+		id, err := strconv.ParseUint(s, 10, 64)
+		if err != nil || id == 0 {
+			return nil, fmt.Errorf("%q is not an asset ID", s)
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func validAssetIDs(v string) error {
+	_, err := parseAssetIDs(v)
+	return err
+}
+
+// load reads the active account's opt-ins in the background, like the
+// node screen reads its status.
+func (b *AssetOptBuilder) load() tea.Cmd {
+	if b.Holdings == nil {
+		b.loadErr = "opt-ins need a connected network"
+		return nil
+	}
+	if b.loading {
+		return nil
+	}
+	b.loading = true
+	read := b.Holdings
+	return func() tea.Msg {
+		holdings, err := read("")
+		return goal.LoadedMsg{Owner: &b.formBuilder, Apply: func() { b.loaded(holdings, err) }}
+	}
+}
+
+// loaded keeps the opt-ins read by load, and the selection of the assets
+// still held.
+func (b *AssetOptBuilder) loaded(holdings []algo.Holding, err error) {
+	b.loading = false
+	if err != nil {
+		b.loadErr = err.Error()
+		return
+	}
+	b.loadErr = ""
+	held := map[uint64]bool{}
+	for _, h := range holdings {
+		held[h.AssetID] = true
+	}
+	for id := range b.selected {
+		if !held[id] {
+			delete(b.selected, id)
+		}
+	}
+	b.holdings = holdings
+	b.cursor = min(b.cursor, max(0, len(holdings)-1))
+}
+
+// checkAssets checks the selection, or looks every pasted asset up, in
+// the background; the opt-ins are read first when not loaded yet.
+func (b *AssetOptBuilder) checkAssets(f *components.Form) checkWork {
+	optIn := f.Get("action") == actionOptIn
+	ids, _ := parseAssetIDs(f.Get("ids"))
+	holdings := b.holdings
+	read, units, state := b.Holdings, b.AssetUnits, b.AccountState
+	return func() func() error {
+		var loadErr error
+		fetched := holdings == nil
+		if fetched {
+			if read == nil {
+				loadErr = errors.New("opt-ins need a connected network")
+			} else {
+				holdings, loadErr = read("")
+			}
+		}
+		var items []optInItem
+		var err error
+		if optIn && loadErr == nil {
+			items, err = checkOptIns(ids, holdings, units, state)
+		}
+		return func() error {
+			if fetched {
+				b.loaded(holdings, loadErr)
+			}
+			if loadErr != nil {
+				return loadErr
+			}
+			if !optIn {
+				if len(b.selected) == 0 {
+					return errors.New("select the assets to opt out of with space")
+				}
+				return nil
+			}
+			b.optIns = items
+			return err
+		}
+	}
+}
+
+// checkOptIns looks up each of ids not held yet, and checks the account
+// can afford opting in to all of them.
+func checkOptIns(ids []uint64, holdings []algo.Holding,
+	units func(uint64) (uint32, string, error), state func(string) (algo.AccountState, error)) ([]optInItem, error) {
+	held := map[uint64]bool{}
+	for _, h := range holdings {
+		held[h.AssetID] = true
+	}
+	var items []optInItem
+	bad := 0
+	for _, id := range ids {
+		item := optInItem{id: id}
+		switch {
+		case held[id]:
+			item.err = "already opted in"
+		case units == nil:
+			item.err = "needs a connected network"
+		default:
+			if _, unit, err := units(id); err != nil {
+				item.err = "not found"
+			} else {
+				item.unit = unit
+			}
+		}
+		if item.err != "" {
+			bad++
+		}
+		items = append(items, item)
+	}
+	if bad > 0 {
+		return items, fmt.Errorf("%d of %d assets cannot be opted in to, see the list", bad, len(ids))
+	}
+
+	// each opt-in raises the min balance, and costs a fee
+	need := uint64(len(ids)) * (algo.AssetMinBalance + transaction.MinTxnFee)
+	st, err := state("")
+	if err != nil {
+		return items, err
+	}
+	if st.Balance < st.MinBalance || st.Balance-st.MinBalance < need {
+		return items, fmt.Errorf("%d opt-ins need %s free above the min balance, the account has %s",
+			len(ids), algo.Amount(need).Algos(), algo.Amount(st.Balance-min(st.Balance, st.MinBalance)).Algos())
+	}
+	return items, nil
+}
+
+// Transactions builds an opt-out closing to the creator for each
+// selected asset, or an opt-in for each pasted one.
+func (b *AssetOptBuilder) Transactions(sender string, sp types.SuggestedParams) ([]types.Transaction, error) {
+	var txns []types.Transaction
+	b.sending = nil
+	b.outcome = map[uint64]string{}
+	if b.form.Get("action") == actionOptOut {
+		for _, h := range b.holdings {
+			// the creator cannot close an asset to itself, the group would fail
+			if !b.selected[h.AssetID] || h.Created || h.Creator == sender {
+				continue
+			}
+			txn, err := transaction.MakeAssetTransferTxn(sender, h.Creator, 0, nil, sp, h.Creator, h.AssetID)
+			if err != nil {
+				return nil, fmt.Errorf("asset %d: %w", h.AssetID, err)
+			}
+			txns = append(txns, txn)
+			b.sending = append(b.sending, h.AssetID)
+		}
+	} else {
+		for _, item := range b.optIns {
+			txn, err := transaction.MakeAssetAcceptanceTxn(sender, nil, sp, item.id)
+			if err != nil {
+				return nil, fmt.Errorf("asset %d: %w", item.id, err)
+			}
+			txns = append(txns, txn)
+			b.sending = append(b.sending, item.id)
+		}
+	}
+	if len(txns) == 0 {
+		return nil, errors.New("nothing to send")
+	}
+	return txns, nil
+}
+
+// Batches groups the transactions by 16.
+func (b *AssetOptBuilder) Batches(txns []types.Transaction) [][]types.Transaction {
+	var out [][]types.Transaction
+	for len(txns) > algo.MaxGroupSize {
+		out = append(out, txns[:algo.MaxGroupSize:algo.MaxGroupSize])
+		txns = txns[algo.MaxGroupSize:]
+	}
+	return append(out, txns)
+}
+
+// BatchSending has nothing to record: an opt-in sent twice is refused.
+func (b *AssetOptBuilder) BatchSending(int, []string) error { return nil }
+
+// BatchDone records the group's outcome for each of its assets, and
+// reloads the opt-ins after the last one.
+func (b *AssetOptBuilder) BatchDone(i int, txIDs []string, round uint64, rejected bool, err error) tea.Cmd {
+	from := i * algo.MaxGroupSize
+	to := min(from+algo.MaxGroupSize, len(b.sending))
+	for _, id := range b.sending[from:to] {
+		switch {
+		case rejected && err != nil:
+			b.outcome[id] = "failed: " + err.Error()
+		case rejected:
+			b.outcome[id] = "failed"
+		case err != nil:
+			b.outcome[id] = "not confirmed yet: " + err.Error()
+		default:
+			b.outcome[id] = fmt.Sprintf("done in round %d", round)
+		}
+	}
+	var done, failed int
+	for _, id := range b.sending {
+		switch o := b.outcome[id]; {
+		case strings.HasPrefix(o, "failed"):
+			failed++
+		case o != "":
+			done++
+		}
+	}
+	b.status = fmt.Sprintf("%d of %d sent, %d failed", done, len(b.sending), failed)
+	if to == len(b.sending) {
+		return b.load()
+	}
+	return nil
+}
+
+func (b *AssetOptBuilder) Update(msg tea.Msg) (goal.Builder, tea.Cmd) {
+	km, ok := msg.(tea.KeyMsg)
+	if ok && b.form.Get("action") == actionOptOut {
+		switch km.String() {
+		case "r":
+			return b, b.load()
+		case "j":
+			b.cursor = min(b.cursor+1, max(0, len(b.holdings)-1))
+			return b, nil
+		case "k":
+			b.cursor = max(b.cursor-1, 0)
+			return b, nil
+		case " ":
+			if b.cursor < len(b.holdings) {
+				b.toggle(b.holdings[b.cursor])
+			}
+			return b, nil
+		case "z":
+			for _, h := range b.holdings {
+				if h.Amount == 0 && !h.Deleted && !h.Created {
+					b.selected[h.AssetID] = true
+				}
+			}
+			return b, nil
+		case "n":
+			b.selected = map[uint64]bool{}
+			return b, nil
+		}
+	}
+	return b, b.update(msg)
+}
+
+// toggle selects h for opt-out; a deleted asset has no creator to close
+// back to, and the creator itself cannot opt out until it destroys it.
+func (b *AssetOptBuilder) toggle(h algo.Holding) {
+	switch {
+	case h.Deleted:
+		b.status = fmt.Sprintf("Asset %d was deleted, it has no creator to close back to", h.AssetID)
+	case h.Created:
+		b.status = fmt.Sprintf("Asset %d was created by this account, destroy it to opt out", h.AssetID)
+	case b.selected[h.AssetID]:
+		delete(b.selected, h.AssetID)
+	default:
+		b.selected[h.AssetID] = true
+	}
+}
+
+func (b *AssetOptBuilder) View() string {
+	top := b.formBuilder.View()
+	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#cba6f7"))
+	faint := lipgloss.NewStyle().Faint(true)
+	bad := lipgloss.NewStyle().Foreground(lipgloss.Color("#f38ba8"))
+	good := lipgloss.NewStyle().Foreground(lipgloss.Color("#a6e3a1"))
+
+	var lines []string
+	if b.form.Get("action") == actionOptIn {
+		lines = []string{title.Render("Assets to opt in to"), ""}
+		for _, item := range b.optIns {
+			status := good.Render(item.unit)
+			if item.err != "" {
+				status = bad.Render("✗ " + item.err)
+			}
+			if o := b.outcome[item.id]; o != "" {
+				status = o
+			}
+			lines = append(lines, fmt.Sprintf("%-12d %s", item.id, status))
+		}
+		if len(b.optIns) == 0 {
+			lines = append(lines, faint.Render("Paste the IDs and press enter to check them"))
+		} else {
+			lines = append(lines, "", fmt.Sprintf("Locks %s more of min balance",
+				algo.Amount(uint64(len(b.optIns))*algo.AssetMinBalance).Algos()))
+		}
+	} else {
+		lines = b.renderHoldings()
+	}
+	list := lipgloss.NewStyle().
+		Width(92).
+		Padding(1).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#f9e2af")).
+		Render(stringsJoin(lines))
+	return lipgloss.JoinVertical(lipgloss.Left, top, list)
+}
+
+func (b *AssetOptBuilder) renderHoldings() []string {
+	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#cba6f7"))
+	faint := lipgloss.NewStyle().Faint(true)
+	warn := lipgloss.NewStyle().Foreground(lipgloss.Color("#f9e2af"))
+
+	lines := []string{title.Render("Opt-ins of the active account"), ""}
+	switch {
+	case b.loading && b.holdings == nil:
+		return append(lines, faint.Render("Loading..."))
+	case b.loadErr != "":
+		return append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("#f38ba8")).Render("Error: "+b.loadErr))
+	case b.holdings == nil:
+		return append(lines, faint.Render("Press r to load them"))
+	case len(b.holdings) == 0:
+		return append(lines, faint.Render("No opt-ins"))
+	}
+
+	zero, freed := 0, uint64(0)
+	for _, h := range b.holdings {
+		if h.Amount == 0 {
+			zero++
+		}
+		if b.selected[h.AssetID] {
+			freed += algo.AssetMinBalance
+		}
+	}
+	lines = append(lines,
+		fmt.Sprintf("%d opt-ins lock %s of min balance (%s each), %d hold nothing",
+			len(b.holdings), algo.Amount(uint64(len(b.holdings))*algo.AssetMinBalance).Algos(),
+			algo.Amount(algo.AssetMinBalance).Algos(), zero),
+		fmt.Sprintf("%d selected, opting out frees %s", len(b.selected), algo.Amount(freed).Algos()),
+		"",
+		faint.Render(fmt.Sprintf("    %-11s %-9s %-20s %s", "Asset", "Unit", "Balance", "Creator")),
+	)
+
+	if b.cursor < b.offset {
+		b.offset = b.cursor
+	}
+	if b.cursor >= b.offset+batchRows {
+		b.offset = b.cursor - batchRows + 1
+	}
+	end := min(b.offset+batchRows, len(b.holdings))
+	for i, h := range b.holdings[b.offset:end] {
+		mark := "[ ]"
+		if b.selected[h.AssetID] {
+			mark = "[x]"
+		}
+		balance := h.Amount.Decimal(h.Decimals)
+		creator := short(h.Creator)
+		switch {
+		case h.Deleted:
+			balance, creator = strconv.FormatUint(uint64(h.Amount), 10), "deleted"
+		case h.Created:
+			mark, creator = " - ", "this account"
+		case h.Amount > 0 && b.selected[h.AssetID]:
+			// the balance left goes with the close-to
+			balance = warn.Render(fmt.Sprintf("%-20s", balance+" → creator"))
+		}
+		if h.Frozen {
+			creator += ", frozen"
+		}
+		if o := b.outcome[h.AssetID]; o != "" {
+			creator += " | " + o
+		}
+		line := fmt.Sprintf("%s %-11d %-9s %-20s %s", mark, h.AssetID, h.UnitName, balance, creator)
+		if b.offset+i == b.cursor {
+			line = lipgloss.NewStyle().Foreground(lipgloss.Color("#ef9f76")).Render("> ") + line
+		} else {
+			line = "  " + line
+		}
+		lines = append(lines, line)
+	}
+	reload := "r: Reload"
+	if b.loading {
+		reload = "Reloading..."
+	}
+	return append(lines, "", faint.Render(fmt.Sprintf("Rows %d-%d of %d | j/k: Move | Space: Select | z: Select empty | n: None | %s",
+		b.offset+1, end, len(b.holdings), reload)))
+}
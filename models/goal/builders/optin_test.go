@@ -0,0 +1,56 @@
+package builders
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	algo "lazychain/lib"
+)
+
+func TestCheckOptIns(t *testing.T) {
+	units := func(id uint64) (uint32, string, error) {
+		if id == 404 {
+			return 0, "", errors.New("not found")
+		}
+		return 6, "UNIT", nil
+	}
+	holdings := []algo.Holding{{AssetID: 7}}
+	// two opt-ins need 2 × (0.1 + 0.001) ALGO free
+	rich := func(string) (algo.AccountState, error) {
+		return algo.AccountState{Balance: 1_000_000, MinBalance: 100_000}, nil
+	}
+	poor := func(string) (algo.AccountState, error) {
+		return algo.AccountState{Balance: 300_000, MinBalance: 100_000}, nil
+	}
+
+	tests := []struct {
+		name  string
+		ids   []uint64
+		state func(string) (algo.AccountState, error)
+		bad   map[uint64]string
+		err   string
+	}{
+		{"affordable", []uint64{1, 2}, rich, nil, ""},
+		{"held and unknown", []uint64{7, 404, 3}, rich, map[uint64]string{7: "already opted in", 404: "not found"}, "2 of 3 assets"},
+		{"min balance", []uint64{1, 2}, poor, nil, "free above the min balance"},
+	}
+	for _, tt := range tests {
+		items, err := checkOptIns(tt.ids, holdings, units, tt.state)
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+		case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+			t.Errorf("%s: error = %v, want %q", tt.name, err, tt.err)
+		}
+		if len(items) != len(tt.ids) {
+			t.Errorf("%s: %d items, want %d", tt.name, len(items), len(tt.ids))
+			continue
+		}
+		for _, item := range items {
+			if item.err != tt.bad[item.id] {
+				t.Errorf("%s: asset %d error = %q, want %q", tt.name, item.id, item.err, tt.bad[item.id])
+			}
+		}
+	}
+}
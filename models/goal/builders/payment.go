@@ -4,11 +4,12 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
-	"strings"
 
+	"github.com/algorand/go-algorand-sdk/v2/transaction"
+	"github.com/algorand/go-algorand-sdk/v2/types"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
 
+	algo "lazychain/lib"
 	"lazychain/models/goal/components"
 	goal "lazychain/models/goal/iface"
 )
@@ -16,148 +17,111 @@ import (
 // PaymentBuilder maps to `goal clerk send`.
 // Flags reference: https://developer.algorand.org/docs/clis/goal/clerk/send/
 type PaymentBuilder struct {
-	fields []*components.Field
-	idx    int
+	formBuilder
 
-	status string
-
-	// plumbed in by host model:
-	RunWith func(argv []string)
+	// Spendable resolves "max" for address ("" for the active account),
+	// plumbed in by host model
+	Spendable func(address string, fee uint64) (algo.Amount, error)
 }
 
 func NewPaymentBuilder() *PaymentBuilder {
-	return &PaymentBuilder{
-		fields: []*components.Field{
-			{Label: "From (-f)", Hint: "address (or default)", Active: true},
-			{Label: "To (-t)", Hint: "recipient address"},
-			{Label: "Amount μAlgos (-a)", Hint: "e.g. 1000000 = 1 Algo"},
-			{Label: "Fee μAlgos (--fee)", Hint: "optional; empty for suggested"},
-			{Label: "FirstValid (--firstvalid)", Hint: "optional"},
-			{Label: "LastValid (--lastvalid)", Hint: "optional"},
-			{Label: "Note (-n)", Hint: "plain text note (optional)"},
-			{Label: "Out file (-o)", Hint: "write txn to file (optional)"},
-			{Label: "Sign (-s)", Hint: "true/false, with -o", },
-			{Label: "No Wait (-N)", Hint: "true/false"},
-			{Label: "Rekey (--rekey-to)", Hint: "optional"},
-		},
+	p := &PaymentBuilder{}
+	fields := []*components.FormField{
+		&components.FormField{Key: "from", Kind: components.KindAddress, Flag: "-f",
+			Field: components.Field{Label: "From (-f)", Hint: "address (or default)"}},
+		&components.FormField{Key: "to", Kind: components.KindAddress, Flag: "-t", Required: true,
+			Field: components.Field{Label: "To (-t)", Hint: "recipient address"}},
+		&components.FormField{Key: "amount", Flag: "-a", Required: true,
+			Validators: []components.Validator{validAlgos}, Arg: p.amountArg, Preview: previewAlgos,
+			Field: components.Field{Label: "Amount (-a)", Hint: "1.5 ALGO, 1500000 µ or max"}},
+		&components.FormField{Key: "fee", Kind: components.KindUint, Flag: "--fee", Unit: "μAlgos",
+			Field: components.Field{Label: "Fee (--fee)", Hint: "optional; empty for suggested"}},
+		&components.FormField{Key: "firstvalid", Kind: components.KindUint, Flag: "--firstvalid",
+			Field: components.Field{Label: "FirstValid (--firstvalid)", Hint: "optional"}},
+		&components.FormField{Key: "lastvalid", Kind: components.KindUint, Flag: "--lastvalid",
+			Field: components.Field{Label: "LastValid (--lastvalid)", Hint: "optional"}},
 	}
+	fields = append(fields, p.noteFields()...)
+	fields = append(fields,
+		&components.FormField{Key: "out", Kind: components.KindPath, Flag: "-o",
+			Field: components.Field{Label: "Out file (-o)", Hint: "write txn to file (optional)"}},
+		&components.FormField{Key: "sign", Kind: components.KindBool, Flag: "-s", VisibleIf: components.IsSet("out"),
+			Field: components.Field{Label: "Sign (-s)", Hint: "sign the txn written to -o"}},
+		&components.FormField{Key: "nowait", Kind: components.KindBool, Flag: "-N",
+			Field: components.Field{Label: "No Wait (-N)"}},
+		&components.FormField{Key: "closeto", Kind: components.KindAddress, Flag: "--close-to",
+			Field: components.Field{Label: "Close to (--close-to)", Hint: "optional; sends everything left and closes the account"}},
+		&components.FormField{Key: "rekey", Kind: components.KindAddress, Flag: "--rekey-to",
+			Field: components.Field{Label: "Rekey (--rekey-to)", Hint: "optional"}},
+	)
+	p.formBuilder = newFormBuilder("Payment (goal clerk send)", []string{"clerk", "send"}, fields...)
+	return p
 }
 
-func (p *PaymentBuilder) Title() string { return "Payment (goal clerk send)" }
-func (p *PaymentBuilder) Init() tea.Cmd { return nil }
-
-func (p *PaymentBuilder) Validate() error {
-	to := p.fields[1].Value
-	amt := p.fields[2].Value
-	if strings.TrimSpace(to) == "" {
-		return errors.New("recipient (-t) is required")
+// amount resolves the amount field to μAlgos, asking the host for "max".
+func (p *PaymentBuilder) amount(v, sender string) (algo.Amount, error) {
+	a, err := algo.ParseAlgos(v)
+	if !errors.Is(err, algo.ErrMax) {
+		return a, err
 	}
-	if _, err := strconv.ParseUint(strings.TrimSpace(amt), 10, 64); err != nil {
-		return errors.New("amount (-a) must be a positive integer μAlgos")
+	if p.Spendable == nil {
+		return 0, errors.New("max needs a connected network")
 	}
-	return nil
+	fee, _, _ := p.form.Uint("fee")
+	return p.Spendable(sender, fee)
 }
 
-func (p *PaymentBuilder) Args() []string {
-	f := func(i int) string { return strings.TrimSpace(p.fields[i].Value) }
-	var argv []string
-	argv = append(argv, "clerk", "send")
-	if v := f(0); v != "" { argv = append(argv, "-f", v) }
-	argv = append(argv, "-t", f(1))
-	argv = append(argv, "-a", f(2))
-	if v := f(3); v != "" { argv = append(argv, "--fee", v) }
-	if v := f(4); v != "" { argv = append(argv, "--firstvalid", v) }
-	if v := f(5); v != "" { argv = append(argv, "--lastvalid", v) }
-	if v := f(6); v != "" { argv = append(argv, "-n", v) }
-	if v := f(7); v != "" { argv = append(argv, "-o", v) }
-	if strings.EqualFold(f(8), "true") { argv = append(argv, "-s") }
-	if strings.EqualFold(f(9), "true") { argv = append(argv, "-N") }
-	if v := f(10); v != "" { argv = append(argv, "--rekey-to", v) }
-	return argv
+// amountArg gives goal the amount in μAlgos.
+func (p *PaymentBuilder) amountArg(v string) (string, error) {
+	a, err := p.amount(v, p.form.Get("from"))
+	return strconv.FormatUint(uint64(a), 10), err
 }
 
-func (p *PaymentBuilder) AfterRun(stdout, stderr string, runErr error) {
-	if runErr != nil {
-		p.status = fmt.Sprintf("Error: %v\n%s", runErr, strings.TrimSpace(stderr))
-		return
-	}
-	p.status = strings.TrimSpace(stdout)
+func (p *PaymentBuilder) Backends() []goal.Backend {
+	return []goal.Backend{goal.BackendGoal, goal.BackendNative}
 }
 
-func (p *PaymentBuilder) Update(msg tea.Msg) (goal.Builder, tea.Cmd) {
-	switch m := msg.(type) {
-	case tea.KeyMsg:
-		switch m.String() {
-		case "tab", "down":
-			p.fields[p.idx].Active = false
-			p.idx = (p.idx + 1) % len(p.fields)
-			p.fields[p.idx].Active = true
-		case "shift+tab", "up":
-			p.fields[p.idx].Active = false
-			p.idx = (p.idx - 1 + len(p.fields)) % len(p.fields)
-			p.fields[p.idx].Active = true
-		case "left":
-			p.fields[p.idx].MoveLeft()
-		case "right":
-			p.fields[p.idx].MoveRight()
-		case "backspace":
-			p.fields[p.idx].Backspace()
-		case "enter":
-			// run command
-			if err := p.Validate(); err != nil {
-				p.status = "Validation: " + err.Error()
-				return p, nil
-			}
-			if p.RunWith != nil {
-				p.RunWith(p.Args())
-			}
-		}
-		// typing
-		if m.Type == tea.KeyRunes {
-			for _, r := range m.Runes {
-				p.fields[p.idx].InsertRune(r)
-			}
-		}
+// Transactions builds the payment for the native backend. Writing to a
+// file (-o, -s) only exists with goal.
+func (p *PaymentBuilder) Transactions(sender string, sp types.SuggestedParams) ([]types.Transaction, error) {
+	f := p.form
+	if from := f.Get("from"); from != "" && from != sender {
+		return nil, fmt.Errorf("from (-f) must be the active account %s", sender)
+	}
+	if f.Get("out") != "" {
+		return nil, errors.New("out file (-o) and sign (-s) need the goal backend")
 	}
-	return p, nil
+	return p.Review(sender, sp)
 }
 
-func (p *PaymentBuilder) View() string {
-	left := []string{
-		lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#cba6f7")).Render(p.Title()),
-		"",
+// Review builds the payment as sent by sender.
+func (p *PaymentBuilder) Review(sender string, sp types.SuggestedParams) ([]types.Transaction, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
 	}
-	for _, f := range p.fields {
-		left = append(left, f.Render(36))
-		left = append(left, "")
+	f := p.form
+	amount, err := p.amount(f.Get("amount"), sender)
+	if err != nil {
+		return nil, err
 	}
-	leftPanel := lipgloss.NewStyle().
-		Width(44).
-		Padding(1).
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#89b4fa")).
-		Render(stringsJoin(left))
-
-	right := []string{
-		lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#a6e3a1")).Render("Output"),
-		"",
-		strings.TrimSpace(p.status),
+	note, err := p.note()
+	if err != nil {
+		return nil, err
 	}
-	rightPanel := lipgloss.NewStyle().
-		Width(44).
-		Padding(1).
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#a6e3a1")).
-		Render(stringsJoin(right))
+	sp = withParams(f, sp)
 
-	return lipgloss.JoinHorizontal(lipgloss.Top, leftPanel, "  ", rightPanel)
+	txn, err := transaction.MakePaymentTxn(sender, f.Get("to"), uint64(amount), note, f.Get("closeto"), sp)
+	if err != nil {
+		return nil, err
+	}
+	if v := f.Get("rekey"); v != "" {
+		if err := txn.Rekey(v); err != nil {
+			return nil, fmt.Errorf("invalid rekey address: %w", err)
+		}
+	}
+	return []types.Transaction{txn}, nil
 }
 
-func stringsJoin(ss []string) string {
-	var b strings.Builder
-	for i, s := range ss {
-		if i > 0 { b.WriteString("\n") }
-		b.WriteString(s)
-	}
-	return b.String()
+func (p *PaymentBuilder) Update(msg tea.Msg) (goal.Builder, tea.Cmd) {
+	return p, p.update(msg)
 }
@@ -18,6 +18,7 @@ type SignSendBuilder struct {
 
 func NewSignSendBuilder() *SignSendBuilder { return &SignSendBuilder{} }
 func (b *SignSendBuilder) Title() string { return "Sign / Send (goal clerk sign/rawsend)" }
+func (b *SignSendBuilder) Backends() []goal.Backend { return []goal.Backend{goal.BackendGoal} }
 func (b *SignSendBuilder) Init() tea.Cmd { return nil }
 func (b *SignSendBuilder) Validate() error { return nil }
 func (b *SignSendBuilder) Args() []string { return []string{"clerk","sign","-i","in.txn","-o","out.stxn"} }
@@ -1,43 +1,306 @@
 package components
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"strings"
+	"unicode"
 
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rivo/uniseg"
+)
+
+// undoLimit bounds the edits Undo can take back.
+const undoLimit = 100
+
+// Field is a text input. Cursor counts runes into Value and always sits
+// on a grapheme boundary, so accents and emoji move and delete as one
+// character; MaxLen counts characters too.
 type Field struct {
-	Label   string
-	Value   string
-	Cursor  int
-	Active  bool
-	Hint    string
-	Secret  bool
-	MaxLen  int
+	Label  string
+	Value  string
+	Cursor int
+	Active bool
+	Hint   string
+	Secret bool
+	MaxLen int
+	// Multiline keeps newlines from pastes and alt+enter
+	Multiline bool
+
+	undo   []fieldState
+	lastOp string
+}
+
+type fieldState struct {
+	value  string
+	cursor int
 }
 
 func (f *Field) SetActive(a bool) { f.Active = a }
 
-func (f *Field) InsertRune(r rune) {
-	if f.MaxLen > 0 && len(f.Value) >= f.MaxLen { return }
-	left := f.Value[:f.Cursor]
-	right := f.Value[f.Cursor:]
-	f.Value = left + string(r) + right
-	f.Cursor++
+// SetValue replaces the value and puts the cursor at the end.
+func (f *Field) SetValue(v string) {
+	f.Value = v
+	f.Cursor = len([]rune(v))
+	f.undo, f.lastOp = nil, ""
+}
+
+// boundaries are the rune offsets where characters start, plus the end.
+func (f *Field) boundaries() []int {
+	b := []int{0}
+	n := 0
+	gr := uniseg.NewGraphemes(f.Value)
+	for gr.Next() {
+		n += len(gr.Runes())
+		b = append(b, n)
+	}
+	return b
+}
+
+// clamp keeps Cursor on a boundary within Value.
+func (f *Field) clamp() {
+	b := f.boundaries()
+	if f.Cursor <= 0 {
+		f.Cursor = 0
+		return
+	}
+	for i := len(b) - 1; i >= 0; i-- {
+		if b[i] <= f.Cursor {
+			f.Cursor = b[i]
+			return
+		}
+	}
+}
+
+func (f *Field) prevBoundary() int {
+	f.clamp()
+	b := f.boundaries()
+	for i := len(b) - 1; i >= 0; i-- {
+		if b[i] < f.Cursor {
+			return b[i]
+		}
+	}
+	return 0
+}
+
+func (f *Field) nextBoundary() int {
+	f.clamp()
+	for _, p := range f.boundaries() {
+		if p > f.Cursor {
+			return p
+		}
+	}
+	return f.Cursor
+}
+
+// save records the state before an edit. Typing runs of letters is one
+// undo step, like in most editors.
+func (f *Field) save(op string) {
+	if op == "type" && f.lastOp == "type" {
+		return
+	}
+	f.lastOp = op
+	f.undo = append(f.undo, fieldState{f.Value, f.Cursor})
+	if len(f.undo) > undoLimit {
+		f.undo = f.undo[1:]
+	}
+}
+
+// Undo takes back the last edit.
+func (f *Field) Undo() {
+	if len(f.undo) == 0 {
+		return
+	}
+	s := f.undo[len(f.undo)-1]
+	f.undo = f.undo[:len(f.undo)-1]
+	f.Value, f.Cursor, f.lastOp = s.value, s.cursor, ""
+}
+
+// replace swaps runes [from, to) for s and leaves the cursor after s.
+func (f *Field) replace(from, to int, s string) {
+	r := []rune(f.Value)
+	ins := []rune(s)
+	f.Value = string(r[:from]) + s + string(r[to:])
+	f.Cursor = from + len(ins)
+}
+
+// Insert types s at the cursor. Newlines become spaces in single-line
+// fields; text past MaxLen is dropped.
+func (f *Field) Insert(s string) {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.ReplaceAll(s, "\r", "\n")
+	if !f.Multiline {
+		s = strings.ReplaceAll(s, "\n", " ")
+	}
+	if f.MaxLen > 0 {
+		room := f.MaxLen - uniseg.GraphemeClusterCount(f.Value)
+		if room <= 0 {
+			return
+		}
+		if uniseg.GraphemeClusterCount(s) > room {
+			var b strings.Builder
+			gr := uniseg.NewGraphemes(s)
+			for i := 0; i < room && gr.Next(); i++ {
+				b.WriteString(gr.Str())
+			}
+			s = b.String()
+		}
+	}
+	if s == "" {
+		return
+	}
+	op := "paste"
+	if r := []rune(s); len(r) == 1 && !unicode.IsSpace(r[0]) {
+		op = "type"
+	}
+	f.save(op)
+	f.clamp()
+	f.replace(f.Cursor, f.Cursor, s)
 }
 
+func (f *Field) InsertRune(r rune) { f.Insert(string(r)) }
+
+// Backspace deletes the character before the cursor.
 func (f *Field) Backspace() {
-	if f.Cursor == 0 || len(f.Value) == 0 { return }
-	left := f.Value[:f.Cursor-1]
-	right := f.Value[f.Cursor:]
-	f.Value = left + right
-	f.Cursor--
+	if f.Cursor == 0 || f.Value == "" {
+		return
+	}
+	f.save("delete")
+	prev := f.prevBoundary()
+	f.replace(prev, f.Cursor, "")
+}
+
+// Delete deletes the character under the cursor.
+func (f *Field) Delete() {
+	next := f.nextBoundary()
+	if next == f.Cursor {
+		return
+	}
+	f.save("delete")
+	f.replace(f.Cursor, next, "")
+}
+
+// DeleteWordBackward deletes from the start of the previous word.
+func (f *Field) DeleteWordBackward() {
+	start := f.wordLeft()
+	if start == f.Cursor {
+		return
+	}
+	f.save("delete")
+	f.replace(start, f.Cursor, "")
+}
+
+func (f *Field) MoveLeft()  { f.Cursor = f.prevBoundary(); f.lastOp = "" }
+func (f *Field) MoveRight() { f.Cursor = f.nextBoundary(); f.lastOp = "" }
+
+// wordLeft is where the word before the cursor starts.
+func (f *Field) wordLeft() int {
+	f.clamp()
+	r := []rune(f.Value)
+	i := f.Cursor
+	for i > 0 && !isWord(r[i-1]) {
+		i--
+	}
+	for i > 0 && isWord(r[i-1]) {
+		i--
+	}
+	return i
+}
+
+// wordRight is where the word after the cursor ends.
+func (f *Field) wordRight() int {
+	f.clamp()
+	r := []rune(f.Value)
+	i := f.Cursor
+	for i < len(r) && !isWord(r[i]) {
+		i++
+	}
+	for i < len(r) && isWord(r[i]) {
+		i++
+	}
+	return i
+}
+
+func isWord(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r) }
+
+func (f *Field) WordLeft()  { f.Cursor = f.wordLeft(); f.clamp(); f.lastOp = "" }
+func (f *Field) WordRight() { f.Cursor = f.wordRight(); f.clamp(); f.lastOp = "" }
+
+// Home and End move to the start and end of the current line.
+func (f *Field) Home() {
+	f.clamp()
+	r := []rune(f.Value)
+	for f.Cursor > 0 && r[f.Cursor-1] != '\n' {
+		f.Cursor--
+	}
+	f.lastOp = ""
+}
+
+func (f *Field) End() {
+	f.clamp()
+	r := []rune(f.Value)
+	for f.Cursor < len(r) && r[f.Cursor] != '\n' {
+		f.Cursor++
+	}
+	f.lastOp = ""
 }
 
-func (f *Field) MoveLeft()  { if f.Cursor > 0 { f.Cursor-- } }
-func (f *Field) MoveRight() { if f.Cursor < len(f.Value) { f.Cursor++ } }
+// HandleKey applies an editing key, reporting whether it was one.
+// Bracketed pastes arrive as runes and may hold several lines.
+func (f *Field) HandleKey(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "left":
+		f.MoveLeft()
+	case "right":
+		f.MoveRight()
+	case "ctrl+left", "alt+left", "alt+b":
+		f.WordLeft()
+	case "ctrl+right", "alt+right", "alt+f":
+		f.WordRight()
+	case "home", "ctrl+a":
+		f.Home()
+	case "end", "ctrl+e":
+		f.End()
+	case "backspace", "ctrl+h":
+		f.Backspace()
+	case "delete", "ctrl+d":
+		f.Delete()
+	case "ctrl+w", "alt+backspace":
+		f.DeleteWordBackward()
+	case "ctrl+z":
+		f.Undo()
+	case "ctrl+v":
+		if s, err := clipboard.ReadAll(); err == nil {
+			f.Insert(s)
+		}
+	case "alt+enter":
+		if !f.Multiline {
+			return false
+		}
+		f.Insert("\n")
+	default:
+		if msg.Type != tea.KeyRunes && msg.Type != tea.KeySpace {
+			return false
+		}
+		f.Insert(string(msg.Runes))
+	}
+	return true
+}
 
 func (f Field) Render(width int) string {
 	label := lipgloss.NewStyle().Bold(true).Render(f.Label + ":")
 	val := f.Value
-	if f.Secret && len(val) > 0 { val = "••••••••" }
-	if f.Active { val += "█" }
+	switch {
+	case f.Secret:
+		if len(val) > 0 {
+			val = "••••••••"
+		}
+		if f.Active {
+			val += "█"
+		}
+	case f.Active:
+		val = f.withCursor()
+	}
 	value := lipgloss.NewStyle().Width(width).Render(val)
 	hint := ""
 	if f.Hint != "" {
@@ -45,3 +308,16 @@ func (f Field) Render(width int) string {
 	}
 	return label + "\n" + "  " + value + hint
 }
+
+// withCursor draws the character under the cursor reversed, or a block
+// at the end of the value or of a line.
+func (f Field) withCursor() string {
+	f.clamp()
+	r := []rune(f.Value)
+	next := f.nextBoundary()
+	before, under, after := string(r[:f.Cursor]), string(r[f.Cursor:next]), string(r[next:])
+	if under == "" || under == "\n" {
+		return before + "█" + under + after
+	}
+	return before + lipgloss.NewStyle().Reverse(true).Render(under) + after
+}
@@ -0,0 +1,107 @@
+package components
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	eAcute = "e\u0301"                                    // e + combining acute: 2 runes, 1 character
+	flag   = "\U0001F1EE\U0001F1F9"                       // regional indicators: 2 runes
+	family = "\U0001F468\u200D\U0001F469\u200D\U0001F467" // ZWJ sequence: 5 runes
+)
+
+func TestFieldGraphemes(t *testing.T) {
+	tests := []struct {
+		name       string
+		value      string
+		cursor     int // runes; -1 for the end
+		ops        []func(*Field)
+		wantValue  string
+		wantCursor int
+	}{
+		{"backspace combining", "caf" + eAcute, -1, []func(*Field){(*Field).Backspace}, "caf", 3},
+		{"backspace flag", "a" + flag, -1, []func(*Field){(*Field).Backspace}, "a", 1},
+		{"backspace zwj", family + "x", 5, []func(*Field){(*Field).Backspace}, "x", 0},
+		{"delete under cursor", eAcute + "x", 0, []func(*Field){(*Field).Delete}, "x", 0},
+		{"delete at end", "ab", -1, []func(*Field){(*Field).Delete}, "ab", 2},
+		{"left over flag", "a" + flag, -1, []func(*Field){(*Field).MoveLeft}, "a" + flag, 1},
+		{"right over zwj", family + "x", 0, []func(*Field){(*Field).MoveRight}, family + "x", 5},
+		{"right at end", "ab", -1, []func(*Field){(*Field).MoveRight}, "ab", 2},
+		// a cursor left inside a character snaps back to its start
+		{"clamp inside", eAcute, 1, []func(*Field){func(f *Field) { f.Insert("x") }}, "x" + eAcute, 1},
+		{"insert before flag", flag, 0, []func(*Field){func(f *Field) { f.Insert("é") }}, "é" + flag, 1},
+		{"word back", "pay " + eAcute + "cole", -1, []func(*Field){(*Field).DeleteWordBackward}, "pay ", 4},
+		{"word left", "one two", -1, []func(*Field){(*Field).WordLeft}, "one two", 4},
+		{"home end", "ab\ncd", 4, []func(*Field){(*Field).Home}, "ab\ncd", 3},
+		{"end of line", "ab\ncd", 0, []func(*Field){(*Field).End}, "ab\ncd", 2},
+	}
+	for _, tt := range tests {
+		f := Field{Multiline: true}
+		f.SetValue(tt.value)
+		if tt.cursor >= 0 {
+			f.Cursor = tt.cursor
+		}
+		for _, op := range tt.ops {
+			op(&f)
+		}
+		if f.Value != tt.wantValue || f.Cursor != tt.wantCursor {
+			t.Errorf("%s: got %q cursor %d, want %q cursor %d", tt.name, f.Value, f.Cursor, tt.wantValue, tt.wantCursor)
+		}
+	}
+}
+
+func TestFieldMaxLen(t *testing.T) {
+	f := Field{MaxLen: 3}
+	f.Insert("a" + flag + eAcute + "zz")
+	if want := "a" + flag + eAcute; f.Value != want {
+		t.Errorf("MaxLen 3 kept %q, want %q", f.Value, want)
+	}
+	f.Insert("b")
+	if f.Value != "a"+flag+eAcute {
+		t.Errorf("full field took more: %q", f.Value)
+	}
+}
+
+func TestFieldInsertLines(t *testing.T) {
+	single := Field{}
+	single.Insert("a\r\nb\rc")
+	if single.Value != "a b c" {
+		t.Errorf("single line = %q", single.Value)
+	}
+	multi := Field{Multiline: true}
+	multi.Insert("a\r\nb")
+	if multi.Value != "a\nb" {
+		t.Errorf("multiline = %q", multi.Value)
+	}
+	if single.HandleKey(tea.KeyMsg{Type: tea.KeyEnter, Alt: true}) {
+		t.Error("alt+enter must not be taken by a single-line field")
+	}
+}
+
+func TestFieldUndo(t *testing.T) {
+	f := Field{}
+	for _, r := range "héllo" {
+		f.InsertRune(r)
+	}
+	f.Insert(" world")
+	f.Backspace()
+
+	f.Undo() // the backspace
+	if f.Value != "héllo world" {
+		t.Errorf("after one undo: %q", f.Value)
+	}
+	f.Undo() // the paste
+	if f.Value != "héllo" {
+		t.Errorf("after two undos: %q", f.Value)
+	}
+	f.Undo() // the typing run, as one step
+	if f.Value != "" || f.Cursor != 0 {
+		t.Errorf("after three undos: %q cursor %d", f.Value, f.Cursor)
+	}
+	f.Undo()
+	if f.Value != "" {
+		t.Errorf("undo past the start: %q", f.Value)
+	}
+}
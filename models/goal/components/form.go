@@ -0,0 +1,472 @@
+package components
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/algorand/go-algorand-sdk/v2/types"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Kind decides how a form field is edited, rendered and mapped to argv.
+type Kind int
+
+const (
+	KindText Kind = iota
+	// KindAddress holds an Algorand address.
+	KindAddress
+	// KindUint holds an unsigned integer in Unit.
+	KindUint
+	// KindBool is toggled with space; it maps to Flag alone when set.
+	KindBool
+	// KindEnum cycles through Options with space or left/right.
+	KindEnum
+	// KindPath holds a file path; a leading ~ is expanded in Args.
+	KindPath
+	// KindNote is multi-line text, alt+enter starts a new line.
+	KindNote
+	// KindSecret is text shown masked.
+	KindSecret
+)
+
+// Validator checks a field's value; it only runs on non-empty values,
+// Required covers empty ones.
+type Validator func(value string) error
+
+// FormField is one declared input of a Form.
+type FormField struct {
+	Field
+
+	Key  string
+	Kind Kind
+	// Flag is the goal flag the value maps to, "" to leave it out of Args
+	Flag string
+	// Unit is shown next to the label of uint fields
+	Unit string
+	// Options of an enum field, the first is the default
+	Options    []string
+	Required   bool
+	Validators []Validator
+	// VisibleIf hides the field, and drops it from Args, while false
+	VisibleIf func(f *Form) bool
+	// Arg turns the value into what goal takes, e.g. "1.5 ALGO" into
+	// μAlgos. It may query the network, so it only runs on Validate.
+	Arg func(v string) (string, error)
+	// Preview shows under the value how it is understood
+	Preview func(v string) string
+
+	Err string
+}
+
+// Form is a list of declared fields with navigation, typing, inline
+// validation and the mapping to goal argv.
+type Form struct {
+	// Command is the argv prefix, e.g. ["clerk", "send"]
+	Command []string
+	Fields  []*FormField
+	idx     int
+}
+
+// NewForm builds a form for command and focuses its first field.
+func NewForm(command []string, fields ...*FormField) *Form {
+	f := &Form{Command: command, Fields: fields}
+	for _, fd := range fields {
+		switch fd.Kind {
+		case KindEnum:
+			if fd.Value == "" && len(fd.Options) > 0 {
+				fd.Value = fd.Options[0]
+			}
+		case KindSecret:
+			fd.Secret = true
+		case KindNote:
+			fd.Multiline = true
+		}
+	}
+	if len(fields) > 0 {
+		fields[0].Active = true
+	}
+	return f
+}
+
+// Field returns the field with key, nil if there is none.
+func (f *Form) Field(key string) *FormField {
+	for _, fd := range f.Fields {
+		if fd.Key == key {
+			return fd
+		}
+	}
+	return nil
+}
+
+// Get is the trimmed value of key, "" when the field is hidden.
+func (f *Form) Get(key string) string {
+	fd := f.Field(key)
+	if fd == nil || !f.visible(fd) {
+		return ""
+	}
+	if fd.Kind == KindNote {
+		return fd.Value
+	}
+	return strings.TrimSpace(fd.Value)
+}
+
+// Bool reports whether the toggle key is on.
+func (f *Form) Bool(key string) bool { return f.Get(key) == "true" }
+
+// Uint parses key; ok is false when it is empty.
+func (f *Form) Uint(key string) (n uint64, ok bool, err error) {
+	v := f.Get(key)
+	if v == "" {
+		return 0, false, nil
+	}
+	n, err = strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("%s must be a whole number", f.Field(key).Label)
+	}
+	return n, true, nil
+}
+
+// IsSet is a VisibleIf condition: the field key has a value.
+func IsSet(key string) func(f *Form) bool {
+	return func(f *Form) bool { return f.Get(key) != "" }
+}
+
+// Values are the raw values that differ from a new form, by key; secret
+// fields are never included.
+func (f *Form) Values() map[string]string {
+	values := map[string]string{}
+	for _, fd := range f.Fields {
+		if fd.Kind == KindSecret || fd.Value == "" {
+			continue
+		}
+		if fd.Kind == KindEnum && len(fd.Options) > 0 && fd.Value == fd.Options[0] {
+			continue
+		}
+		values[fd.Key] = fd.Value
+	}
+	return values
+}
+
+// SetValues fills the form with values, resetting the fields it has no
+// value for. Unknown keys are ignored.
+func (f *Form) SetValues(values map[string]string) {
+	for _, fd := range f.Fields {
+		v, ok := values[fd.Key]
+		if !ok && fd.Kind == KindEnum && len(fd.Options) > 0 {
+			v = fd.Options[0]
+		}
+		fd.SetValue(v)
+		fd.Err = ""
+	}
+}
+
+func (f *Form) visible(fd *FormField) bool {
+	return fd.VisibleIf == nil || fd.VisibleIf(f)
+}
+
+// Visible lists the fields currently shown.
+func (f *Form) Visible() []*FormField {
+	var out []*FormField
+	for _, fd := range f.Fields {
+		if f.visible(fd) {
+			out = append(out, fd)
+		}
+	}
+	return out
+}
+
+// Active is the focused field.
+func (f *Form) Active() *FormField {
+	if len(f.Fields) == 0 {
+		return nil
+	}
+	return f.Fields[f.idx]
+}
+
+// FocusFirst focuses the first visible field, for when the VisibleIf
+// conditions hid the focused one.
+func (f *Form) FocusFirst() {
+	found := false
+	for i, fd := range f.Fields {
+		fd.Active = !found && f.visible(fd)
+		if fd.Active {
+			f.idx, found = i, true
+		}
+	}
+}
+
+// validateField sets fd.Err and returns it as an error.
+func (f *Form) validateField(fd *FormField) error {
+	fd.Err = ""
+	v := strings.TrimSpace(fd.Value)
+	if v == "" || (fd.Kind == KindBool && v != "true") {
+		if fd.Required {
+			fd.Err = "required"
+		}
+	} else {
+		checks := append(kindValidators(fd), fd.Validators...)
+		for _, check := range checks {
+			if err := check(v); err != nil {
+				fd.Err = err.Error()
+				break
+			}
+		}
+	}
+	if fd.Err == "" {
+		return nil
+	}
+	return fmt.Errorf("%s: %s", fd.Label, fd.Err)
+}
+
+func kindValidators(fd *FormField) []Validator {
+	switch fd.Kind {
+	case KindAddress:
+		return []Validator{ValidAddress}
+	case KindUint:
+		return []Validator{ValidUint}
+	case KindEnum:
+		return []Validator{OneOf(fd.Options...)}
+	}
+	return nil
+}
+
+// Validate checks every visible field, marks the ones in error and
+// returns the first error.
+func (f *Form) Validate() error {
+	var first error
+	for _, fd := range f.Fields {
+		if !f.visible(fd) {
+			fd.Err = ""
+			continue
+		}
+		err := f.validateField(fd)
+		if v := f.Get(fd.Key); err == nil && fd.Arg != nil && v != "" {
+			if _, argErr := fd.Arg(v); argErr != nil {
+				fd.Err = argErr.Error()
+				err = fmt.Errorf("%s: %s", fd.Label, fd.Err)
+			}
+		}
+		if err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// Args maps the visible fields to argv: Command, then "Flag value" for
+// each filled field, or the bare Flag for toggles that are on.
+func (f *Form) Args() []string {
+	argv := append([]string{}, f.Command...)
+	for _, fd := range f.Fields {
+		v := f.Get(fd.Key)
+		if fd.Flag == "" || v == "" {
+			continue
+		}
+		if fd.Arg != nil {
+			if arg, err := fd.Arg(v); err == nil {
+				v = arg
+			}
+		}
+		switch fd.Kind {
+		case KindBool:
+			if v == "true" {
+				argv = append(argv, fd.Flag)
+			}
+		case KindPath:
+			argv = append(argv, fd.Flag, ExpandHome(v))
+		default:
+			argv = append(argv, fd.Flag, v)
+		}
+	}
+	return argv
+}
+
+// focus moves by step through the visible fields, validating the one
+// that is left.
+func (f *Form) focus(step int) {
+	if len(f.Fields) == 0 {
+		return
+	}
+	cur := f.Fields[f.idx]
+	if strings.TrimSpace(cur.Value) != "" {
+		_ = f.validateField(cur)
+	}
+	cur.Active = false
+	for i := 0; i < len(f.Fields); i++ {
+		f.idx = (f.idx + step + len(f.Fields)) % len(f.Fields)
+		if f.visible(f.Fields[f.idx]) {
+			break
+		}
+	}
+	f.Fields[f.idx].Active = true
+}
+
+// Update handles a key for the form; submit is true on enter.
+func (f *Form) Update(msg tea.KeyMsg) (submit bool) {
+	fd := f.Active()
+	if fd == nil {
+		return msg.String() == "enter"
+	}
+	switch msg.String() {
+	case "tab", "down":
+		f.focus(1)
+		return false
+	case "shift+tab", "up":
+		f.focus(-1)
+		return false
+	case "enter":
+		return true
+	}
+
+	switch fd.Kind {
+	case KindBool:
+		switch msg.String() {
+		case " ", "left", "right":
+			if fd.Value == "true" {
+				fd.Value = "false"
+			} else {
+				fd.Value = "true"
+			}
+		}
+		return false
+	case KindEnum:
+		switch msg.String() {
+		case " ", "right":
+			fd.Value = cycle(fd.Options, fd.Value, 1)
+		case "left":
+			fd.Value = cycle(fd.Options, fd.Value, -1)
+		}
+		return false
+	}
+
+	if fd.HandleKey(msg) && fd.Err != "" {
+		_ = f.validateField(fd)
+	}
+	return false
+}
+
+func cycle(options []string, current string, step int) string {
+	if len(options) == 0 {
+		return current
+	}
+	for i, o := range options {
+		if o == current {
+			return options[(i+step+len(options))%len(options)]
+		}
+	}
+	return options[0]
+}
+
+// Render draws the visible fields with their inline errors.
+func (f *Form) Render(width int) string {
+	errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#f38ba8"))
+	faint := lipgloss.NewStyle().Faint(true)
+	var lines []string
+	for _, fd := range f.Visible() {
+		shown := fd.Field
+		switch fd.Kind {
+		case KindBool:
+			shown.Value = "[ ] no"
+			if fd.Value == "true" {
+				shown.Value = "[x] yes"
+			}
+			shown.Active = false
+		case KindEnum:
+			shown.Value = "‹ " + fd.Value + " ›"
+			shown.Active = false
+		case KindUint:
+			if fd.Unit != "" {
+				shown.Label += " " + faint.Render("["+fd.Unit+"]")
+			}
+		}
+		if fd.Active && (fd.Kind == KindBool || fd.Kind == KindEnum) {
+			shown.Value = lipgloss.NewStyle().Foreground(lipgloss.Color("#ef9f76")).Render(shown.Value)
+		}
+		lines = append(lines, shown.Render(width))
+		if v := strings.TrimSpace(fd.Value); fd.Preview != nil && v != "" {
+			if p := fd.Preview(v); p != "" {
+				lines = append(lines, "  "+lipgloss.NewStyle().Foreground(lipgloss.Color("#a6e3a1")).Render("= "+p))
+			}
+		}
+		if fd.Err != "" {
+			lines = append(lines, "  "+errStyle.Render("✗ "+fd.Err))
+		}
+		lines = append(lines, "")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ValidAddress accepts Algorand addresses.
+func ValidAddress(v string) error {
+	if _, err := types.DecodeAddress(v); err != nil {
+		return errors.New("not a valid address")
+	}
+	return nil
+}
+
+// ValidUint accepts whole non-negative numbers.
+func ValidUint(v string) error {
+	if _, err := strconv.ParseUint(v, 10, 64); err != nil {
+		return errors.New("must be a whole number")
+	}
+	return nil
+}
+
+// NonZero rejects 0.
+func NonZero(v string) error {
+	if n, err := strconv.ParseUint(v, 10, 64); err == nil && n == 0 {
+		return errors.New("must be more than 0")
+	}
+	return nil
+}
+
+// OneOf accepts only the given options.
+func OneOf(options ...string) Validator {
+	return func(v string) error {
+		for _, o := range options {
+			if v == o {
+				return nil
+			}
+		}
+		return fmt.Errorf("must be one of %s", strings.Join(options, ", "))
+	}
+}
+
+// MaxBytes rejects values longer than n bytes.
+func MaxBytes(n int) Validator {
+	return func(v string) error {
+		if len(v) > n {
+			return fmt.Errorf("%d bytes, at most %d", len(v), n)
+		}
+		return nil
+	}
+}
+
+// FileExists accepts paths to existing files.
+func FileExists(v string) error {
+	st, err := os.Stat(ExpandHome(v))
+	if err != nil {
+		return errors.New("file not found")
+	}
+	if st.IsDir() {
+		return errors.New("is a directory")
+	}
+	return nil
+}
+
+// ExpandHome replaces a leading ~ with the home directory.
+func ExpandHome(p string) string {
+	if p != "~" && !strings.HasPrefix(p, "~/") {
+		return p
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return p
+	}
+	return filepath.Join(home, strings.TrimPrefix(p, "~"))
+}
@@ -1,10 +1,24 @@
 package iface
 
-import tea "github.com/charmbracelet/bubbletea"
+import (
+	"github.com/algorand/go-algorand-sdk/v2/types"
+	tea "github.com/charmbracelet/bubbletea"
+)
 
 // RunFunc lets the host model execute a goal command with argv.
 type RunFunc func(argv []string)
 
+// Backend is a way of carrying out what a builder describes.
+type Backend string
+
+const (
+	// BackendGoal shells out to the goal CLI with Args().
+	BackendGoal Backend = "goal"
+	// BackendNative builds SDK transactions, signed with the active
+	// account and sent to the connected algod.
+	BackendNative Backend = "native"
+)
+
 // Builder is the interface every TUI builder implements.
 type Builder interface {
 	Title() string
@@ -15,4 +29,69 @@ type Builder interface {
 	Validate() error
 	Args() []string
 	AfterRun(stdout, stderr string, runErr error)
+
+	// Backends lists the backends the builder supports.
+	Backends() []Backend
+}
+
+// NativeBuilder is implemented by builders supporting BackendNative.
+type NativeBuilder interface {
+	Builder
+
+	// Transactions turns the form into unsigned transactions sent by
+	// sender. More than one transaction is sent as an atomic group.
+	Transactions(sender string, sp types.SuggestedParams) ([]types.Transaction, error)
+}
+
+// Batcher is implemented by native builders sending more transactions
+// than fit in a group: after one review they are sent batch by batch, and
+// the builder is told how each batch went.
+type Batcher interface {
+	NativeBuilder
+
+	// Batches splits the reviewed transactions into what is sent
+	// together, an atomic group or a single transaction.
+	Batches(txns []types.Transaction) [][]types.Transaction
+	// BatchSending records batch i as sent, with the IDs it will have,
+	// just before it is submitted; an error stops the run unsent.
+	BatchSending(i int, txIDs []string) error
+	// BatchDone records batch i, sent as txIDs: rejected when it surely
+	// is not on chain, err alone when it may be but was not seen confirmed.
+	// The command returned, if any, runs next, e.g. to reload what changed.
+	BatchDone(i int, txIDs []string, round uint64, rejected bool, err error) tea.Cmd
+}
+
+// CheckedMsg hands the outcome of a builder's background checks back to
+// Owner, the builder that started them, whichever builder is current:
+// Apply, run on the update goroutine, stores the outcome and returns what
+// Validate would have. Current tells Owner whether it is still on screen.
+type CheckedMsg struct {
+	Owner   any
+	Apply   func() error
+	Current bool
+}
+
+// LoadedMsg hands what a builder read in the background back to Owner,
+// the builder that asked, whichever builder is current: Apply stores it
+// on the update goroutine.
+type LoadedMsg struct {
+	Owner any
+	Apply func()
+}
+
+// Templater is implemented by builders whose fields can be saved, as a
+// template or an unsent draft, and filled back in.
+type Templater interface {
+	// Values are the fields set, by key; secrets are left out.
+	Values() map[string]string
+	// SetValues replaces every field with values.
+	SetValues(values map[string]string)
+}
+
+// Reviewer is implemented by builders that can decode what they are about
+// to send, whatever the backend, for the review screen.
+type Reviewer interface {
+	// Review builds the transactions as sent by sender, without the
+	// backend's restrictions.
+	Review(sender string, sp types.SuggestedParams) ([]types.Transaction, error)
 }
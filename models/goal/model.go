@@ -1,19 +1,23 @@
 package goal
 
 import (
-	"context"
+	"errors"
 	"fmt"
 	"strings"
-	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	algo "lazychain/lib"
+	"lazychain/models/appctx"
 	"lazychain/models/goal/builders"
 	"lazychain/models/goal/components"
+	"lazychain/models/goal/iface"
+	"lazychain/models/settings"
 )
 
 type GOALModel struct {
+	app     *appctx.Context
 	nav     components.ListNav
 	builder Builder
 	runner  *Runner
@@ -21,23 +25,44 @@ type GOALModel struct {
 	builders []Builder
 	output   string
 	errLine  string
+
+	// review waiting for confirmation, nil when none
+	review *review
+
+	// templates and drafts; the nav lists builderItems, then templates
+	store        settings.BuilderStore
+	builderItems []string
+	// sent holds the values last sent by each builder, not a draft
+	sent   map[string]map[string]string
+	prompt *prompt
+
+	// batch being sent, nil when none
+	batch *batchRun
+	// builder whose run is in flight, nil when none
+	running Builder
 }
 
-func NewGOALModel() *GOALModel {
+func NewGOALModel(appCtx *appctx.Context) *GOALModel {
 	m := &GOALModel{
+		app:    appCtx,
 		runner: NewRunner(),
+		sent:   map[string]map[string]string{},
 	}
 	// Left menu
+	m.builderItems = []string{
+		"Payment (clerk send)",
+		"ASA Transfer (asset send)",
+		"Batch Payments (CSV)",
+		"Asset Opt-in / Opt-out",
+		"Create Asset (wizard)",
+		"App Call (app call/method)",
+		"Atomic Group (clerk group)",
+		"Sign / Send (clerk sign/rawsend)",
+		"Inspect / Simulate",
+	}
 	m.nav = components.ListNav{
-		Title: "GOAL: Transaction Builder",
-		Items: []string{
-			"Payment (clerk send)",
-			"ASA Transfer (asset send)",
-			"App Call (app call/method)",
-			"Atomic Group (clerk group)",
-			"Sign / Send (clerk sign/rawsend)",
-			"Inspect / Simulate",
-		},
+		Title:  "GOAL: Transaction Builder",
+		Items:  m.builderItems,
 		Active: true,
 		Width:  28,
 	}
@@ -45,9 +70,78 @@ func NewGOALModel() *GOALModel {
 	// Builders
 	pay := builders.NewPaymentBuilder()
 	pay.RunWith = m.run
+	pay.Spendable = func(addr string, fee uint64) (algo.Amount, error) {
+		c, err := m.chain()
+		if err != nil {
+			return 0, err
+		}
+		return c.SpendableAlgos(m.account(addr), fee)
+	}
 
 	asa := builders.NewAssetTransferBuilder()
 	asa.RunWith = m.run
+	asa.AssetUnits = func(id uint64) (uint32, string, error) {
+		c, err := m.chain()
+		if err != nil {
+			return 0, "", err
+		}
+		return c.AssetUnits(id)
+	}
+	asa.AssetBalance = func(addr string, id uint64) (algo.Amount, error) {
+		c, err := m.chain()
+		if err != nil {
+			return 0, err
+		}
+		return c.AssetBalance(m.account(addr), id)
+	}
+
+	batch := builders.NewBatchPaymentBuilder()
+	batch.RunWith = m.run
+	batch.AssetUnits = asa.AssetUnits
+	batch.AssetBalance = asa.AssetBalance
+	batch.AccountState = func(addr string) (algo.AccountState, error) {
+		c, err := m.chain()
+		if err != nil {
+			return algo.AccountState{}, err
+		}
+		return c.AccountState(m.account(addr))
+	}
+	batch.OptedIn = func(addr string, id uint64) (bool, error) {
+		c, err := m.chain()
+		if err != nil {
+			return false, err
+		}
+		return c.OptedIn(addr, id)
+	}
+	batch.ConfirmedRound = func(txID string) (uint64, error) {
+		c, err := m.chain()
+		if err != nil {
+			return 0, err
+		}
+		return c.ConfirmedRound(txID)
+	}
+	batch.KnownRound = func() (uint64, error) {
+		c, err := m.chain()
+		if err != nil {
+			return 0, err
+		}
+		return c.KnownRound()
+	}
+
+	opt := builders.NewAssetOptBuilder()
+	opt.RunWith = m.run
+	opt.AssetUnits = asa.AssetUnits
+	opt.AccountState = batch.AccountState
+	opt.Holdings = func(addr string) ([]algo.Holding, error) {
+		c, err := m.chain()
+		if err != nil {
+			return nil, err
+		}
+		return c.Holdings(m.account(addr))
+	}
+
+	create := builders.NewAssetCreateBuilder()
+	create.RunWith = m.run
 
 	app := builders.NewAppCallBuilder()
 	app.RunWith = m.run
@@ -61,19 +155,53 @@ func NewGOALModel() *GOALModel {
 	ins := builders.NewInspectSimBuilder()
 	ins.RunWith = m.run
 
-	m.builders = []Builder{pay, asa, app, group, sign, ins}
+	m.builders = []Builder{pay, asa, batch, opt, create, app, group, sign, ins}
 	m.builder = m.builders[0]
+	m.loadStore()
 	return m
 }
 
 func (m *GOALModel) Init() tea.Cmd { return m.builder.Init() }
 
+// Runner returns the goal runner shared with other goal-driven views.
+func (m *GOALModel) Runner() *Runner { return m.runner }
+
+// run is called by builders on enter: nothing is sent before the review
+// is confirmed.
 func (m *GOALModel) run(argv []string) {
-	// fire and wait (no background work; we execute synchronously here)
-	_ = m.runner.CheckBinary()
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-	res := m.runner.Run(ctx, argv)
+	if m.batch != nil {
+		m.builder.AfterRun("", "", errors.New("a batch is still being sent"))
+		return
+	}
+	if m.running != nil {
+		m.builder.AfterRun("", "", fmt.Errorf("%s is still running", m.running.Title()))
+		return
+	}
+	m.openReview(argv)
+}
+
+// execute carries out a confirmed review in the background with the
+// returned command; its result comes back as RunDoneMsg.
+func (m *GOALModel) execute(r *review) tea.Cmd {
+	if b, ok := m.builder.(iface.Batcher); ok && r.backend == iface.BackendNative {
+		return m.startBatch(b, r.txns)
+	}
+	m.running = m.builder
+	m.output, m.errLine = "", ""
+	if r.backend == iface.BackendNative {
+		return m.runNative(r.txns)
+	}
+	return m.runGoal(r.argv)
+}
+
+// runDone shows the result of a run and hands it to the builder that
+// started it; a transaction sent is announced to the other screens.
+func (m *GOALModel) runDone(msg RunDoneMsg) tea.Cmd {
+	b, res := m.running, msg.Result
+	if b == nil {
+		return nil
+	}
+	m.running = nil
 	m.output = strings.TrimSpace(res.Stdout)
 	if res.Err != nil {
 		if m.output == "" {
@@ -82,22 +210,82 @@ func (m *GOALModel) run(argv []string) {
 			m.errLine = fmt.Sprintf("error: %v", res.Err)
 		}
 	}
-	m.builder.AfterRun(res.Stdout, res.Stderr, res.Err)
+	b.AfterRun(res.Stdout, res.Stderr, res.Err)
+	if res.Err == nil {
+		m.markSent(b)
+	}
+	if msg.TxID == "" {
+		return nil
+	}
+	return m.app.Bus().Publish(appctx.Event{Kind: appctx.TxSubmitted, TxID: msg.TxID, Account: msg.Sender})
+}
+
+// IsEditing reports whether a review or prompt has the keyboard, so ESC
+// closes it instead of leaving the screen.
+func (m *GOALModel) IsEditing() bool { return m.review != nil || m.prompt != nil }
+
+// selectNav makes the builder under the nav cursor current; a template
+// keeps the last builder.
+func (m *GOALModel) selectNav() {
+	if m.nav.Cursor < len(m.builders) {
+		m.builder = m.builders[m.nav.Cursor]
+	}
 }
 
 func (m *GOALModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch t := msg.(type) {
+	case BatchSentMsg:
+		return m, m.batchSent(t)
+	case RunDoneMsg:
+		return m, m.runDone(t)
+	case CheckedMsg:
+		// checks run in the background, another builder may be on screen
+		for _, b := range m.builders {
+			t.Current = b == m.builder
+			b.Update(t)
+		}
+		return m, nil
+	case LoadedMsg:
+		for _, b := range m.builders {
+			b.Update(t)
+		}
+		return m, nil
 	case tea.KeyMsg:
+		if m.review != nil {
+			if t.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			return m.handleReview(t)
+		}
+		if m.prompt != nil {
+			if t.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			return m.handlePrompt(t)
+		}
 		switch t.String() {
 		case "ctrl+c", "esc":
+			m.SaveDraft()
 			return m, tea.Quit
+		case "ctrl+b":
+			m.cycleBackend()
+			return m, nil
 		case "up":
+			m.SaveDraft()
 			m.nav.Up()
-			m.builder = m.builders[m.nav.Cursor]
+			m.selectNav()
 			return m, nil
 		case "down":
+			m.SaveDraft()
 			m.nav.Down()
-			m.builder = m.builders[m.nav.Cursor]
+			m.selectNav()
+			return m, nil
+		}
+		if tmpl, ok := m.selectedTemplate(); ok {
+			return m.handleTemplate(tmpl, t)
+		}
+		if t.String() == "ctrl+t" {
+			m.openSave()
 			return m, nil
 		}
 	}
@@ -109,6 +297,15 @@ func (m *GOALModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m *GOALModel) View() string {
 	left := m.nav.Render()
 	right := m.builder.View()
+	if tmpl, ok := m.selectedTemplate(); ok {
+		right = m.renderTemplate(tmpl)
+	}
+	switch {
+	case m.review != nil:
+		right = m.renderReview()
+	case m.prompt != nil:
+		right = m.renderPrompt()
+	}
 	footer := m.renderFooter()
 	return lipgloss.JoinVertical(lipgloss.Left,
 		lipgloss.JoinHorizontal(lipgloss.Top, left, "  ", right),
@@ -118,11 +315,33 @@ func (m *GOALModel) View() string {
 }
 
 func (m *GOALModel) renderFooter() string {
+	backend := "auto"
+	if choice := m.profileBackend(); choice != "" {
+		backend = choice
+	}
+	if resolved, err := m.backend(); err == nil {
+		backend += " → " + string(resolved)
+	}
 	info := []string{
 		"Tab/Shift+Tab or Up/Down: Navigate fields",
 		"Enter: Run command",
+		"Ctrl+T: Save as template",
+		"Ctrl+B: Backend (" + backend + ")",
 		"ESC/Ctrl+C: Close",
 	}
+	if _, ok := m.selectedTemplate(); ok {
+		info = []string{"Up/Down: Navigate", "Enter: Load template", "Ctrl+X: Delete template", "ESC/Ctrl+C: Close"}
+	}
+	switch {
+	case m.review != nil:
+		info = []string{"Enter: Send", "Esc: Back to the form", "Ctrl+C: Close"}
+	case m.prompt != nil:
+		info = []string{"Enter: OK", "Esc: Cancel", "Ctrl+C: Close"}
+	case m.batch != nil:
+		info = append([]string{fmt.Sprintf("Sending batch %d of %d", m.batch.done+1, len(m.batch.batches))}, info...)
+	case m.running != nil:
+		info = append([]string{"Running " + m.running.Title() + "..."}, info...)
+	}
 	line := strings.Join(info, " | ")
 	return lipgloss.NewStyle().Faint(true).Render(line)
 }
@@ -0,0 +1,266 @@
+package goal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+	tea "github.com/charmbracelet/bubbletea"
+
+	algo "lazychain/lib"
+	"lazychain/models/appctx"
+	"lazychain/models/goal/iface"
+	"lazychain/models/settings"
+)
+
+// confirmRounds is how long the native backend waits for confirmation,
+// like goal does by default.
+const confirmRounds = 4
+
+// batchStop is how many batches in a row may fail to send before a
+// batch run stops; the rest is left for the next run.
+const batchStop = 3
+
+// Backend choices stored in a profile; "" picks automatically.
+var backendChoices = []string{"", string(iface.BackendGoal), string(iface.BackendNative)}
+
+func supports(b Builder, backend iface.Backend) bool {
+	for _, s := range b.Backends() {
+		if s == backend {
+			return true
+		}
+	}
+	return false
+}
+
+// profileBackend is the backend chosen in the active profile, "" for auto.
+func (m *GOALModel) profileBackend() string {
+	if p, ok := m.app.Profile(); ok {
+		return p.Backend
+	}
+	return ""
+}
+
+// backend resolves the backend for the current builder: the profile's
+// choice, or goal when the binary and a data dir are there and native
+// otherwise.
+func (m *GOALModel) backend() (iface.Backend, error) {
+	switch choice := iface.Backend(m.profileBackend()); choice {
+	case iface.BackendGoal, iface.BackendNative:
+		if !supports(m.builder, choice) {
+			return choice, fmt.Errorf("%s does not support the %s backend, switch with ctrl+b", m.builder.Title(), choice)
+		}
+		return choice, nil
+	}
+	if supports(m.builder, iface.BackendGoal) && m.runner.CheckBinary() == nil && m.runner.HasDataDir() {
+		return iface.BackendGoal, nil
+	}
+	if supports(m.builder, iface.BackendNative) {
+		return iface.BackendNative, nil
+	}
+	if err := m.runner.CheckBinary(); err != nil {
+		return iface.BackendGoal, fmt.Errorf("%w, and %s has no native backend", err, m.builder.Title())
+	}
+	return iface.BackendGoal, nil
+}
+
+// cycleBackend switches the active profile to the next backend choice.
+func (m *GOALModel) cycleBackend() {
+	current := m.profileBackend()
+	next := backendChoices[0]
+	for i, c := range backendChoices {
+		if c == current {
+			next = backendChoices[(i+1)%len(backendChoices)]
+		}
+	}
+	if err := m.app.UpdateProfile(func(p *settings.Profile) { p.Backend = next }); err != nil {
+		m.errLine = "error: " + err.Error()
+	}
+}
+
+// chain reads the connected network for builders that need balances or
+// asset parameters; no key is involved.
+func (m *GOALModel) chain() (*algo.AlgoClient, error) {
+	if !m.app.Network().IsConnected() {
+		return nil, errors.New("not connected to any network")
+	}
+	return algo.NewClientFrom(m.app.Algod(), m.app.Indexer()), nil
+}
+
+// account is addr, or the active account when a builder leaves it empty.
+func (m *GOALModel) account(addr string) string {
+	if addr == "" {
+		return m.app.Account()
+	}
+	return addr
+}
+
+// nativeTransactions builds the current builder's SDK transactions, sent
+// by the active account.
+func (m *GOALModel) nativeTransactions(nb iface.NativeBuilder) ([]types.Transaction, error) {
+	signer, err := m.app.Signer()
+	if err != nil {
+		return nil, err
+	}
+	sp, err := signer.SuggestedParams()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get suggested params: %w", err)
+	}
+	return nb.Transactions(signer.Address(), sp)
+}
+
+// RunDoneMsg carries the result of a run started by execute. TxID is set
+// once a native run sent its transaction, signed by Sender.
+type RunDoneMsg struct {
+	Result RunResult
+	TxID   string
+	Sender string
+}
+
+// runNative signs reviewed transactions with the active account and
+// sends them to the connected algod in the background.
+func (m *GOALModel) runNative(txns []types.Transaction) tea.Cmd {
+	if len(txns) == 0 {
+		return done(RunResult{Err: errors.New("nothing to send")})
+	}
+	signer, err := m.app.Signer()
+	if err != nil {
+		return done(RunResult{Err: err})
+	}
+	m.app.Approve(txns)
+	network := m.app.Network().GetCurrentNetwork().Name
+	return func() tea.Msg {
+		txID, err := signer.SignAndSend(txns...)
+		if err != nil {
+			return RunDoneMsg{Result: RunResult{Err: err}}
+		}
+
+		var out strings.Builder
+		fmt.Fprintf(&out, "Transaction %s sent to %s\n", txID, network)
+		round, err := signer.WaitForConfirmation(txID, confirmRounds)
+		if err != nil {
+			fmt.Fprintf(&out, "Not confirmed yet: %v", err)
+		} else {
+			fmt.Fprintf(&out, "Transaction %s committed in round %d", txID, round)
+		}
+		return RunDoneMsg{Result: RunResult{Stdout: out.String()}, TxID: txID, Sender: signer.Address()}
+	}
+}
+
+// runGoal runs argv with goal in the background, on a copy of the runner
+// so a profile switch meanwhile does not change the flags.
+func (m *GOALModel) runGoal(argv []string) tea.Cmd {
+	runner := *m.runner
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		return RunDoneMsg{Result: runner.Run(ctx, argv)}
+	}
+}
+
+func done(res RunResult) tea.Cmd {
+	return func() tea.Msg { return RunDoneMsg{Result: res} }
+}
+
+// BatchSentMsg reports one batch of a batch run, see iface.Batcher.
+type BatchSentMsg struct {
+	Batch int
+	TxIDs []string
+	// Sent is set once the node took the batch; Rejected when it surely
+	// did not, neither when the outcome is unknown, e.g. on a timeout
+	Sent     bool
+	Rejected bool
+	Round    uint64
+	Err      error
+}
+
+// batchRun is a batch builder's reviewed transactions being sent, one
+// batch after the other.
+type batchRun struct {
+	builder iface.Batcher
+	signer  *algo.AlgoClient
+	batches [][]types.Transaction
+	// done is how many batches went through, sent or not
+	done int
+	// failed counts the batches in a row the node did not take
+	failed int
+}
+
+// startBatch sends the reviewed transactions of b batch by batch in the
+// background, each confirmed before the next.
+func (m *GOALModel) startBatch(b iface.Batcher, txns []types.Transaction) tea.Cmd {
+	signer, err := m.app.Signer()
+	if err != nil {
+		b.AfterRun("", "", err)
+		return nil
+	}
+	batches := b.Batches(txns)
+	// groups are set now, so each batch is recorded with its final IDs
+	// before it leaves; approvals follow the IDs
+	for _, batch := range batches {
+		if len(batch) > 1 {
+			if err := algo.AssignGroup(batch); err != nil {
+				b.AfterRun("", "", err)
+				return nil
+			}
+		}
+		m.app.Approve(batch)
+	}
+	m.batch = &batchRun{builder: b, signer: signer, batches: batches}
+	return m.sendBatch(0)
+}
+
+// sendBatch records batch i as sent with the builder, then submits it and
+// waits for its confirmation in the background.
+func (m *GOALModel) sendBatch(i int) tea.Cmd {
+	run := m.batch
+	txns := run.batches[i]
+	ids := make([]string, len(txns))
+	for j := range txns {
+		ids[j] = crypto.GetTxID(txns[j])
+	}
+	if err := run.builder.BatchSending(i, ids); err != nil {
+		m.batch = nil
+		run.builder.AfterRun("", "", fmt.Errorf("batch %d not sent, it could not be recorded: %w", i+1, err))
+		return nil
+	}
+	signer := run.signer
+	return func() tea.Msg {
+		if _, err := signer.SignAndSend(txns...); err != nil {
+			return BatchSentMsg{Batch: i, TxIDs: ids, Rejected: algo.Rejected(err), Err: err}
+		}
+		round, err := signer.WaitForConfirmation(ids[0], confirmRounds)
+		return BatchSentMsg{Batch: i, TxIDs: ids, Sent: true, Round: round, Err: err}
+	}
+}
+
+// batchSent records a batch and sends the next one.
+func (m *GOALModel) batchSent(msg BatchSentMsg) tea.Cmd {
+	run := m.batch
+	if run == nil {
+		return nil
+	}
+	reload := run.builder.BatchDone(msg.Batch, msg.TxIDs, msg.Round, msg.Rejected, msg.Err)
+	run.done = msg.Batch + 1
+	if !msg.Sent {
+		run.failed++
+	} else {
+		run.failed = 0
+		reload = tea.Batch(reload, m.app.Bus().Publish(appctx.Event{Kind: appctx.TxSubmitted, TxID: msg.TxIDs[0], Account: run.signer.Address()}))
+	}
+	next := msg.Batch + 1
+	switch {
+	case run.failed >= batchStop && next < len(run.batches):
+		m.batch = nil
+		run.builder.AfterRun("", "", fmt.Errorf("stopped after %d batches in a row failed (%v), validate again to resume", run.failed, msg.Err))
+		return reload
+	case next < len(run.batches):
+		return tea.Batch(reload, m.sendBatch(next))
+	}
+	m.batch = nil
+	return reload
+}
@@ -0,0 +1,358 @@
+package goal
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"lazychain/models/goal/components"
+)
+
+const nodeRefreshInterval = 3 * time.Second
+
+// NodeStatusMsg carries the result of a `goal node status` run.
+type NodeStatusMsg struct {
+	Status NodeStatus
+	Err    error
+	At     time.Time
+}
+
+// NodeActionMsg carries the result of start/stop/restart/catchup.
+type NodeActionMsg struct {
+	Action string
+	Output string
+	Err    error
+}
+
+// NodeTickMsg drives the periodic status refresh while the view is open.
+type NodeTickMsg struct{ id int }
+
+// NodeModel is a small control panel for a locally running node, driven
+// through `goal node ...` with the Runner's data directories.
+type NodeModel struct {
+	runner *Runner
+
+	status    NodeStatus
+	statusAt  time.Time
+	statusErr error
+
+	busy    string // action currently running, empty when idle
+	lastOut string
+
+	// Inline inputs
+	editing string // "", "catchpoint" or "datadir"
+	input   components.Field
+
+	visible bool
+	tickID  int
+}
+
+func NewNodeModel(runner *Runner) *NodeModel {
+	if runner == nil {
+		runner = NewRunner()
+	}
+	return &NodeModel{runner: runner}
+}
+
+func (m *NodeModel) Init() tea.Cmd { return nil }
+
+// Start is called when the view becomes visible: it fetches the status
+// immediately and starts the refresh ticker.
+func (m *NodeModel) Start() tea.Cmd {
+	m.visible = true
+	m.tickID++
+	return tea.Batch(m.statusCmd(), m.tickCmd())
+}
+
+// Stop halts the refresh ticker when leaving the view.
+func (m *NodeModel) Stop() { m.visible = false }
+
+// IsEditing reports whether an inline input has focus (ESC cancels it
+// instead of leaving the view).
+func (m *NodeModel) IsEditing() bool { return m.editing != "" }
+
+func (m *NodeModel) tickCmd() tea.Cmd {
+	id := m.tickID
+	return tea.Tick(nodeRefreshInterval, func(time.Time) tea.Msg { return NodeTickMsg{id: id} })
+}
+
+func (m *NodeModel) statusCmd() tea.Cmd {
+	runner := m.runner
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		res := runner.Run(ctx, []string{"node", "status"})
+		if res.Err != nil {
+			return NodeStatusMsg{Err: runError(res), At: time.Now()}
+		}
+		return NodeStatusMsg{Status: ParseNodeStatus(res.Stdout), At: time.Now()}
+	}
+}
+
+func (m *NodeModel) actionCmd(action string, argv []string) tea.Cmd {
+	runner := m.runner
+	m.busy = action
+	return func() tea.Msg {
+		// start/restart wait for the node to come up, give them room
+		ctx, cancel := context.WithTimeout(context.Background(), 90*time.Second)
+		defer cancel()
+		res := runner.Run(ctx, argv)
+		out := strings.TrimSpace(res.Stdout + "\n" + res.Stderr)
+		if res.Err != nil {
+			return NodeActionMsg{Action: action, Output: out, Err: runError(res)}
+		}
+		return NodeActionMsg{Action: action, Output: out}
+	}
+}
+
+func runError(res RunResult) error {
+	if msg := strings.TrimSpace(res.Stderr); msg != "" {
+		return fmt.Errorf("%v: %s", res.Err, msg)
+	}
+	return res.Err
+}
+
+func (m *NodeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case NodeTickMsg:
+		if !m.visible || msg.id != m.tickID {
+			return m, nil
+		}
+		return m, tea.Batch(m.statusCmd(), m.tickCmd())
+
+	case NodeStatusMsg:
+		m.statusAt = msg.At
+		m.statusErr = msg.Err
+		if msg.Err == nil {
+			m.status = msg.Status
+		}
+		return m, nil
+
+	case NodeActionMsg:
+		m.busy = ""
+		if msg.Err != nil {
+			m.lastOut = fmt.Sprintf("%s failed: %v", msg.Action, msg.Err)
+		} else if msg.Output != "" {
+			m.lastOut = msg.Output
+		} else {
+			m.lastOut = msg.Action + " done"
+		}
+		return m, m.statusCmd()
+
+	case tea.KeyMsg:
+		if m.editing != "" {
+			return m.handleInput(msg)
+		}
+		if m.busy != "" {
+			return m, nil
+		}
+		switch msg.String() {
+		case "r":
+			return m, m.statusCmd()
+		case "s":
+			return m, m.actionCmd("start", []string{"node", "start"})
+		case "x":
+			return m, m.actionCmd("stop", []string{"node", "stop"})
+		case "R":
+			return m, m.actionCmd("restart", []string{"node", "restart"})
+		case "a":
+			if m.status.CatchingUp() {
+				return m, m.actionCmd("abort catchup", []string{"node", "catchup", "--abort"})
+			}
+		case "c":
+			m.editing = "catchpoint"
+			m.input = components.Field{Label: "Catchpoint", Hint: "round#LABEL", Active: true}
+		case "d":
+			m.editing = "datadir"
+			m.input = components.Field{Label: "Data dir (-d)", Hint: "empty uses $ALGORAND_DATA", Active: true}
+			if len(m.runner.DataDirs) > 0 {
+				m.input.SetValue(m.runner.DataDirs[0])
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m *NodeModel) handleInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.editing = ""
+		return m, nil
+	case "enter":
+		value := strings.TrimSpace(m.input.Value)
+		field := m.editing
+		m.editing = ""
+		switch field {
+		case "catchpoint":
+			if value == "" {
+				return m, nil
+			}
+			return m, m.actionCmd("catchup", []string{"node", "catchup", value})
+		case "datadir":
+			if value == "" {
+				m.runner.DataDirs = nil
+			} else {
+				m.runner.DataDirs = []string{value}
+			}
+			return m, m.statusCmd()
+		}
+		return m, nil
+	}
+	m.input.HandleKey(msg)
+	return m, nil
+}
+
+func (m *NodeModel) View() string {
+	left := m.renderStatusPanel()
+	right := m.renderControlPanel()
+	return lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.JoinHorizontal(lipgloss.Top, left, "  ", right),
+		"",
+		m.renderFooter(),
+	)
+}
+
+func (m *NodeModel) renderStatusPanel() string {
+	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#cba6f7")).Render("Node Status")
+	content := []string{title, ""}
+
+	dataDir := "$ALGORAND_DATA"
+	if len(m.runner.DataDirs) > 0 {
+		dataDir = strings.Join(m.runner.DataDirs, ", ")
+	}
+	content = append(content, "Data dir: "+truncateMiddle(dataDir, 20), "")
+
+	switch {
+	case m.statusErr != nil:
+		content = append(content,
+			lipgloss.NewStyle().Foreground(lipgloss.Color("#f38ba8")).Render("Node unreachable"),
+			lipgloss.NewStyle().Faint(true).Width(30).Render(m.statusErr.Error()))
+	case m.statusAt.IsZero():
+		content = append(content, lipgloss.NewStyle().Faint(true).Render("Fetching status..."))
+	default:
+		s := m.status
+		state := lipgloss.NewStyle().Foreground(lipgloss.Color("#a6e3a1")).Bold(true).Render("In sync")
+		if s.Syncing() {
+			state = lipgloss.NewStyle().Foreground(lipgloss.Color("#f9e2af")).Bold(true).Render("Syncing")
+		}
+		content = append(content,
+			"State: "+state,
+			fmt.Sprintf("Last round: %d", s.LastRound),
+			fmt.Sprintf("Since last round: %s", s.TimeSinceLastRound.Round(100*time.Millisecond)),
+		)
+		if s.SyncTime > 0 {
+			content = append(content, fmt.Sprintf("Sync time: %s", s.SyncTime.Round(time.Second)))
+		}
+		if s.GenesisID != "" {
+			content = append(content, "Genesis: "+s.GenesisID)
+		}
+		content = append(content, "")
+		if s.CatchingUp() {
+			phase, progress := s.CatchupPhase()
+			content = append(content,
+				lipgloss.NewStyle().Bold(true).Render("Fast catchup"),
+				truncateMiddle(s.Catchpoint, 28),
+				phase,
+				progressBar(progress, 20),
+			)
+		} else {
+			last := s.LastCatchpoint
+			if last == "" {
+				last = "(none)"
+			}
+			content = append(content, "Last catchpoint:", "  "+truncateMiddle(last, 26))
+		}
+		content = append(content, "",
+			lipgloss.NewStyle().Faint(true).Render("Updated "+m.statusAt.Format("15:04:05")))
+	}
+
+	for len(content) < 12 {
+		content = append(content, "")
+	}
+	return lipgloss.NewStyle().
+		Width(30).
+		Padding(2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#89b4fa")).
+		Render(strings.Join(content, "\n"))
+}
+
+func (m *NodeModel) renderControlPanel() string {
+	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#a6e3a1")).Render("Controls")
+	content := []string{title, ""}
+
+	if m.editing != "" {
+		content = append(content, m.input.Render(37), "",
+			lipgloss.NewStyle().Italic(true).Foreground(lipgloss.Color("#f9e2af")).Render("Enter to confirm, ESC to cancel"))
+	} else {
+		keys := []string{
+			"s  start node",
+			"x  stop node",
+			"R  restart node",
+			"c  fast catchup from catchpoint",
+			"d  set data dir",
+			"r  refresh status",
+		}
+		if m.status.CatchingUp() {
+			keys = append(keys, "a  abort catchup")
+		}
+		content = append(content, keys...)
+	}
+
+	content = append(content, "")
+	if m.busy != "" {
+		content = append(content, lipgloss.NewStyle().Foreground(lipgloss.Color("#f9e2af")).Render("Running "+m.busy+"..."))
+	} else if m.lastOut != "" {
+		content = append(content, lipgloss.NewStyle().Width(41).Render(m.lastOut))
+	}
+
+	for len(content) < 12 {
+		content = append(content, "")
+	}
+	return lipgloss.NewStyle().
+		Width(45).
+		Padding(2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#a6e3a1")).
+		Render(strings.Join(content, "\n"))
+}
+
+func (m *NodeModel) renderFooter() string {
+	instructions := []string{"s/x/R: Start/Stop/Restart", "c: Catchup", "r: Refresh", "ESC: Back"}
+	if m.editing != "" {
+		instructions = []string{"Enter: Confirm", "ESC: Cancel"}
+	}
+	return lipgloss.NewStyle().
+		Width(77).
+		Align(lipgloss.Center).
+		Foreground(lipgloss.Color("#6c7086")).
+		Italic(true).
+		Render(strings.Join(instructions, " | "))
+}
+
+// progressBar renders a fixed-width text progress bar.
+func progressBar(ratio float64, width int) string {
+	if ratio < 0 {
+		ratio = 0
+	}
+	if ratio > 1 {
+		ratio = 1
+	}
+	filled := int(ratio * float64(width))
+	return "[" + strings.Repeat("█", filled) + strings.Repeat("░", width-filled) + "]" +
+		fmt.Sprintf(" %3.0f%%", ratio*100)
+}
+
+// truncateMiddle shortens s to max runes, keeping both ends visible.
+func truncateMiddle(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max || max < 5 {
+		return s
+	}
+	half := (max - 3) / 2
+	return string(r[:half]) + "..." + string(r[len(r)-(max-3-half):])
+}
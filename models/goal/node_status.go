@@ -0,0 +1,117 @@
+package goal
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NodeStatus is the parsed output of `goal node status`.
+type NodeStatus struct {
+	LastRound          uint64
+	TimeSinceLastRound time.Duration
+	SyncTime           time.Duration
+	LastProtocol       string
+	NextProtocol       string
+	LastCatchpoint     string
+	GenesisID          string
+	GenesisHash        string
+
+	// Fast catchup progress, only set while a catchpoint catchup is running.
+	Catchpoint          string
+	CatchpointAccounts  uint64
+	CatchpointProcessed uint64
+	CatchpointVerified  uint64
+	CatchpointBlocks    uint64
+	CatchpointDownload  uint64
+}
+
+// Syncing reports whether the node is still catching up with the network.
+func (s NodeStatus) Syncing() bool { return s.SyncTime > 0 || s.CatchingUp() }
+
+// CatchingUp reports whether a catchpoint (fast) catchup is in progress.
+func (s NodeStatus) CatchingUp() bool { return s.Catchpoint != "" }
+
+// CatchupPhase returns a human label and a 0..1 progress for the current
+// catchpoint catchup stage.
+func (s NodeStatus) CatchupPhase() (string, float64) {
+	ratio := func(done, total uint64) float64 {
+		if total == 0 {
+			return 0
+		}
+		return float64(done) / float64(total)
+	}
+	switch {
+	case s.CatchpointBlocks > 0:
+		return "downloading blocks", ratio(s.CatchpointDownload, s.CatchpointBlocks)
+	case s.CatchpointVerified > 0:
+		return "verifying accounts", ratio(s.CatchpointVerified, s.CatchpointAccounts)
+	case s.CatchpointAccounts > 0:
+		return "processing accounts", ratio(s.CatchpointProcessed, s.CatchpointAccounts)
+	default:
+		return "downloading catchpoint", 0
+	}
+}
+
+// ParseNodeStatus parses the "Key: value" lines printed by `goal node status`.
+// Unknown keys are ignored so newer goal versions keep working.
+func ParseNodeStatus(out string) NodeStatus {
+	var s NodeStatus
+	for _, line := range strings.Split(out, "\n") {
+		key, val, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		val = strings.TrimSpace(val)
+
+		switch key {
+		case "last committed block":
+			s.LastRound = parseUint(val)
+		case "time since last block":
+			s.TimeSinceLastRound = parseSeconds(val)
+		case "sync time":
+			s.SyncTime = parseSeconds(val)
+		case "last consensus protocol":
+			s.LastProtocol = val
+		case "next consensus protocol":
+			s.NextProtocol = val
+		case "last catchpoint":
+			s.LastCatchpoint = val
+		case "genesis id":
+			s.GenesisID = val
+		case "genesis hash":
+			s.GenesisHash = val
+		case "catchpoint":
+			s.Catchpoint = val
+		case "catchpoint total accounts":
+			s.CatchpointAccounts = parseUint(val)
+		case "catchpoint accounts processed":
+			s.CatchpointProcessed = parseUint(val)
+		case "catchpoint accounts verified":
+			s.CatchpointVerified = parseUint(val)
+		case "catchpoint total blocks":
+			s.CatchpointBlocks = parseUint(val)
+		case "catchpoint downloaded blocks":
+			s.CatchpointDownload = parseUint(val)
+		}
+	}
+	return s
+}
+
+func parseUint(s string) uint64 {
+	v, _ := strconv.ParseUint(strings.TrimSpace(s), 10, 64)
+	return v
+}
+
+// parseSeconds accepts goal's "3.2s" format as well as Go durations.
+func parseSeconds(s string) time.Duration {
+	if d, err := time.ParseDuration(s); err == nil {
+		return d
+	}
+	f, err := strconv.ParseFloat(strings.TrimSuffix(s, "s"), 64)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(f * float64(time.Second))
+}
@@ -0,0 +1,81 @@
+package goal
+
+import (
+	"testing"
+	"time"
+)
+
+const protocolURL = "https://github.com/algorandfoundation/specs/tree/abd3d4823c6f77349fc04c3af7b1e99fe4df699f"
+
+func TestParseNodeStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want NodeStatus
+	}{
+		{
+			"synced",
+			"Last committed block: 31265438\n" +
+				"Time since last block: 2.5s\n" +
+				"Sync Time: 0.0s\n" +
+				"Last consensus protocol: " + protocolURL + "\n" +
+				"Next consensus protocol: " + protocolURL + "\n" +
+				"Round for next consensus protocol: 31265439\n" +
+				"Next consensus protocol supported: true\n" +
+				"Last Catchpoint: 31260000#ZJ6GQXS3\n" +
+				"Genesis ID: mainnet-v1.0\n" +
+				"Genesis hash: wGHE2Pwdvd7S12BL5FaOP20EGYesN73ktiC1qzkkit8=\n",
+			NodeStatus{
+				LastRound: 31265438, TimeSinceLastRound: 2500 * time.Millisecond,
+				LastProtocol: protocolURL, NextProtocol: protocolURL, LastCatchpoint: "31260000#ZJ6GQXS3",
+				GenesisID: "mainnet-v1.0", GenesisHash: "wGHE2Pwdvd7S12BL5FaOP20EGYesN73ktiC1qzkkit8=",
+			},
+		},
+		{
+			"catching up",
+			"Last committed block: 0\r\n" +
+				"Sync Time: 12.3s\r\n" +
+				"Catchpoint: 31260000#ZJ6GQXS3\r\n" +
+				"Catchpoint total accounts: 1000\r\n" +
+				"Catchpoint accounts processed: 1000\r\n" +
+				"Catchpoint accounts verified: 400\r\n",
+			NodeStatus{
+				SyncTime: 12300 * time.Millisecond, Catchpoint: "31260000#ZJ6GQXS3",
+				CatchpointAccounts: 1000, CatchpointProcessed: 1000, CatchpointVerified: 400,
+			},
+		},
+		{
+			"go durations and noise",
+			"Time since last block: 1m2s\nnot a status line\nUnknown key: 7\nLast committed block: many\n",
+			NodeStatus{TimeSinceLastRound: 62 * time.Second},
+		},
+		{"empty", "", NodeStatus{}},
+	}
+	for _, tt := range tests {
+		if got := ParseNodeStatus(tt.out); got != tt.want {
+			t.Errorf("%s:\ngot  %+v\nwant %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNodeStatusCatchup(t *testing.T) {
+	tests := []struct {
+		s       NodeStatus
+		phase   string
+		ratio   float64
+		syncing bool
+	}{
+		{NodeStatus{}, "downloading catchpoint", 0, false},
+		{NodeStatus{SyncTime: time.Second}, "downloading catchpoint", 0, true},
+		{NodeStatus{Catchpoint: "1#A", CatchpointAccounts: 4, CatchpointProcessed: 1}, "processing accounts", 0.25, true},
+		{NodeStatus{Catchpoint: "1#A", CatchpointAccounts: 4, CatchpointVerified: 2}, "verifying accounts", 0.5, true},
+		{NodeStatus{Catchpoint: "1#A", CatchpointBlocks: 10, CatchpointDownload: 10}, "downloading blocks", 1, true},
+	}
+	for _, tt := range tests {
+		phase, ratio := tt.s.CatchupPhase()
+		if phase != tt.phase || ratio != tt.ratio || tt.s.Syncing() != tt.syncing {
+			t.Errorf("%+v: got %q %v syncing %v, want %q %v syncing %v",
+				tt.s, phase, ratio, tt.s.Syncing(), tt.phase, tt.ratio, tt.syncing)
+		}
+	}
+}
@@ -0,0 +1,486 @@
+package goal
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/algorand/go-algorand-sdk/v2/types"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	algo "lazychain/lib"
+	"lazychain/models/goal/components"
+	"lazychain/models/goal/iface"
+	"lazychain/models/settings"
+)
+
+// confirmWord must be typed to send on mainnet, or when a transaction
+// rekeys or closes an account.
+const confirmWord = "send"
+
+// highFee is the fee, in μAlgos, above which the review warns.
+const highFee = 100_000
+
+// reviewDetailed is how many transactions are shown field by field;
+// longer batches are listed one line each, up to reviewLines.
+const (
+	reviewDetailed = 4
+	reviewLines    = 12
+)
+
+// unitInfo is an asset's decimals and unit name, err when unknown.
+type unitInfo struct {
+	decimals uint32
+	name     string
+	err      error
+}
+
+// review is what is about to be sent, shown before anything leaves:
+// nothing runs until it is confirmed.
+type review struct {
+	argv    []string
+	backend iface.Backend
+	txns    []types.Transaction
+	// amounts of txns as shown, looked up once
+	amounts []string
+	// totals of a batch, by asset, and its fees
+	totals []string
+	// decodeErr explains why txns could not be built for display
+	decodeErr error
+
+	network string
+	chain   settings.ChainIdentity
+	// goalChain is the genesis ID of goal's data dir, "" when unknown
+	goalChain string
+	mainnet   bool
+	// verified is false when the chain sent to could not be checked
+	// against the connected one
+	verified bool
+	warnings []string
+
+	// typed confirmation, when needed
+	typed components.Field
+}
+
+// needsTyping reports whether enter alone is not enough.
+func (r *review) needsTyping() bool {
+	if r.mainnet || !r.verified {
+		return true
+	}
+	for _, txn := range r.txns {
+		if !txn.RekeyTo.IsZero() || !txn.CloseRemainderTo.IsZero() || !txn.AssetCloseTo.IsZero() {
+			return true
+		}
+	}
+	return r.decodeErr != nil && hasFlag(r.argv, "--rekey-to", "--close-to")
+}
+
+func hasFlag(argv []string, flags ...string) bool {
+	for _, a := range argv {
+		for _, f := range flags {
+			if a == f {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// openReview prepares the review of argv for the resolved backend. The
+// native backend reviews, and later sends, the exact transactions it
+// signs; for goal they are decoded from the form when the builder can.
+func (m *GOALModel) openReview(argv []string) {
+	r := &review{argv: argv, typed: components.Field{Label: "Type \"" + confirmWord + "\" to confirm", Active: true}}
+	backend, err := m.backend()
+	if err != nil {
+		m.builder.AfterRun("", "", err)
+		return
+	}
+	r.backend = backend
+	nm := m.app.Network()
+	if nm.IsConnected() {
+		r.network = nm.GetCurrentNetwork().Name
+		r.chain = nm.Chain()
+		r.mainnet = r.chain.GenesisHash == settings.MainnetGenesisHash
+		r.verified = true
+	}
+	// goal sends to the chain of its data dir, whatever is connected
+	if backend == iface.BackendGoal {
+		if dir := m.runner.DataDir(); dir != "" {
+			r.goalChain, _ = settings.DataDirGenesisID(dir)
+		}
+		r.mainnet = r.mainnet || r.goalChain == settings.MainnetGenesisID
+		r.verified = r.verified && r.goalChain != "" && r.goalChain == r.chain.GenesisID
+	}
+
+	nb, native := m.builder.(iface.NativeBuilder)
+	rv, reviewer := m.builder.(iface.Reviewer)
+	switch {
+	case backend == iface.BackendNative && native:
+		r.txns, r.decodeErr = m.nativeTransactions(nb)
+		if r.decodeErr != nil {
+			// Nothing to send: report it like a failed run
+			m.builder.AfterRun("", "", r.decodeErr)
+			return
+		}
+	case reviewer:
+		r.txns, r.decodeErr = m.reviewTransactions(rv)
+	default:
+		r.decodeErr = errors.New("this builder cannot decode its transactions")
+	}
+	units := map[uint64]unitInfo{}
+	for _, txn := range r.txns {
+		r.amounts = append(r.amounts, m.amount(txn, units))
+	}
+	r.totals = m.totals(r.txns, units)
+	r.warnings = m.warnings(r)
+	m.review = r
+}
+
+// reviewTransactions builds the transactions goal will send, with the
+// node's current params; goal picks its own when it runs.
+func (m *GOALModel) reviewTransactions(b iface.Reviewer) ([]types.Transaction, error) {
+	c, err := m.chain()
+	if err != nil {
+		return nil, err
+	}
+	sp, err := c.SuggestedParams()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get suggested params: %w", err)
+	}
+	return b.Review(m.account(m.builderSender()), sp)
+}
+
+// builderSender is the From field of the builder's command, if any.
+func (m *GOALModel) builderSender() string {
+	argv := m.builder.Args()
+	for i, a := range argv {
+		if a == "-f" && i+1 < len(argv) {
+			return argv[i+1]
+		}
+	}
+	return ""
+}
+
+// warnings lists what deserves a second look before confirming.
+func (m *GOALModel) warnings(r *review) []string {
+	var w []string
+	var unknown []types.Address
+	var closes []string
+	switch {
+	case r.network == "":
+		w = append(w, "Not connected: the network goal sends to is not verified")
+	case r.backend == iface.BackendGoal && r.goalChain == "":
+		w = append(w, "The chain of goal's data dir is unknown, it may not be "+r.chain.GenesisID)
+	case r.backend == iface.BackendGoal && !r.verified:
+		w = append(w, "goal sends to "+r.goalChain+", not the connected "+r.chain.GenesisID+": fee and validity shown are not goal's")
+	}
+	if r.mainnet {
+		w = append(w, "MAINNET: real funds")
+	}
+	for _, txn := range r.txns {
+		if !txn.RekeyTo.IsZero() {
+			w = append(w, "REKEY: signing authority of "+m.label(txn.Sender)+" moves to "+m.label(txn.RekeyTo))
+		}
+		if !txn.CloseRemainderTo.IsZero() {
+			w = append(w, "CLOSE: all ALGO left in "+m.label(txn.Sender)+" goes to "+m.label(txn.CloseRemainderTo)+" and the account closes")
+		}
+		if !txn.AssetCloseTo.IsZero() {
+			closes = append(closes, fmt.Sprintf("CLOSE: all of asset %d left goes to %s and the account opts out", txn.XferAsset, m.label(txn.AssetCloseTo)))
+		}
+		if txn.Type == types.AssetConfigTx && txn.ConfigAsset == 0 && txn.AssetParams.Manager.IsZero() {
+			w = append(w, "IMMUTABLE: the asset has no manager, its roles can never change")
+		}
+		if !txn.AssetSender.IsZero() {
+			w = append(w, "CLAWBACK: taking the asset from "+m.label(txn.AssetSender))
+		}
+		if uint64(txn.Fee) > highFee {
+			w = append(w, "High fee: "+algo.Amount(txn.Fee).Algos())
+		}
+		for _, to := range []types.Address{txn.Receiver, txn.AssetReceiver} {
+			if !to.IsZero() && to != txn.Sender && !m.known(to) {
+				unknown = append(unknown, to)
+			}
+		}
+	}
+	if len(closes) > 3 {
+		w = append(w, fmt.Sprintf("CLOSE: the account opts out of %d assets, what is left of each goes to the close-to address", len(closes)))
+	} else {
+		w = append(w, closes...)
+	}
+	if len(unknown) > 3 {
+		w = append(w, fmt.Sprintf("%d receivers are not in the address book", len(unknown)))
+	} else {
+		for _, to := range unknown {
+			w = append(w, "Receiver "+short(to.String())+" is not in the address book")
+		}
+	}
+	if r.decodeErr != nil && hasFlag(r.argv, "--rekey-to") {
+		w = append(w, "REKEY requested (--rekey-to)")
+	}
+	if r.decodeErr != nil && hasFlag(r.argv, "--close-to") {
+		w = append(w, "CLOSE requested (--close-to)")
+	}
+	return w
+}
+
+// label names addr with its address book label when there is one.
+func (m *GOALModel) label(addr types.Address) string {
+	s := addr.String()
+	if p, ok := m.app.Profile(); ok {
+		if l, ok := p.LabelFor(s); ok {
+			return l + " (" + short(s) + ")"
+		}
+	}
+	return short(s)
+}
+
+func (m *GOALModel) known(addr types.Address) bool {
+	if addr.String() == m.app.Account() {
+		return true
+	}
+	p, ok := m.app.Profile()
+	if !ok {
+		return false
+	}
+	_, ok = p.LabelFor(addr.String())
+	return ok
+}
+
+func short(addr string) string {
+	if len(addr) > 12 {
+		return addr[:6] + "..." + addr[len(addr)-4:]
+	}
+	return addr
+}
+
+// handleReview handles keys while the review is open: esc goes back to
+// the form, enter sends once confirmed.
+func (m *GOALModel) handleReview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	r := m.review
+	switch msg.String() {
+	case "esc":
+		m.review = nil
+		m.builder.AfterRun("Cancelled at review, nothing was sent", "", nil)
+		return m, nil
+	case "enter":
+		if r.needsTyping() && strings.TrimSpace(r.typed.Value) != confirmWord {
+			r.typed.Value, r.typed.Cursor = "", 0
+			return m, nil
+		}
+		m.review = nil
+		return m, m.execute(r)
+	}
+	if r.needsTyping() {
+		r.typed.HandleKey(msg)
+	}
+	return m, nil
+}
+
+func (m *GOALModel) renderReview() string {
+	r := m.review
+	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#cba6f7"))
+	key := lipgloss.NewStyle().Bold(true).Width(12)
+	warn := lipgloss.NewStyle().Foreground(lipgloss.Color("#f38ba8"))
+	faint := lipgloss.NewStyle().Faint(true)
+
+	lines := []string{title.Render("Review: " + m.builder.Title()), ""}
+	row := func(k, v string) { lines = append(lines, key.Render(k)+v) }
+
+	network := "not connected"
+	if r.network != "" {
+		network = r.network + " (" + r.chain.GenesisID + ")"
+	}
+	row("Network", network)
+	if r.chain.GenesisHash != "" {
+		row("Genesis", r.chain.GenesisHash)
+	}
+	row("Backend", string(r.backend))
+	if r.backend == iface.BackendGoal {
+		goalChain := r.goalChain
+		if goalChain == "" {
+			goalChain = "unknown (no genesis.json in the data dir)"
+		}
+		row("goal chain", goalChain)
+	}
+
+	if len(r.txns) > reviewDetailed {
+		lines = append(lines, "", title.Render(fmt.Sprintf("%d transactions", len(r.txns))))
+		for i, txn := range r.txns {
+			if i == reviewLines {
+				lines = append(lines, faint.Render(fmt.Sprintf("... and %d more", len(r.txns)-reviewLines)))
+				break
+			}
+			to := txn.Receiver
+			if txn.Type == types.AssetTransferTx {
+				to = txn.AssetReceiver
+			}
+			lines = append(lines, fmt.Sprintf("%4d  %-6s → %-26s %s", i+1, txn.Type, m.label(to), r.amounts[i]))
+		}
+	}
+	for i, txn := range r.txns {
+		if len(r.txns) > reviewDetailed {
+			break
+		}
+		lines = append(lines, "")
+		if len(r.txns) > 1 {
+			lines = append(lines, title.Render(fmt.Sprintf("Transaction %d of %d", i+1, len(r.txns))))
+		}
+		row("Type", string(txn.Type))
+		row("From", m.label(txn.Sender))
+		switch txn.Type {
+		case types.PaymentTx:
+			row("To", m.label(txn.Receiver))
+			row("Amount", r.amounts[i])
+			if !txn.CloseRemainderTo.IsZero() {
+				row("Close to", m.label(txn.CloseRemainderTo))
+			}
+		case types.AssetTransferTx:
+			row("To", m.label(txn.AssetReceiver))
+			row("Amount", r.amounts[i])
+			if !txn.AssetSender.IsZero() {
+				row("Clawback", m.label(txn.AssetSender))
+			}
+			if !txn.AssetCloseTo.IsZero() {
+				row("Close to", m.label(txn.AssetCloseTo))
+			}
+		case types.AssetConfigTx:
+			p := txn.AssetParams
+			if txn.ConfigAsset == 0 {
+				row("Create", fmt.Sprintf("%s (%s)", p.AssetName, p.UnitName))
+				row("Supply", fmt.Sprintf("%s %s (%d base, %d decimals)", algo.Amount(p.Total).Decimal(p.Decimals), p.UnitName, p.Total, p.Decimals))
+			} else {
+				row("Asset", fmt.Sprint(txn.ConfigAsset))
+			}
+			if p.URL != "" {
+				row("URL", p.URL)
+			}
+			if p.MetadataHash != ([32]byte{}) {
+				row("Hash", base64.StdEncoding.EncodeToString(p.MetadataHash[:]))
+			}
+			for _, role := range []struct {
+				name string
+				addr types.Address
+			}{{"Manager", p.Manager}, {"Reserve", p.Reserve}, {"Freeze", p.Freeze}, {"Clawback", p.Clawback}} {
+				if role.addr.IsZero() {
+					row(role.name, "none")
+				} else {
+					row(role.name, m.label(role.addr))
+				}
+			}
+			if p.DefaultFrozen {
+				row("Frozen", "holdings start frozen")
+			}
+		}
+		row("Fee", algo.Amount(txn.Fee).Algos())
+		row("Valid", fmt.Sprintf("rounds %d to %d", txn.FirstValid, txn.LastValid))
+		if len(txn.Note) > 0 {
+			note := algo.DecodeNote(txn.Note)
+			row("Note", note.Format+": "+note.Text)
+		}
+		if !txn.RekeyTo.IsZero() {
+			row("Rekey to", m.label(txn.RekeyTo))
+		}
+	}
+	if len(r.totals) > 0 {
+		lines = append(lines, "")
+		for _, t := range r.totals {
+			row("Total", t)
+		}
+	}
+	if r.decodeErr != nil {
+		lines = append(lines, "", faint.Render("Cannot decode: "+r.decodeErr.Error()))
+	}
+	if r.backend == iface.BackendGoal {
+		lines = append(lines, "", faint.Render("goal "+strings.Join(r.argv, " ")))
+		if len(r.txns) > 0 {
+			lines = append(lines, faint.Render("goal picks fee and validity itself when it runs"))
+		}
+	}
+
+	if len(r.warnings) > 0 {
+		lines = append(lines, "")
+		for _, w := range r.warnings {
+			lines = append(lines, warn.Render("⚠ "+w))
+		}
+	}
+	lines = append(lines, "")
+	if r.needsTyping() {
+		lines = append(lines, r.typed.Render(40), "", faint.Render("Enter: Send | Esc: Back to the form"))
+	} else {
+		lines = append(lines, faint.Render("Enter: Send | Esc: Back to the form"))
+	}
+
+	return lipgloss.NewStyle().
+		Width(77).
+		Padding(1).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#f9e2af")).
+		Render(strings.Join(lines, "\n"))
+}
+
+// amount spells out what txn moves: ALGO in both units, assets with
+// their decimals when they can be looked up.
+func (m *GOALModel) amount(txn types.Transaction, units map[uint64]unitInfo) string {
+	switch txn.Type {
+	case types.PaymentTx:
+		return fmt.Sprintf("%s (%d µAlgo)", algo.Amount(txn.Amount).Algos(), uint64(txn.Amount))
+	case types.AssetTransferTx:
+		id, amount := uint64(txn.XferAsset), txn.AssetAmount
+		if u := m.units(id, units); u.err == nil {
+			return fmt.Sprintf("%s %s (%d base, asset %d)", algo.Amount(amount).Decimal(u.decimals), u.name, amount, id)
+		}
+		return fmt.Sprintf("%d base units of asset %d", amount, id)
+	}
+	return ""
+}
+
+// units looks an asset's decimals up once per review.
+func (m *GOALModel) units(id uint64, cache map[uint64]unitInfo) unitInfo {
+	if u, ok := cache[id]; ok {
+		return u
+	}
+	var u unitInfo
+	c, err := m.chain()
+	if err == nil {
+		u.decimals, u.name, u.err = c.AssetUnits(id)
+	} else {
+		u.err = err
+	}
+	cache[id] = u
+	return u
+}
+
+// totals sums what a batch moves, ALGO then each asset, and its fees.
+func (m *GOALModel) totals(txns []types.Transaction, units map[uint64]unitInfo) []string {
+	if len(txns) < 2 {
+		return nil
+	}
+	var algos, fees algo.Amount
+	assets := map[uint64]algo.Amount{}
+	var ids []uint64
+	for _, txn := range txns {
+		fees += algo.Amount(txn.Fee)
+		switch txn.Type {
+		case types.PaymentTx:
+			algos += algo.Amount(txn.Amount)
+		case types.AssetTransferTx:
+			id := uint64(txn.XferAsset)
+			if _, ok := assets[id]; !ok {
+				ids = append(ids, id)
+			}
+			assets[id] += algo.Amount(txn.AssetAmount)
+		}
+	}
+	out := []string{algos.Algos()}
+	for _, id := range ids {
+		if u := m.units(id, units); u.err == nil {
+			out = append(out, fmt.Sprintf("%s %s (asset %d)", assets[id].Decimal(u.decimals), u.name, id))
+		} else {
+			out = append(out, fmt.Sprintf("%d base units of asset %d", uint64(assets[id]), id))
+		}
+	}
+	return append(out, fees.Algos()+" in fees")
+}
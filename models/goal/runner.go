@@ -26,14 +26,21 @@ func NewRunner() *Runner {
 	}
 }
 
-// baseFlags composes [-d ...] [-k ...] [-w ...] for any goal command.
-func (r *Runner) baseFlags() []string {
+// walletCommands are the goal commands taking -w, the only ones given
+// the kmd dir and wallet; goal rejects -w anywhere else.
+var walletCommands = map[string]bool{"account": true, "clerk": true, "asset": true, "app": true}
+
+// baseFlags composes [-d ...] [-k ...] [-w ...] for the goal command argv.
+func (r *Runner) baseFlags(argv []string) []string {
 	var flags []string
 	for _, d := range r.DataDirs {
 		if strings.TrimSpace(d) != "" {
 			flags = append(flags, "-d", d)
 		}
 	}
+	if len(argv) == 0 || !walletCommands[argv[0]] {
+		return flags
+	}
 	if strings.TrimSpace(r.KmdDir) != "" {
 		flags = append(flags, "-k", r.KmdDir)
 	}
@@ -58,7 +65,7 @@ func (r *Runner) Run(ctx context.Context, argv []string) RunResult {
 		ctx, cancel = context.WithTimeout(context.Background(), r.Timeout)
 		defer cancel()
 	}
-	cmd := exec.CommandContext(ctx, r.Binary, append(r.baseFlags(), argv...)...)
+	cmd := exec.CommandContext(ctx, r.Binary, append(r.baseFlags(argv), argv...)...)
 	var out, errb bytes.Buffer
 	cmd.Stdout = &out
 	cmd.Stderr = &errb
@@ -68,6 +75,27 @@ func (r *Runner) Run(ctx context.Context, argv []string) RunResult {
 	return RunResult{Stdout: out.String(), Stderr: errb.String(), Err: err}
 }
 
+// HasDataDir reports whether goal knows which node to talk to, through
+// -d or $ALGORAND_DATA.
+func (r *Runner) HasDataDir() bool {
+	for _, d := range r.DataDirs {
+		if strings.TrimSpace(d) != "" {
+			return true
+		}
+	}
+	return strings.TrimSpace(os.Getenv("ALGORAND_DATA")) != ""
+}
+
+// DataDir is the data dir goal uses: the first -d, or $ALGORAND_DATA.
+func (r *Runner) DataDir() string {
+	for _, d := range r.DataDirs {
+		if strings.TrimSpace(d) != "" {
+			return d
+		}
+	}
+	return strings.TrimSpace(os.Getenv("ALGORAND_DATA"))
+}
+
 func (r *Runner) CheckBinary() error {
 	_, err := exec.LookPath(r.Binary)
 	if err != nil {
@@ -0,0 +1,264 @@
+package goal
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"lazychain/models/goal/components"
+	"lazychain/models/goal/iface"
+	"lazychain/models/settings"
+)
+
+// templateMark prefixes templates in the nav, below the builders.
+const templateMark = "★ "
+
+// prompt asks for a template name, or for the placeholders of a template
+// being loaded, one at a time.
+type prompt struct {
+	field components.Field
+	err   string
+
+	// saving is true while naming a new template
+	saving bool
+
+	// placeholders of tmpl still to fill, the current one first
+	tmpl  settings.Template
+	names []string
+	fills map[string]string
+}
+
+// loadStore reads templates and drafts, and puts each draft back in its
+// builder.
+func (m *GOALModel) loadStore() {
+	store, err := settings.LoadBuilderStore()
+	if err != nil {
+		m.builder.AfterRun("", "", err)
+		return
+	}
+	m.store = store
+	for _, b := range m.builders {
+		if t, ok := b.(iface.Templater); ok {
+			if draft, ok := store.Drafts[b.Title()]; ok {
+				t.SetValues(draft)
+			}
+		}
+	}
+	m.refreshNav()
+}
+
+func (m *GOALModel) saveStore() {
+	if err := settings.SaveBuilderStore(m.store); err != nil {
+		m.builder.AfterRun("", "", err)
+	}
+}
+
+// refreshNav lists the builders, then the templates.
+func (m *GOALModel) refreshNav() {
+	items := append([]string{}, m.builderItems...)
+	for _, t := range m.store.Templates {
+		name := t.Name
+		if len([]rune(name)) > 22 {
+			name = string([]rune(name)[:21]) + "…"
+		}
+		items = append(items, templateMark+name)
+	}
+	m.nav.Items = items
+	if m.nav.Cursor >= len(items) {
+		m.nav.Cursor = len(items) - 1
+	}
+}
+
+// selectedTemplate is the template under the nav cursor, if any.
+func (m *GOALModel) selectedTemplate() (settings.Template, bool) {
+	i := m.nav.Cursor - len(m.builders)
+	if i < 0 || i >= len(m.store.Templates) {
+		return settings.Template{}, false
+	}
+	return m.store.Templates[i], true
+}
+
+// SaveDraft keeps the unsent values of the current builder, so they
+// survive switching builders, leaving the screen and restarting. Values
+// just sent are not a draft.
+func (m *GOALModel) SaveDraft() {
+	t, ok := m.builder.(iface.Templater)
+	if !ok {
+		return
+	}
+	title := m.builder.Title()
+	values := t.Values()
+	if maps.Equal(values, m.sent[title]) {
+		values = nil
+	}
+	if maps.Equal(values, m.store.Drafts[title]) {
+		return
+	}
+	m.store.SetDraft(title, values)
+	m.saveStore()
+}
+
+// markSent records that b's values went out.
+func (m *GOALModel) markSent(b Builder) {
+	t, ok := b.(iface.Templater)
+	if !ok {
+		return
+	}
+	title := b.Title()
+	m.sent[title] = t.Values()
+	if _, ok := m.store.Drafts[title]; ok {
+		m.store.SetDraft(title, nil)
+		m.saveStore()
+	}
+}
+
+// openSave asks for the name to save the current builder's values under.
+func (m *GOALModel) openSave() {
+	t, ok := m.builder.(iface.Templater)
+	if !ok {
+		m.builder.AfterRun("", "", errors.New("this builder has no fields to save"))
+		return
+	}
+	if len(t.Values()) == 0 {
+		m.builder.AfterRun("", "", errors.New("nothing to save, fill some fields first"))
+		return
+	}
+	m.prompt = &prompt{
+		saving: true,
+		field:  components.Field{Label: "Template name", Hint: "e.g. weekly payroll to X", Active: true},
+	}
+}
+
+// load fills the template's builder, asking for its placeholders first.
+func (m *GOALModel) load(tmpl settings.Template) {
+	names := tmpl.Placeholders()
+	if len(names) == 0 {
+		m.apply(tmpl, nil)
+		return
+	}
+	m.prompt = &prompt{tmpl: tmpl, names: names, fills: map[string]string{}}
+	m.prompt.next()
+}
+
+func (p *prompt) next() {
+	p.field = components.Field{Label: "{{" + p.names[0] + "}}", Hint: "value for " + p.names[0], Active: true}
+}
+
+// apply switches to the template's builder and fills it.
+func (m *GOALModel) apply(tmpl settings.Template, fills map[string]string) {
+	for i, b := range m.builders {
+		if b.Title() != tmpl.Builder {
+			continue
+		}
+		t, ok := b.(iface.Templater)
+		if !ok {
+			break
+		}
+		m.nav.Cursor = i
+		m.builder = b
+		t.SetValues(tmpl.Fill(fills))
+		m.builder.AfterRun("Template \""+tmpl.Name+"\" loaded", "", nil)
+		return
+	}
+	m.builder.AfterRun("", "", fmt.Errorf("template %q is for %q, which is not available", tmpl.Name, tmpl.Builder))
+}
+
+// handlePrompt handles keys while a prompt is open: enter confirms, esc
+// cancels.
+func (m *GOALModel) handlePrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := m.prompt
+	switch msg.String() {
+	case "esc":
+		m.prompt = nil
+	case "enter":
+		value := strings.TrimSpace(p.field.Value)
+		if p.saving {
+			m.saveTemplate(value)
+			return m, nil
+		}
+		p.fills[p.names[0]] = value
+		if p.names = p.names[1:]; len(p.names) > 0 {
+			p.next()
+			return m, nil
+		}
+		m.prompt = nil
+		m.apply(p.tmpl, p.fills)
+	default:
+		p.field.HandleKey(msg)
+	}
+	return m, nil
+}
+
+func (m *GOALModel) saveTemplate(name string) {
+	t := m.builder.(iface.Templater)
+	tmpl := settings.Template{Name: name, Builder: m.builder.Title(), Values: t.Values()}
+	if err := m.store.PutTemplate(tmpl); err != nil {
+		m.prompt.err = err.Error()
+		return
+	}
+	m.prompt = nil
+	m.saveStore()
+	m.refreshNav()
+	m.builder.AfterRun("Template \""+tmpl.Name+"\" saved, {{name}} in a value is asked for on load", "", nil)
+}
+
+// handleTemplate handles keys while a template is selected in the nav.
+func (m *GOALModel) handleTemplate(tmpl settings.Template, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		m.load(tmpl)
+	case "ctrl+x":
+		m.store.DeleteTemplate(tmpl.Name)
+		m.saveStore()
+		m.refreshNav()
+		m.selectNav()
+	}
+	return m, nil
+}
+
+func (m *GOALModel) renderTemplate(tmpl settings.Template) string {
+	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#cba6f7"))
+	key := lipgloss.NewStyle().Bold(true).Width(14)
+	faint := lipgloss.NewStyle().Faint(true)
+
+	lines := []string{title.Render("Template: " + tmpl.Name), "", key.Render("Builder") + tmpl.Builder, ""}
+	keys := make([]string, 0, len(tmpl.Values))
+	for k := range tmpl.Values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		lines = append(lines, key.Render(k)+tmpl.Values[k])
+	}
+	if names := tmpl.Placeholders(); len(names) > 0 {
+		lines = append(lines, "", faint.Render("Asked for on load: {{"+strings.Join(names, "}}, {{")+"}}"))
+	}
+	return panel(lines)
+}
+
+func (m *GOALModel) renderPrompt() string {
+	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#cba6f7"))
+	heading := "Save " + m.builder.Title() + " as a template"
+	if !m.prompt.saving {
+		heading = "Load template: " + m.prompt.tmpl.Name
+	}
+	lines := []string{title.Render(heading), "", m.prompt.field.Render(60)}
+	if m.prompt.err != "" {
+		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("#f38ba8")).Render("✗ "+m.prompt.err))
+	}
+	return panel(lines)
+}
+
+func panel(lines []string) string {
+	return lipgloss.NewStyle().
+		Width(77).
+		Padding(1).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#89b4fa")).
+		Render(strings.Join(lines, "\n"))
+}
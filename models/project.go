@@ -16,8 +16,29 @@ type ProjectModel struct {
 	Options      []string
 	Cursor       int
 	Selected     map[int]struct{} // Selected items
+
+	// Profile switcher, filled by the main model
+	Profiles      []string
+	ActiveProfile string
+	picking       bool
+	profileCursor int
+	naming        bool
+	nameInput     string
+	ProfileErr    string
 }
 
+// ProfileSwitchMsg asks to make a profile active.
+type ProfileSwitchMsg struct{ Name string }
+
+// ProfileCreateMsg asks to save the current settings as a new profile.
+type ProfileCreateMsg struct{ Name string }
+
+// ProfileDeleteMsg asks to delete a profile.
+type ProfileDeleteMsg struct{ Name string }
+
+// newProfileItem is the last entry of the profile switcher.
+const newProfileItem = "+ New profile"
+
 func NewProjectModel() *ProjectModel {
 	return &ProjectModel{
 		Options: []string{
@@ -25,6 +46,9 @@ func NewProjectModel() *ProjectModel {
 			"Applications",
 			"Commands Goals",
 			"Explore",
+			"Dashboard",
+			"Node",
+			"Profiles",
 		},
 		Cursor:   0,
 		Selected: make(map[int]struct{}),
@@ -38,6 +62,9 @@ func (m *ProjectModel) Init() tea.Cmd {
 func (m *ProjectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.picking {
+			return m.updatePicker(msg)
+		}
 		switch msg.String() {
 		case "ctrl+c", "q":
 			return m, tea.Quit
@@ -59,6 +86,110 @@ func (m *ProjectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// OpenProfiles shows the profile switcher in place of the menu.
+func (m *ProjectModel) OpenProfiles() {
+	m.picking = true
+	m.naming = false
+	m.nameInput = ""
+	m.ProfileErr = ""
+	m.profileCursor = 0
+	for i, p := range m.Profiles {
+		if p == m.ActiveProfile {
+			m.profileCursor = i
+		}
+	}
+}
+
+// IsPicking reports whether the profile switcher is open.
+func (m *ProjectModel) IsPicking() bool { return m.picking }
+
+// IsNaming reports whether a new profile name is being typed.
+func (m *ProjectModel) IsNaming() bool { return m.naming }
+
+// NameInput is the profile name typed so far.
+func (m *ProjectModel) NameInput() string { return m.nameInput }
+
+// MenuItems returns the entries to show: menu options, or profiles
+// while the switcher is open.
+func (m *ProjectModel) MenuItems() ([]string, int) {
+	if !m.picking {
+		return m.Options, m.Cursor
+	}
+	items := make([]string, 0, len(m.Profiles)+1)
+	for _, p := range m.Profiles {
+		if p == m.ActiveProfile {
+			p += " *"
+		}
+		items = append(items, p)
+	}
+	return append(items, newProfileItem), m.profileCursor
+}
+
+// ProfileAt maps a switcher entry back to the profile name, "" for the
+// new profile entry.
+func (m *ProjectModel) ProfileAt(i int) string {
+	if i >= 0 && i < len(m.Profiles) {
+		return m.Profiles[i]
+	}
+	return ""
+}
+
+func (m *ProjectModel) updatePicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.naming {
+		switch msg.Type {
+		case tea.KeyEsc:
+			m.naming = false
+			m.nameInput = ""
+		case tea.KeyEnter:
+			name := strings.TrimSpace(m.nameInput)
+			m.naming = false
+			m.nameInput = ""
+			if name == "" {
+				return m, nil
+			}
+			return m, func() tea.Msg { return ProfileCreateMsg{Name: name} }
+		case tea.KeyBackspace:
+			if r := []rune(m.nameInput); len(r) > 0 {
+				m.nameInput = string(r[:len(r)-1])
+			}
+		case tea.KeyRunes:
+			m.nameInput += string(msg.Runes)
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.picking = false
+		m.ProfileErr = ""
+	case "up", "k":
+		if m.profileCursor > 0 {
+			m.profileCursor--
+		}
+	case "down", "j":
+		if m.profileCursor < len(m.Profiles) {
+			m.profileCursor++
+		}
+	case "enter", " ":
+		name := m.ProfileAt(m.profileCursor)
+		if name == "" {
+			m.naming = true
+			m.nameInput = ""
+			m.ProfileErr = ""
+			return m, nil
+		}
+		m.picking = false
+		return m, func() tea.Msg { return ProfileSwitchMsg{Name: name} }
+	case "d":
+		if name := m.ProfileAt(m.profileCursor); name != "" {
+			return m, func() tea.Msg { return ProfileDeleteMsg{Name: name} }
+		}
+	}
+	return m, nil
+}
+
 func (m *ProjectModel) View() string {
 	// Create two-column layout: Menu options (left) | Preview (right)
 	// Using same dimensions as SettingsModel for consistency
@@ -210,6 +341,12 @@ func subtitleFor(option string) string {
 		return "Why CLI when you can TUI? Build transactions easily"
 	case "Explore":
 		return "Explore blockchain data and resources"
+	case "Node":
+		return "Start, stop and catch up your own node with goal"
+	case "Dashboard":
+		return "Live network metrics, refreshed on every block"
+	case "Profiles":
+		return "Switch between named contexts: network, account, node and address book"
 	default:
 		return ""
 	}
@@ -0,0 +1,103 @@
+package settings
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Address book panel modes.
+const (
+	bookModeList    = "list"
+	bookModeLabel   = "label"
+	bookModeAddress = "address"
+)
+
+// contacts returns the address book of the active profile.
+func (m *SettingsModel) contacts() []Contact {
+	p, _ := m.ActiveProfile()
+	return p.AddressBook
+}
+
+func (m *SettingsModel) openAddressBook() {
+	m.bookMode = bookModeList
+	m.bookInput = ""
+	m.bookPending = ""
+	m.bookMsg = ""
+	m.bookCursor = 0
+}
+
+func (m *SettingsModel) handleAddressBook(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.bookMode == bookModeList {
+		return m.handleAddressBookList(msg)
+	}
+
+	switch msg.String() {
+	case "esc":
+		m.bookMode = bookModeList
+		m.bookInput = ""
+		m.bookPending = ""
+		return m, nil
+	case "enter":
+		input := m.bookInput
+		m.bookInput = ""
+		if m.bookMode == bookModeLabel {
+			m.bookPending = input
+			m.bookMode = bookModeAddress
+			return m, nil
+		}
+		m.bookMode = bookModeList
+		if err := m.AddContact(m.bookPending, input); err != nil {
+			m.bookMsg = err.Error()
+		} else {
+			m.bookMsg = fmt.Sprintf("Saved %s", m.bookPending)
+			m.bookCursor = len(m.contacts()) - 1
+		}
+		m.bookPending = ""
+		return m, nil
+	}
+
+	switch msg.Type {
+	case tea.KeyRunes, tea.KeySpace:
+		m.bookInput += string(msg.Runes)
+	case tea.KeyBackspace:
+		if r := []rune(m.bookInput); len(r) > 0 {
+			m.bookInput = string(r[:len(r)-1])
+		}
+	}
+	return m, nil
+}
+
+func (m *SettingsModel) handleAddressBookList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	contacts := m.contacts()
+	switch msg.String() {
+	case "esc":
+		m.bookMode = ""
+		m.bookMsg = ""
+	case "up":
+		if m.bookCursor > 0 {
+			m.bookCursor--
+		}
+	case "down":
+		if m.bookCursor < len(contacts)-1 {
+			m.bookCursor++
+		}
+	case "a":
+		m.bookMode = bookModeLabel
+		m.bookInput = ""
+		m.bookMsg = ""
+	case "d":
+		if m.bookCursor < len(contacts) {
+			c := contacts[m.bookCursor]
+			if err := m.RemoveContact(c.Address); err != nil {
+				m.bookMsg = err.Error()
+			} else {
+				m.bookMsg = fmt.Sprintf("Removed %s", c.Label)
+			}
+			if m.bookCursor > 0 && m.bookCursor >= len(m.contacts()) {
+				m.bookCursor--
+			}
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,115 @@
+package settings
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
+)
+
+// Well-known genesis identities, used to pin the predefined networks.
+const (
+	MainnetGenesisID   = "mainnet-v1.0"
+	MainnetGenesisHash = "wGHE2Pwdvd7S12BL5FaOP20EGYesN73ktiC1qzkkit8="
+	TestnetGenesisID   = "testnet-v1.0"
+	TestnetGenesisHash = "SGO1GKSzyE7IEPItTxCByw9x8FmnrCDexi9/cOUJOiI="
+)
+
+// ChainIdentity identifies the chain an algod node is serving.
+type ChainIdentity struct {
+	GenesisID       string
+	GenesisHash     string // base64, as used in transactions
+	ProtocolVersion string
+	BuildVersion    string
+}
+
+// genesisDoc holds the fields of the genesis document we care about.
+type genesisDoc struct {
+	ID      string `json:"id"`
+	Network string `json:"network"`
+	Proto   string `json:"proto"`
+}
+
+// DataDirGenesisID reads the genesis ID of the chain a node data dir
+// belongs to, from its genesis.json.
+func DataDirGenesisID(dir string) (string, error) {
+	buff, err := os.ReadFile(filepath.Join(dir, "genesis.json"))
+	if err != nil {
+		return "", err
+	}
+	var genesis genesisDoc
+	if err := json.Unmarshal(buff, &genesis); err != nil {
+		return "", fmt.Errorf("failed to parse genesis: %w", err)
+	}
+	if genesis.Network == "" || genesis.ID == "" {
+		return "", fmt.Errorf("genesis.json has no network or id")
+	}
+	return genesis.Network + "-" + genesis.ID, nil
+}
+
+// fetchChainIdentity asks the node for its versions and genesis and
+// cross-checks the two answers.
+func fetchChainIdentity(ctx context.Context, client *algod.Client) (ChainIdentity, error) {
+	versions, err := client.Versions().Do(ctx)
+	if err != nil {
+		return ChainIdentity{}, fmt.Errorf("failed to get versions: %w", err)
+	}
+
+	raw, err := client.GetGenesis().Do(ctx)
+	if err != nil {
+		return ChainIdentity{}, fmt.Errorf("failed to get genesis: %w", err)
+	}
+	var genesis genesisDoc
+	if err := json.Unmarshal([]byte(raw), &genesis); err != nil {
+		return ChainIdentity{}, fmt.Errorf("failed to parse genesis: %w", err)
+	}
+
+	genesisID := versions.GenesisID
+	if fromDoc := genesis.Network + "-" + genesis.ID; genesis.Network != "" {
+		if genesisID == "" {
+			genesisID = fromDoc
+		} else if genesisID != fromDoc {
+			return ChainIdentity{}, fmt.Errorf("node reports genesis %q but its genesis file says %q", genesisID, fromDoc)
+		}
+	}
+
+	b := versions.Build
+	identity := ChainIdentity{
+		GenesisID:   genesisID,
+		GenesisHash: base64.StdEncoding.EncodeToString(versions.GenesisHash),
+		BuildVersion: fmt.Sprintf("%d.%d.%d %s",
+			b.Major, b.Minor, b.BuildNumber, b.Channel),
+	}
+
+	if status, err := client.Status().Do(ctx); err == nil {
+		identity.ProtocolVersion = status.LastVersion
+	} else {
+		identity.ProtocolVersion = genesis.Proto
+	}
+	return identity, nil
+}
+
+// CheckPinned returns an error when info is pinned to a different chain
+// than the one described by the identity. Unpinned networks always match.
+func (ci ChainIdentity) CheckPinned(info NetworkInfo) error {
+	if info.GenesisHash != "" && info.GenesisHash != ci.GenesisHash {
+		return fmt.Errorf("%s is pinned to genesis %s (%s) but the node serves %s (%s)",
+			info.Name, info.GenesisID, info.GenesisHash, ci.GenesisID, ci.GenesisHash)
+	}
+	if info.GenesisID != "" && info.GenesisID != ci.GenesisID {
+		return fmt.Errorf("%s is pinned to genesis %s but the node serves %s",
+			info.Name, info.GenesisID, ci.GenesisID)
+	}
+	return nil
+}
+
+// Pin records the identity in info so later connections can be verified.
+func (ci ChainIdentity) Pin(info NetworkInfo) NetworkInfo {
+	info.GenesisID = ci.GenesisID
+	info.GenesisHash = ci.GenesisHash
+	return info
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 )
 
 // NetworkInfo holds RPC endpoints and tokens.
@@ -15,59 +16,120 @@ type NetworkInfo struct {
 	IndexerURL   string `json:"indexer_url"`
 	IndexerPort  string `json:"indexer_port"`
 	IndexerToken string `json:"indexer_token"`
+
+	// Extra endpoints tried when the primary one is slow or down.
+	AlgodFallbacks   []Endpoint `json:"algod_fallbacks,omitempty"`
+	IndexerFallbacks []Endpoint `json:"indexer_fallbacks,omitempty"`
+
+	// Transport options, used by every client of the network. Headers
+	// are sent on each request to the primary endpoints, fallbacks carry
+	// their own; TokenHeader replaces the default X-Algo-API-Token /
+	// X-Indexer-API-Token.
+	Headers            map[string]string `json:"headers,omitempty"`
+	TokenHeader        string            `json:"token_header,omitempty"`
+	Proxy              string            `json:"proxy,omitempty"`
+	CAFile             string            `json:"ca_file,omitempty"`
+	InsecureSkipVerify bool              `json:"insecure_skip_verify,omitempty"`
+
+	// Key management daemon and node data directory, when known. goal
+	// runs with -d DataDir, -k KmdDir and -w KmdWallet on this network.
+	KmdURL    string `json:"kmd_url,omitempty"`
+	KmdToken  string `json:"kmd_token,omitempty"`
+	DataDir   string `json:"data_dir,omitempty"`
+	KmdDir    string `json:"kmd_dir,omitempty"`
+	KmdWallet string `json:"kmd_wallet,omitempty"`
+
+	// Chain the network was configured for; empty until first pinned.
+	GenesisID   string `json:"genesis_id,omitempty"`
+	GenesisHash string `json:"genesis_hash,omitempty"`
 }
 
 // Config represents the configuration settings for the application.
 type Config struct {
+	// Schema version, see ConfigVersion and configMigrations.
+	Version int `json:"version"`
+
 	Network        string        `json:"network"`
 	WalletAddr     string        `json:"wallet_addr"`
 	CustomNetworks []NetworkInfo `json:"custom_networks"`
+
+	// Order of the network list in Settings, by name. Networks missing
+	// from it keep their default position after the listed ones.
+	NetworkOrder []string `json:"network_order,omitempty"`
+
+	// Named working contexts; Network and WalletAddr above always mirror
+	// the active one.
+	Profiles      []Profile `json:"profiles,omitempty"`
+	ActiveProfile string    `json:"active_profile,omitempty"`
+
+	// When set, tokens live in the encrypted vault and are never
+	// written to this file.
+	VaultEnabled bool `json:"vault_enabled,omitempty"`
+}
+
+// configPath replaces the default location when set, see SetConfigPath.
+var configPath string
+
+// SetConfigPath makes the config (and the vault next to it) live at path.
+// It must be called before any model is created.
+func SetConfigPath(path string) {
+	configPath = path
 }
 
 func ConfigPath() string {
+	if configPath != "" {
+		return configPath
+	}
 	home, _ := os.UserHomeDir()
 	return fmt.Sprintf("%s/.lazy-chain/config.json", home)
 }
 
-func LoadConfig() (Config, error) {
-	path := ConfigPath()
-	buff, err := os.ReadFile(path)
-	if err != nil {
-		// Default config
-		return Config{
-			Network:        "testnet",
-			WalletAddr:     "",
-			CustomNetworks: []NetworkInfo{},
-		}, nil
-	}
-
-	var cfg Config
-	if err := json.Unmarshal(buff, &cfg); err != nil {
-		return Config{}, err
-	}
+// LoadConfig returns the config, upgraded to the current schema. Problems
+// in the file are returned as issues alongside the usable part of it; the
+// error is only set when the file cannot be read at all.
+func LoadConfig() (Config, []ConfigIssue, error) {
+	cfg, issues, err := loadConfigFile()
 
 	// Ensure slice is non-nil
 	if cfg.CustomNetworks == nil {
 		cfg.CustomNetworks = []NetworkInfo{}
 	}
-	return cfg, nil
+	return cfg, issues, err
 }
 
 func SaveConfig(cfg Config) error {
 	// Ensure directory exists
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return fmt.Errorf("failed to get user home directory: %w", err)
+	configDir := filepath.Dir(ConfigPath())
+	if configPath == "" {
+		if _, err := os.UserHomeDir(); err != nil {
+			return fmt.Errorf("failed to get user home directory: %w", err)
+		}
 	}
-	configDir := fmt.Sprintf("%s/.lazy-chain", home)
-	if err := os.MkdirAll(configDir, 0755); err != nil {
+	if err := os.MkdirAll(configDir, 0700); err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
+	// Tighten directories created by older versions, but leave a
+	// directory chosen by the user alone
+	if configPath == "" {
+		if err := os.Chmod(configDir, 0700); err != nil {
+			return fmt.Errorf("failed to secure config directory: %w", err)
+		}
+	}
 
 	// Ensure non-nil
 	if cfg.CustomNetworks == nil {
 		cfg.CustomNetworks = []NetworkInfo{}
 	}
+	cfg.Version = ConfigVersion
+
+	// Tokens belong to the vault once it is enabled
+	if cfg.VaultEnabled {
+		redacted := make([]NetworkInfo, len(cfg.CustomNetworks))
+		for i, n := range cfg.CustomNetworks {
+			redacted[i] = n.WithSecrets(NetworkSecrets{})
+		}
+		cfg.CustomNetworks = redacted
+	}
 
 	// Pretty JSON
 	buff, err := json.MarshalIndent(cfg, "", "  ")
@@ -75,8 +137,40 @@ func SaveConfig(cfg Config) error {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
-	if err := os.WriteFile(ConfigPath(), buff, 0644); err != nil {
+	if err := writePrivateFile(ConfigPath(), buff); err != nil {
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
+	// Backups made before the vault still hold the tokens in clear
+	if cfg.VaultEnabled {
+		if err := scrubConfigBackups(); err != nil {
+			return fmt.Errorf("config saved, but failed to remove tokens from its backups: %w", err)
+		}
+	}
 	return nil
 }
+
+// writePrivateFile atomically replaces path with data readable only by
+// the owner, so secrets never sit in a world-readable file.
+func writePrivateFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
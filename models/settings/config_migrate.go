@@ -0,0 +1,344 @@
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// ConfigVersion is the schema version written by this build. Bump it and
+// append to configMigrations whenever the shape of Config changes.
+const ConfigVersion = 1
+
+// configMigration upgrades a raw config from version Version-1 to Version.
+type configMigration struct {
+	Version     int
+	Description string
+	Apply       func(raw map[string]any) error
+}
+
+// configMigrations run in order on files older than ConfigVersion.
+var configMigrations = []configMigration{
+	{
+		Version:     1,
+		Description: "add schema version, normalise hand-edited ports and lists",
+		Apply:       migrateV1,
+	},
+}
+
+// ConfigIssue is a problem found while loading the config. The config is
+// still loaded; the offending value is skipped or defaulted.
+type ConfigIssue struct {
+	Field   string
+	Message string
+}
+
+func (i ConfigIssue) String() string {
+	if i.Field == "" {
+		return i.Message
+	}
+	return i.Field + ": " + i.Message
+}
+
+// defaultConfig is used when there is no file, or nothing in it is usable.
+func defaultConfig() Config {
+	return Config{
+		Version:        ConfigVersion,
+		Network:        "testnet",
+		WalletAddr:     "",
+		CustomNetworks: []NetworkInfo{},
+	}
+}
+
+// backupConfig keeps a copy of the file as it was before we touched it.
+func backupConfig(buff []byte, suffix string) (string, error) {
+	path := ConfigPath() + "." + suffix + ".bak"
+	return path, writePrivateFile(path, buff)
+}
+
+// backupSecrets are the keys of a network holding tokens, as written in
+// config.json; fallbacks keep theirs in "token".
+var backupSecrets = []string{"algod_token", "indexer_token", "kmd_token", "headers"}
+
+// scrubConfigBackups removes the tokens from the backups of config.json
+// once they belong to the vault. A backup that is not valid JSON cannot
+// be scrubbed and is deleted.
+func scrubConfigBackups() error {
+	paths, err := filepath.Glob(ConfigPath() + ".*.bak")
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		buff, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var raw map[string]any
+		if json.Unmarshal(buff, &raw) != nil {
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+			continue
+		}
+		nets, _ := raw["custom_networks"].([]any)
+		for _, n := range nets {
+			net, ok := n.(map[string]any)
+			if !ok {
+				continue
+			}
+			for _, key := range backupSecrets {
+				delete(net, key)
+			}
+			for _, key := range []string{"algod_fallbacks", "indexer_fallbacks"} {
+				eps, _ := net[key].([]any)
+				for _, e := range eps {
+					if ep, ok := e.(map[string]any); ok {
+						delete(ep, "token")
+					}
+				}
+			}
+		}
+		scrubbed, err := json.MarshalIndent(raw, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := writePrivateFile(path, scrubbed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateConfig upgrades raw in place and reports the versions crossed.
+func migrateConfig(raw map[string]any) (from int, err error) {
+	from = 0
+	if v, ok := raw["version"].(float64); ok {
+		from = int(v)
+	}
+	for _, mig := range configMigrations {
+		if mig.Version <= from {
+			continue
+		}
+		if err := mig.Apply(raw); err != nil {
+			return from, fmt.Errorf("migration to v%d (%s): %w", mig.Version, mig.Description, err)
+		}
+		raw["version"] = mig.Version
+	}
+	return from, nil
+}
+
+// migrateV1 handles files written before versioning. Those were often
+// edited by hand: ports as numbers, null lists.
+func migrateV1(raw map[string]any) error {
+	if raw["custom_networks"] == nil {
+		raw["custom_networks"] = []any{}
+	}
+	nets, ok := raw["custom_networks"].([]any)
+	if !ok {
+		return nil // reported by decodeConfig
+	}
+	for _, n := range nets {
+		net, ok := n.(map[string]any)
+		if !ok {
+			continue
+		}
+		for _, key := range []string{"algod_port", "indexer_port"} {
+			if f, ok := net[key].(float64); ok {
+				net[key] = strconv.Itoa(int(f))
+			}
+		}
+	}
+	return nil
+}
+
+// decodeConfig fills a Config key by key, so one bad value costs only
+// that value and not the whole file.
+func decodeConfig(raw map[string]any) (Config, []ConfigIssue) {
+	cfg := defaultConfig()
+	var issues []ConfigIssue
+
+	decode := func(key string, dst any) {
+		v, ok := raw[key]
+		if !ok || v == nil {
+			return
+		}
+		b, _ := json.Marshal(v)
+		if err := json.Unmarshal(b, dst); err != nil {
+			issues = append(issues, ConfigIssue{Field: key, Message: "ignored, " + describeJSONError(err)})
+		}
+	}
+
+	// Every setting of Config is known, lists are decoded element by
+	// element below
+	known := map[string]bool{}
+	rv := reflect.ValueOf(&cfg).Elem()
+	for i := 0; i < rv.NumField(); i++ {
+		key := strings.Split(rv.Type().Field(i).Tag.Get("json"), ",")[0]
+		known[key] = true
+		if key == "custom_networks" || key == "profiles" {
+			continue
+		}
+		decode(key, rv.Field(i).Addr().Interface())
+	}
+
+	if list, ok := raw["custom_networks"].([]any); ok {
+		for i, item := range list {
+			var n NetworkInfo
+			b, _ := json.Marshal(item)
+			if err := json.Unmarshal(b, &n); err != nil {
+				issues = append(issues, ConfigIssue{
+					Field:   fmt.Sprintf("custom_networks[%d]", i),
+					Message: "skipped, " + describeJSONError(err),
+				})
+				continue
+			}
+			cfg.CustomNetworks = append(cfg.CustomNetworks, n)
+		}
+	} else if v, present := raw["custom_networks"]; present && v != nil {
+		issues = append(issues, ConfigIssue{Field: "custom_networks", Message: "ignored, expected a list"})
+	}
+	if list, ok := raw["profiles"].([]any); ok {
+		for i, item := range list {
+			var p Profile
+			b, _ := json.Marshal(item)
+			if err := json.Unmarshal(b, &p); err != nil {
+				issues = append(issues, ConfigIssue{
+					Field:   fmt.Sprintf("profiles[%d]", i),
+					Message: "skipped, " + describeJSONError(err),
+				})
+				continue
+			}
+			cfg.Profiles = append(cfg.Profiles, p)
+		}
+	}
+
+	var unknown []string
+	for k := range raw {
+		if !known[k] {
+			unknown = append(unknown, k)
+		}
+	}
+	sort.Strings(unknown)
+	for _, k := range unknown {
+		issues = append(issues, ConfigIssue{Field: k, Message: "unknown setting, ignored and dropped on next save"})
+	}
+
+	return cfg, issues
+}
+
+func describeJSONError(err error) string {
+	if te, ok := err.(*json.UnmarshalTypeError); ok {
+		if te.Field != "" {
+			return fmt.Sprintf("%s should be %s, got %s", te.Field, te.Type, te.Value)
+		}
+		return fmt.Sprintf("should be %s, got %s", te.Type, te.Value)
+	}
+	return err.Error()
+}
+
+// validateConfig reports values that decode fine but make no sense.
+// Problems that can be fixed without guessing are fixed.
+func validateConfig(cfg *Config) []ConfigIssue {
+	var issues []ConfigIssue
+
+	seen := map[string]bool{}
+	nets := cfg.CustomNetworks[:0]
+	for _, n := range cfg.CustomNetworks {
+		// Indexes no longer match the file once entries were skipped
+		field := "custom_networks"
+		switch {
+		case strings.TrimSpace(n.Name) == "":
+			issues = append(issues, ConfigIssue{Field: field, Message: "skipped, network has no name"})
+			continue
+		case seen[n.Name]:
+			issues = append(issues, ConfigIssue{Field: field, Message: fmt.Sprintf("skipped, duplicate network %q", n.Name)})
+			continue
+		case strings.TrimSpace(n.AlgodURL) == "":
+			issues = append(issues, ConfigIssue{Field: field, Message: fmt.Sprintf("network %q has no algod URL", n.Name)})
+		}
+		seen[n.Name] = true
+		nets = append(nets, n)
+	}
+	cfg.CustomNetworks = nets
+
+	if cfg.WalletAddr != "" {
+		if _, err := types.DecodeAddress(cfg.WalletAddr); err != nil {
+			issues = append(issues, ConfigIssue{Field: "wallet_addr", Message: "not a valid Algorand address"})
+		}
+	}
+
+	profiles := map[string]bool{}
+	for _, p := range cfg.Profiles {
+		if strings.TrimSpace(p.Name) == "" || profiles[p.Name] {
+			issues = append(issues, ConfigIssue{Field: "profiles", Message: fmt.Sprintf("missing or duplicate profile name %q", p.Name)})
+		}
+		profiles[p.Name] = true
+	}
+	if cfg.ActiveProfile != "" && !profiles[cfg.ActiveProfile] {
+		issues = append(issues, ConfigIssue{Field: "active_profile", Message: fmt.Sprintf("profile %q does not exist, none active", cfg.ActiveProfile)})
+		cfg.ActiveProfile = ""
+	}
+	return issues
+}
+
+// loadConfigFile reads, migrates and validates the config. It only fails
+// when the file exists but cannot be read; every other problem becomes
+// an issue and the best usable config is returned.
+func loadConfigFile() (Config, []ConfigIssue, error) {
+	path := ConfigPath()
+	buff, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return defaultConfig(), nil, nil
+	}
+	if err != nil {
+		return defaultConfig(), nil, err
+	}
+
+	var raw map[string]any
+	if err := json.Unmarshal(buff, &raw); err != nil {
+		issue := ConfigIssue{Message: fmt.Sprintf("config.json is not valid JSON (%v), using defaults", err)}
+		if bak, berr := backupConfig(buff, "invalid"); berr == nil {
+			issue.Message += ", original kept in " + bak
+		}
+		return defaultConfig(), []ConfigIssue{issue}, nil
+	}
+
+	var issues []ConfigIssue
+	from, err := migrateConfig(raw)
+	if err != nil {
+		issues = append(issues, ConfigIssue{Message: err.Error()})
+	}
+	if from > ConfigVersion {
+		issues = append(issues, ConfigIssue{
+			Field:   "version",
+			Message: fmt.Sprintf("written by a newer lazychain (v%d, this one knows v%d), saving may drop settings", from, ConfigVersion),
+		})
+	}
+
+	cfg, decodeIssues := decodeConfig(raw)
+	issues = append(issues, decodeIssues...)
+	issues = append(issues, validateConfig(&cfg)...)
+
+	// Persist the upgrade once, keeping the old file around
+	if from < ConfigVersion && err == nil {
+		bak, berr := backupConfig(buff, fmt.Sprintf("v%d", from))
+		if berr != nil {
+			issues = append(issues, ConfigIssue{Message: fmt.Sprintf("config upgraded in memory only, backup failed: %v", berr)})
+		} else if upgraded, merr := json.MarshalIndent(raw, "", "  "); merr == nil {
+			if werr := writePrivateFile(path, upgraded); werr != nil {
+				issues = append(issues, ConfigIssue{Message: fmt.Sprintf("config upgraded in memory only: %v", werr)})
+			} else {
+				issues = append(issues, ConfigIssue{Message: fmt.Sprintf("config upgraded from v%d to v%d, previous file kept in %s", from, ConfigVersion, bak)})
+			}
+		}
+	}
+	cfg.Version = ConfigVersion
+	return cfg, issues, nil
+}
@@ -0,0 +1,122 @@
+package settings
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestMigrateV1(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			"null list",
+			`{"network":"testnet","custom_networks":null}`,
+			`{"network":"testnet","custom_networks":[],"version":1}`,
+		},
+		{
+			"missing list",
+			`{"network":"testnet"}`,
+			`{"network":"testnet","custom_networks":[],"version":1}`,
+		},
+		{
+			"numeric ports",
+			`{"custom_networks":[{"name":"local","algod_port":4001,"indexer_port":8980}]}`,
+			`{"custom_networks":[{"name":"local","algod_port":"4001","indexer_port":"8980"}],"version":1}`,
+		},
+		{
+			"string ports kept",
+			`{"custom_networks":[{"name":"local","algod_port":"443"}]}`,
+			`{"custom_networks":[{"name":"local","algod_port":"443"}],"version":1}`,
+		},
+		{
+			// left for decodeConfig to report
+			"not a list",
+			`{"custom_networks":"local"}`,
+			`{"custom_networks":"local","version":1}`,
+		},
+		{
+			"not an object",
+			`{"custom_networks":[7]}`,
+			`{"custom_networks":[7],"version":1}`,
+		},
+	}
+	for _, tt := range tests {
+		raw := decodeRaw(t, tt.in)
+		from, err := migrateConfig(raw)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if from != 0 {
+			t.Errorf("%s: from = %d, want 0", tt.name, from)
+		}
+		// compare through JSON, as the migrated map is written back
+		got := decodeRaw(t, encodeRaw(t, raw))
+		if want := decodeRaw(t, tt.want); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %s, want %s", tt.name, encodeRaw(t, raw), tt.want)
+		}
+	}
+}
+
+func TestMigrateConfigCurrent(t *testing.T) {
+	raw := decodeRaw(t, `{"version":1,"custom_networks":[{"algod_port":4001}]}`)
+	from, err := migrateConfig(raw)
+	if err != nil || from != ConfigVersion {
+		t.Fatalf("migrateConfig = %d, %v, want %d", from, err, ConfigVersion)
+	}
+	// a current file is not touched
+	if got := encodeRaw(t, raw); got != `{"custom_networks":[{"algod_port":4001}],"version":1}` {
+		t.Errorf("current file changed: %s", got)
+	}
+}
+
+func TestDecodeConfigIssues(t *testing.T) {
+	raw := decodeRaw(t, `{"network":7,"wallet_addr":"ADDR","custom_networks":[{"name":"ok","algod_url":"http://localhost","algod_port":"4001"},{"name":3}]}`)
+	if _, err := migrateConfig(raw); err != nil {
+		t.Fatal(err)
+	}
+	cfg, issues := decodeConfig(raw)
+	if cfg.WalletAddr != "ADDR" {
+		t.Errorf("wallet = %q, want ADDR", cfg.WalletAddr)
+	}
+	fields := map[string]bool{}
+	for _, i := range issues {
+		fields[i.Field] = true
+	}
+	for _, f := range []string{"network", "custom_networks[1]"} {
+		if !fields[f] {
+			t.Errorf("no issue reported for %s, got %+v", f, issues)
+		}
+	}
+	found := false
+	for _, n := range cfg.CustomNetworks {
+		if n.Name == "ok" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("valid network dropped: %+v", cfg.CustomNetworks)
+	}
+}
+
+func decodeRaw(t *testing.T, s string) map[string]any {
+	t.Helper()
+	var raw map[string]any
+	if err := json.Unmarshal([]byte(s), &raw); err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+func encodeRaw(t *testing.T, raw map[string]any) string {
+	t.Helper()
+	b, err := json.Marshal(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
@@ -0,0 +1,359 @@
+package settings
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Endpoint is a single algod or indexer server. Headers are sent to it
+// alone, like its token: they often carry the provider's API key.
+type Endpoint struct {
+	URL     string            `json:"url"`
+	Port    string            `json:"port,omitempty"`
+	Token   string            `json:"token,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// BaseURL joins URL and port the same way the primary endpoint always has:
+// explicit non-default ports are appended, bare hosts get the port unless
+// they already carry one.
+func (e Endpoint) BaseURL() string {
+	base := strings.TrimSpace(e.URL)
+	if hasScheme(base) {
+		base = trimSlash(base)
+		if e.Port != "" && !hasExplicitPort(base) && e.Port != "443" && e.Port != "80" {
+			base = fmt.Sprintf("%s:%s", base, e.Port)
+		}
+		return base
+	}
+	base = trimSlash(base)
+	if e.Port == "" || hasExplicitPort(base) {
+		return base
+	}
+	return fmt.Sprintf("%s:%s", base, e.Port)
+}
+
+// AlgodEndpoints returns the primary algod endpoint followed by fallbacks.
+// The network's headers belong to the primary endpoints.
+func (n NetworkInfo) AlgodEndpoints() []Endpoint {
+	eps := []Endpoint{{URL: n.AlgodURL, Port: n.AlgodPort, Token: n.AlgodToken, Headers: n.Headers}}
+	return append(eps, n.AlgodFallbacks...)
+}
+
+// IndexerEndpoints returns the primary indexer endpoint followed by
+// fallbacks, or nothing when no indexer is configured.
+func (n NetworkInfo) IndexerEndpoints() []Endpoint {
+	var eps []Endpoint
+	if n.IndexerURL != "" {
+		eps = append(eps, Endpoint{URL: n.IndexerURL, Port: n.IndexerPort, Token: n.IndexerToken, Headers: n.Headers})
+	}
+	return append(eps, n.IndexerFallbacks...)
+}
+
+// FormatEndpoints renders fallbacks as the comma separated "url[:port]"
+// list used by the network editor. Tokens and headers are not shown, see
+// ParseEndpoints for how they are entered.
+func FormatEndpoints(eps []Endpoint) string {
+	parts := make([]string, 0, len(eps))
+	for _, e := range eps {
+		parts = append(parts, e.BaseURL())
+	}
+	return strings.Join(parts, ",")
+}
+
+// ParseEndpoints is the inverse of FormatEndpoints. Each entry is
+// "scheme://host[:port]", optionally followed by "|token" to set its
+// token ("|" alone clears it) and by "|Name: value; ..." to set its
+// headers; endpoints kept without them (same base URL) keep their token
+// and headers from prev.
+func ParseEndpoints(s string, prev []Endpoint) ([]Endpoint, error) {
+	known := map[string]Endpoint{}
+	for _, e := range prev {
+		known[e.BaseURL()] = e
+	}
+	var eps []Endpoint
+	for _, part := range strings.Split(s, ",") {
+		fields := strings.SplitN(part, "|", 3)
+		raw := trimSlash(fields[0])
+		if raw == "" {
+			if len(fields) > 1 {
+				return nil, fmt.Errorf("token without an endpoint in %q", strings.TrimSpace(part))
+			}
+			continue
+		}
+		e := Endpoint{URL: raw, Token: known[raw].Token, Headers: known[raw].Headers}
+		if len(fields) > 1 {
+			e.Token = strings.TrimSpace(fields[1])
+		}
+		if len(fields) > 2 {
+			headers, err := ParseHeaders(fields[2])
+			if err != nil {
+				return nil, fmt.Errorf("endpoint %q: %w", raw, err)
+			}
+			e.Headers = headers
+		}
+		if err := validateEndpoint(e); err != nil {
+			return nil, err
+		}
+		eps = append(eps, e)
+	}
+	return eps, nil
+}
+
+// validateEndpoint checks a fallback endpoint: fallbacks have no port
+// field of their own, so the scheme and host must be in the URL.
+func validateEndpoint(e Endpoint) error {
+	raw := e.BaseURL()
+	if !hasScheme(raw) {
+		return fmt.Errorf("endpoint %q needs a scheme, e.g. http://%s", raw, raw)
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid endpoint %q: %w", raw, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("endpoint %q must be http or https", raw)
+	}
+	if u.Hostname() == "" {
+		return fmt.Errorf("endpoint %q has no host", raw)
+	}
+	return nil
+}
+
+// How many consecutive failures mark an endpoint down, and for how long
+// it is skipped before being tried again.
+const (
+	endpointDownAfter = 3
+	endpointCooldown  = 30 * time.Second
+)
+
+// EndpointHealth is the running score of one endpoint.
+type EndpointHealth struct {
+	Endpoint  Endpoint
+	Latency   time.Duration // exponentially weighted average
+	Requests  uint64
+	Errors    uint64
+	Failures  int // consecutive
+	LastError string
+	LastSeen  time.Time
+	LastFail  time.Time
+}
+
+// Down reports whether the endpoint is currently being skipped.
+func (h EndpointHealth) Down() bool {
+	return h.Failures >= endpointDownAfter && time.Since(h.LastFail) < endpointCooldown
+}
+
+// Score ranks endpoints, lower is better. Latency counts in milliseconds,
+// each recent consecutive failure weighs like a second of latency.
+func (h EndpointHealth) Score() float64 {
+	score := float64(h.Latency.Milliseconds()) + 1000*float64(h.Failures)
+	if h.Requests > 0 {
+		score += 500 * float64(h.Errors) / float64(h.Requests)
+	}
+	if h.Down() {
+		score += 1e9
+	}
+	return score
+}
+
+func (h *EndpointHealth) recordSuccess(latency time.Duration, measure bool) {
+	h.Requests++
+	h.Failures = 0
+	h.LastError = ""
+	h.LastSeen = time.Now()
+	if !measure {
+		return
+	}
+	if h.Latency == 0 {
+		h.Latency = latency
+	} else {
+		h.Latency = (h.Latency*7 + latency) / 8
+	}
+}
+
+func (h *EndpointHealth) recordFailure(err error) {
+	h.Requests++
+	h.Errors++
+	h.Failures++
+	h.LastError = err.Error()
+	h.LastFail = time.Now()
+}
+
+// endpointPool is an http.RoundTripper that sends every request to the
+// healthiest endpoint and transparently retries the next one on network
+// errors and 5xx responses. SDK clients are built against a placeholder
+// base URL and the pool rewrites each request to the chosen endpoint.
+type endpointPool struct {
+	mu          sync.Mutex
+	kind        string // "algod" or "indexer"
+	tokenHeader string
+	entries     []*EndpointHealth
+	base        http.RoundTripper
+}
+
+// poolBaseURL is the placeholder address handed to the SDK clients.
+const poolBaseURL = "http://endpoint-pool"
+
+func newEndpointPool(kind, tokenHeader string, eps []Endpoint, base http.RoundTripper) *endpointPool {
+	p := &endpointPool{kind: kind, tokenHeader: tokenHeader, base: base}
+	for _, e := range eps {
+		p.entries = append(p.entries, &EndpointHealth{Endpoint: e})
+	}
+	return p
+}
+
+// ranked returns the entries ordered by score, keeping configuration order
+// between equal scores so the primary wins until measured otherwise.
+func (p *endpointPool) ranked() []*EndpointHealth {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	order := make([]*EndpointHealth, len(p.entries))
+	copy(order, p.entries)
+	sort.SliceStable(order, func(i, j int) bool { return order[i].Score() < order[j].Score() })
+	return order
+}
+
+// Snapshot returns a copy of the health table in configuration order.
+func (p *endpointPool) Snapshot() []EndpointHealth {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	out := make([]EndpointHealth, 0, len(p.entries))
+	for _, e := range p.entries {
+		out = append(out, *e)
+	}
+	return out
+}
+
+func (p *endpointPool) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = b
+	}
+
+	// Long-polling calls say nothing about latency
+	measure := !strings.Contains(req.URL.Path, "/wait-for-block-after/")
+
+	var lastErr error
+	for _, entry := range p.ranked() {
+		target, err := url.Parse(entry.Endpoint.BaseURL())
+		if err != nil {
+			p.fail(entry, err)
+			lastErr = err
+			continue
+		}
+
+		out := req.Clone(req.Context())
+		out.URL.Scheme = target.Scheme
+		out.URL.Host = target.Host
+		out.URL.Path = strings.TrimRight(target.Path, "/") + req.URL.Path
+		out.Host = target.Host
+		p.authorize(out, entry.Endpoint)
+		if body != nil {
+			out.Body = io.NopCloser(bytes.NewReader(body))
+			out.ContentLength = int64(len(body))
+		}
+
+		start := time.Now()
+		resp, err := p.base.RoundTrip(out)
+		if err == nil && resp.StatusCode >= 500 {
+			resp.Body.Close()
+			err = fmt.Errorf("%s: HTTP %d", target.Host, resp.StatusCode)
+		}
+		if err != nil {
+			if req.Context().Err() != nil {
+				// caller gave up, not the endpoint's fault
+				return nil, req.Context().Err()
+			}
+			p.fail(entry, err)
+			lastErr = err
+			continue
+		}
+
+		p.mu.Lock()
+		entry.recordSuccess(time.Since(start), measure)
+		p.mu.Unlock()
+		return resp, nil
+	}
+
+	if lastErr == nil {
+		lastErr = errors.New("no endpoints configured")
+	}
+	return nil, fmt.Errorf("all %s endpoints failed, last error: %w", p.kind, lastErr)
+}
+
+// authorize sets the headers and the token of the endpoint called, and
+// nothing configured for another one. The token is set last so a custom
+// header cannot silently replace it.
+func (p *endpointPool) authorize(req *http.Request, ep Endpoint) {
+	for name, value := range ep.Headers {
+		req.Header.Set(name, value)
+	}
+	if ep.Token != "" {
+		req.Header.Set(p.tokenHeader, ep.Token)
+	}
+}
+
+func (p *endpointPool) fail(entry *EndpointHealth, err error) {
+	p.mu.Lock()
+	entry.recordFailure(err)
+	p.mu.Unlock()
+}
+
+// Probe pings the health route of every endpoint to refresh the scores.
+func (p *endpointPool) Probe(ctx context.Context) {
+	p.mu.Lock()
+	entries := append([]*EndpointHealth(nil), p.entries...)
+	p.mu.Unlock()
+	var wg sync.WaitGroup
+	for _, entry := range entries {
+		wg.Add(1)
+		go func(entry *EndpointHealth) {
+			defer wg.Done()
+			p.probeOne(ctx, entry)
+		}(entry)
+	}
+	wg.Wait()
+}
+
+// probeOne pings entry; its Endpoint is set once by newEndpointPool and
+// read without the lock.
+func (p *endpointPool) probeOne(ctx context.Context, entry *EndpointHealth) {
+	ep := entry.Endpoint
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ep.BaseURL()+"/health", nil)
+	if err != nil {
+		p.fail(entry, err)
+		return
+	}
+	p.authorize(req, ep)
+
+	start := time.Now()
+	resp, err := p.base.RoundTrip(req)
+	if err == nil {
+		resp.Body.Close()
+		if resp.StatusCode >= 400 {
+			err = fmt.Errorf("health: HTTP %d", resp.StatusCode)
+		}
+	}
+	if err != nil {
+		p.fail(entry, err)
+		return
+	}
+	p.mu.Lock()
+	entry.recordSuccess(time.Since(start), true)
+	p.mu.Unlock()
+}
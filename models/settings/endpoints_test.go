@@ -0,0 +1,101 @@
+package settings
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// TestEndpointPoolFailover checks a failing primary is skipped for the
+// fallback, and that the fallback only gets its own token and headers.
+func TestEndpointPoolFailover(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer primary.Close()
+	var got http.Header
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer fallback.Close()
+
+	pool := newEndpointPool("algod", algodTokenHeader, []Endpoint{
+		{URL: primary.URL, Token: "primary-token", Headers: map[string]string{"X-Api-Key": "primary-key"}},
+		{URL: fallback.URL, Token: "fallback-token", Headers: map[string]string{"X-Other": "fallback-key"}},
+	}, http.DefaultTransport)
+
+	req, _ := http.NewRequest(http.MethodGet, poolBaseURL+"/v2/status", nil)
+	resp, err := pool.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip: %v", err)
+	}
+	resp.Body.Close()
+
+	if got == nil {
+		t.Fatal("the fallback was not called")
+	}
+	if v := got.Get("X-Api-Key"); v != "" {
+		t.Errorf("the primary's header leaked to the fallback: %q", v)
+	}
+	if v := got.Get(algodTokenHeader); v != "fallback-token" {
+		t.Errorf("token = %q, want fallback-token", v)
+	}
+	if v := got.Get("X-Other"); v != "fallback-key" {
+		t.Errorf("X-Other = %q, want fallback-key", v)
+	}
+	health := pool.Snapshot()
+	if health[0].Failures != 1 || health[1].Failures != 0 || health[1].Requests != 1 {
+		t.Errorf("health = %+v", health)
+	}
+	// the failed primary now ranks after the fallback
+	if ranked := pool.ranked(); ranked[0].Endpoint.URL != fallback.URL {
+		t.Errorf("first ranked = %s, want the fallback", ranked[0].Endpoint.URL)
+	}
+}
+
+func TestEndpointPoolAllDown(t *testing.T) {
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer down.Close()
+	pool := newEndpointPool("indexer", indexerTokenHeader, []Endpoint{{URL: down.URL}, {URL: down.URL + "/v1"}}, http.DefaultTransport)
+	req, _ := http.NewRequest(http.MethodGet, poolBaseURL+"/health", nil)
+	if _, err := pool.RoundTrip(req); err == nil {
+		t.Error("RoundTrip with every endpoint down: want an error")
+	}
+}
+
+func TestParseEndpoints(t *testing.T) {
+	prev := []Endpoint{{URL: "https://a.example", Token: "old", Headers: map[string]string{"X-Api-Key": "k"}}}
+	tests := []struct {
+		in      string
+		want    []Endpoint
+		wantErr bool
+	}{
+		{"", nil, false},
+		{"https://a.example/", prev, false},
+		{"https://a.example|new", []Endpoint{{URL: "https://a.example", Token: "new", Headers: map[string]string{"X-Api-Key": "k"}}}, false},
+		{"https://a.example|", []Endpoint{{URL: "https://a.example", Headers: map[string]string{"X-Api-Key": "k"}}}, false},
+		{"https://a.example|t|x-key: v; X-Two: w", []Endpoint{{URL: "https://a.example", Token: "t",
+			Headers: map[string]string{"X-Key": "v", "X-Two": "w"}}}, false},
+		{"https://a.example|t|", []Endpoint{{URL: "https://a.example", Token: "t"}}, false},
+		{"http://b.example:8080, https://c.example", []Endpoint{{URL: "http://b.example:8080"}, {URL: "https://c.example"}}, false},
+
+		{"|token", nil, true},
+		{"b.example", nil, true},
+		{"ftp://b.example", nil, true},
+		{"https://a.example|t|no colon", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseEndpoints(tt.in, prev)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseEndpoints(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseEndpoints(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
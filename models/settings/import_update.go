@@ -0,0 +1,224 @@
+package settings
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Import panel modes.
+const (
+	importModeMenu   = "menu"
+	importModePath   = "path"
+	importModeReview = "review"
+	importModeRename = "rename"
+)
+
+// Import sources, in menu order.
+var importSources = []string{
+	"AlgoKit LocalNet",
+	"Node data directory",
+	"JSON/TOML file",
+}
+
+func (m *SettingsModel) openImportPanel() {
+	m.importMode = importModeMenu
+	m.importCursor = 0
+	m.importInput = ""
+	m.importFound = nil
+	m.importMsg = ""
+	m.importReplace = nil
+}
+
+func (m *SettingsModel) handleImport(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.importMode {
+	case importModeMenu:
+		switch msg.String() {
+		case "esc":
+			m.importMode = ""
+		case "up":
+			if m.importCursor > 0 {
+				m.importCursor--
+			}
+		case "down":
+			if m.importCursor < len(importSources)-1 {
+				m.importCursor++
+			}
+		case "1", "2", "3":
+			m.importCursor = int(msg.Runes[0] - '1')
+			m.chooseImportSource()
+		case "enter":
+			m.chooseImportSource()
+		}
+
+	case importModePath, importModeRename:
+		switch msg.Type {
+		case tea.KeyEsc:
+			if m.importMode == importModeRename {
+				m.importMode = importModeReview
+			} else {
+				m.importMode = importModeMenu
+			}
+			m.importInput = ""
+		case tea.KeyEnter:
+			if m.importMode == importModeRename {
+				if name := strings.TrimSpace(m.importInput); name != "" {
+					m.importFound[m.importCursor].Name = name
+				}
+				m.importMode = importModeReview
+				m.importInput = ""
+				return m, nil
+			}
+			m.readImportPath(m.importInput)
+		case tea.KeyRunes, tea.KeySpace:
+			m.importInput += string(msg.Runes)
+		case tea.KeyBackspace:
+			if r := []rune(m.importInput); len(r) > 0 {
+				m.importInput = string(r[:len(r)-1])
+			}
+		}
+
+	case importModeReview:
+		if len(m.importReplace) > 0 {
+			switch msg.String() {
+			case "y", "Y", "enter":
+				m.importReplace = nil
+				m.saveImported()
+			case "r":
+				m.importReplace = nil
+				m.importMsg = ""
+				m.importMode = importModeRename
+				m.importInput = m.importFound[m.importCursor].Name
+			case "n", "N", "esc":
+				m.importReplace = nil
+				m.importMsg = ""
+			}
+			return m, nil
+		}
+		switch msg.String() {
+		case "esc":
+			m.importMode = importModeMenu
+			m.importFound = nil
+			m.importCursor = 0
+		case "up":
+			if m.importCursor > 0 {
+				m.importCursor--
+			}
+		case "down":
+			if m.importCursor < len(m.importFound)-1 {
+				m.importCursor++
+			}
+		case "r":
+			m.importMode = importModeRename
+			m.importInput = m.importFound[m.importCursor].Name
+		case "enter":
+			m.confirmImport()
+		}
+	}
+	return m, nil
+}
+
+// confirmImport saves the reviewed networks, refusing predefined names and
+// asking before replacing networks that already exist.
+func (m *SettingsModel) confirmImport() {
+	var replace []string
+	for _, info := range m.importFound {
+		if isPredefined(info.Name) {
+			m.importMsg = fmt.Sprintf("%s is predefined and cannot be imported over, rename it (r)", info.Name)
+			return
+		}
+		if m.hasNetwork(info.Name) {
+			replace = append(replace, info.Name)
+		}
+	}
+	if len(replace) > 0 {
+		m.importReplace = replace
+		m.importMsg = fmt.Sprintf("Replace %s with the imported settings? (y/n, r renames the selected one)", strings.Join(replace, ", "))
+		return
+	}
+	m.saveImported()
+}
+
+// chooseImportSource runs the menu entry under the cursor.
+func (m *SettingsModel) chooseImportSource() {
+	m.importMsg = ""
+	m.importSource = m.importCursor
+	switch m.importCursor {
+	case 0:
+		found, err := ImportAlgoKitLocalNet()
+		m.reviewImport(found, err)
+	case 1:
+		m.importMode = importModePath
+		m.importInput = os.Getenv("ALGORAND_DATA")
+	case 2:
+		m.importMode = importModePath
+		m.importInput = ""
+	}
+}
+
+func (m *SettingsModel) readImportPath(path string) {
+	if m.importSource == 1 {
+		info, err := ImportDataDir(path)
+		m.reviewImport([]NetworkInfo{info}, err)
+		return
+	}
+	found, err := ImportFile(path)
+	m.reviewImport(found, err)
+}
+
+func (m *SettingsModel) reviewImport(found []NetworkInfo, err error) {
+	if err != nil {
+		m.importMsg = err.Error()
+		return
+	}
+	m.importFound = found
+	m.importCursor = 0
+	m.importInput = ""
+	m.importMode = importModeReview
+}
+
+// saveImported stores the reviewed networks in CustomNetworks, replacing
+// custom networks with the same name once confirmed.
+func (m *SettingsModel) saveImported() {
+	for _, info := range m.importFound {
+		replaced := false
+		for i, n := range m.config.CustomNetworks {
+			if n.Name == info.Name {
+				m.config.CustomNetworks[i] = info
+				replaced = true
+			}
+		}
+		if !replaced {
+			m.config.CustomNetworks = append(m.config.CustomNetworks, info)
+		}
+		m.networkInfos[info.Name] = info
+		if !m.hasNetwork(info.Name) {
+			m.networks = append(m.networks, info.Name)
+		}
+	}
+	if err := m.persistConfig(); err != nil {
+		m.importMsg = fmt.Sprintf("Failed to save config: %v", err)
+		return
+	}
+	m.applyOverrides()
+
+	names := make([]string, 0, len(m.importFound))
+	for _, n := range m.importFound {
+		names = append(names, n.Name)
+	}
+	m.importMode = ""
+	m.importFound = nil
+	m.connectionStatus = fmt.Sprintf("Imported %s, press t to test", strings.Join(names, ", "))
+	m.showStatus = true
+}
+
+func (m *SettingsModel) hasNetwork(name string) bool {
+	for _, n := range m.networks {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
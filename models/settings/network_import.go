@@ -0,0 +1,388 @@
+package settings
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AlgoKit LocalNet ships a fixed, well-known API token.
+var algokitToken = strings.Repeat("a", 64)
+
+// algokitConfigDir mirrors the platform directories AlgoKit uses.
+func algokitConfigDir() string {
+	home, _ := os.UserHomeDir()
+	switch runtime.GOOS {
+	case "darwin":
+		return filepath.Join(home, "Library", "Application Support", "algokit")
+	case "windows":
+		if appData := os.Getenv("APPDATA"); appData != "" {
+			return filepath.Join(appData, "algokit")
+		}
+	}
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, "algokit")
+	}
+	return filepath.Join(home, ".config", "algokit")
+}
+
+// ImportAlgoKitLocalNet reads every LocalNet AlgoKit has set up: the
+// default "sandbox" and named ones ("sandbox_<name>"). Ports come from
+// the docker-compose file AlgoKit generated.
+func ImportAlgoKitLocalNet() ([]NetworkInfo, error) {
+	dir := algokitConfigDir()
+	sandboxes, _ := filepath.Glob(filepath.Join(dir, "sandbox*"))
+	sort.Strings(sandboxes)
+
+	var found []NetworkInfo
+	for _, sb := range sandboxes {
+		compose, err := os.ReadFile(filepath.Join(sb, "docker-compose.yml"))
+		if err != nil {
+			continue
+		}
+		ports := composePorts(string(compose))
+
+		name := "algokit-localnet"
+		if suffix := strings.TrimPrefix(filepath.Base(sb), "sandbox_"); suffix != filepath.Base(sb) {
+			name = "algokit-" + suffix
+		}
+		info := NetworkInfo{
+			Name:         name,
+			AlgodURL:     "http://localhost",
+			AlgodPort:    portOr(ports["algod"]["8080"], "4001"),
+			AlgodToken:   algokitToken,
+			IndexerURL:   "http://localhost",
+			IndexerPort:  portOr(ports["indexer"]["8980"], "8980"),
+			IndexerToken: algokitToken,
+			KmdURL:       "http://localhost:" + portOr(ports["algod"]["7833"], "4002"),
+			KmdToken:     algokitToken,
+		}
+		found = append(found, info)
+	}
+	if len(found) == 0 {
+		return nil, fmt.Errorf("no AlgoKit LocalNet found in %s, run `algokit localnet start` first", dir)
+	}
+	return found, nil
+}
+
+func portOr(port, def string) string {
+	if port == "" {
+		return def
+	}
+	return port
+}
+
+// composePorts extracts "host:container" port mappings per service from a
+// docker-compose file. Only the plain list syntax AlgoKit writes is read.
+func composePorts(compose string) map[string]map[string]string {
+	ports := map[string]map[string]string{}
+	service := ""
+	inPorts := false
+	inServices := false
+
+	scanner := bufio.NewScanner(strings.NewReader(compose))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+
+		switch {
+		case indent == 0:
+			inServices = trimmed == "services:"
+			service, inPorts = "", false
+		case inServices && indent == 2 && strings.HasSuffix(trimmed, ":"):
+			service = strings.TrimSuffix(trimmed, ":")
+			ports[service] = map[string]string{}
+			inPorts = false
+		case service != "" && indent == 4:
+			inPorts = trimmed == "ports:"
+		case inPorts && strings.HasPrefix(trimmed, "- "):
+			mapping := strings.Trim(strings.TrimPrefix(trimmed, "- "), `"'`)
+			parts := strings.Split(mapping, ":")
+			if len(parts) >= 2 {
+				// host[:ip]:container, keep the last two
+				host, container := parts[len(parts)-2], parts[len(parts)-1]
+				ports[service][strings.Split(container, "/")[0]] = host
+			}
+		}
+	}
+	return ports
+}
+
+// ImportDataDir reads the endpoint files a running node writes into its
+// data directory: algod.net and algod.token, and kmd.net and kmd.token in
+// the kmd-v* subdirectory when kmd is running.
+func ImportDataDir(dir string) (NetworkInfo, error) {
+	dir = expandHome(strings.TrimSpace(dir))
+	if dir == "" {
+		return NetworkInfo{}, errors.New("no data directory given and $ALGORAND_DATA is not set")
+	}
+
+	addr, err := readTrimmed(filepath.Join(dir, "algod.net"))
+	if err != nil {
+		return NetworkInfo{}, fmt.Errorf("%s has no algod.net, is the node running? (%w)", dir, err)
+	}
+	token, err := readTrimmed(filepath.Join(dir, "algod.token"))
+	if err != nil {
+		return NetworkInfo{}, fmt.Errorf("failed to read algod.token: %w", err)
+	}
+	host, port, err := dialableHostPort(addr)
+	if err != nil {
+		return NetworkInfo{}, fmt.Errorf("unexpected algod.net %q: %w", addr, err)
+	}
+
+	info := NetworkInfo{
+		Name:       "node-" + filepath.Base(filepath.Clean(dir)),
+		AlgodURL:   "http://" + host,
+		AlgodPort:  port,
+		AlgodToken: token,
+		DataDir:    dir,
+	}
+
+	// Name after the network in genesis.json when there is one
+	if buff, err := os.ReadFile(filepath.Join(dir, "genesis.json")); err == nil {
+		var genesis genesisDoc
+		if json.Unmarshal(buff, &genesis) == nil && genesis.Network != "" {
+			info.Name = "node-" + genesis.Network
+		}
+	}
+
+	kmdDirs, _ := filepath.Glob(filepath.Join(dir, "kmd-v*"))
+	for _, kd := range kmdDirs {
+		kaddr, err := readTrimmed(filepath.Join(kd, "kmd.net"))
+		if err != nil {
+			continue
+		}
+		ktoken, err := readTrimmed(filepath.Join(kd, "kmd.token"))
+		if err != nil {
+			continue
+		}
+		if khost, kport, err := dialableHostPort(kaddr); err == nil {
+			info.KmdURL = fmt.Sprintf("http://%s:%s", khost, kport)
+			info.KmdToken = ktoken
+			info.KmdDir = kd
+		}
+		break
+	}
+	return info, nil
+}
+
+func readTrimmed(path string) (string, error) {
+	buff, err := os.ReadFile(path)
+	return strings.TrimSpace(string(buff)), err
+}
+
+// dialableHostPort turns a listen address into one we can connect to.
+func dialableHostPort(addr string) (string, string, error) {
+	host, port, err := net.SplitHostPort(strings.TrimPrefix(strings.TrimPrefix(addr, "http://"), "https://"))
+	if err != nil {
+		return "", "", err
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "127.0.0.1"
+	}
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	return host, port, nil
+}
+
+func expandHome(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		home, _ := os.UserHomeDir()
+		return filepath.Join(home, path[2:])
+	}
+	return path
+}
+
+// ImportFile reads network definitions shared as JSON or TOML. JSON may
+// hold one network, a list of networks or a whole config.json; TOML uses
+// the same keys, with [network] or [[networks]] tables, each followed by
+// its custom headers in a [network.headers] table if it has any.
+func ImportFile(path string) ([]NetworkInfo, error) {
+	path = expandHome(strings.TrimSpace(path))
+	buff, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var nets []NetworkInfo
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		nets, err = parseNetworksTOML(string(buff))
+	default:
+		nets, err = parseNetworksJSON(buff)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+
+	var valid []NetworkInfo
+	for _, n := range nets {
+		if strings.TrimSpace(n.Name) == "" || strings.TrimSpace(n.AlgodURL) == "" {
+			continue
+		}
+		valid = append(valid, n)
+	}
+	if len(valid) == 0 {
+		return nil, fmt.Errorf("%s contains no network with a name and an algod URL", filepath.Base(path))
+	}
+	return valid, nil
+}
+
+func parseNetworksJSON(buff []byte) ([]NetworkInfo, error) {
+	var probe any
+	if err := json.Unmarshal(buff, &probe); err != nil {
+		return nil, err
+	}
+	switch v := probe.(type) {
+	case []any:
+		var nets []NetworkInfo
+		err := json.Unmarshal(buff, &nets)
+		return nets, err
+	case map[string]any:
+		if _, ok := v["custom_networks"]; ok {
+			var cfg Config
+			err := json.Unmarshal(buff, &cfg)
+			return cfg.CustomNetworks, err
+		}
+		var n NetworkInfo
+		err := json.Unmarshal(buff, &n)
+		return []NetworkInfo{n}, err
+	}
+	return nil, errors.New("expected an object or a list")
+}
+
+// parseNetworksTOML understands the small TOML subset network files
+// need: tables, arrays of tables, a headers table under each, and string,
+// number or boolean values. Anything else is refused rather than read as
+// a network.
+func parseNetworksTOML(src string) ([]NetworkInfo, error) {
+	var tables []map[string]any
+	current := map[string]any{}
+	tables = append(tables, current)
+	// network is the name of the table current was opened with; headers
+	// takes the keys instead while in its headers table
+	network := ""
+	var headers map[string]any
+
+	for i, line := range strings.Split(src, "\n") {
+		line = strings.TrimSpace(stripTOMLComment(line))
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			name, array, err := tomlTable(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			parent, sub, nested := strings.Cut(name, ".")
+			switch {
+			case !nested:
+				current, network, headers = map[string]any{}, name, nil
+				tables = append(tables, current)
+			case !array && sub == "headers" && parent == network && current["headers"] == nil:
+				headers = map[string]any{}
+				current["headers"] = headers
+			default:
+				return nil, fmt.Errorf("line %d: table %s is not supported, only networks and their [<network>.headers]", i+1, line)
+			}
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key = value", i+1)
+		}
+		key = strings.TrimSpace(key)
+		if !strings.HasPrefix(key, `"`) && strings.Contains(key, ".") {
+			return nil, fmt.Errorf("line %d: dotted key %s is not supported, use a table", i+1, key)
+		}
+		key = strings.Trim(key, `"`)
+		value = strings.TrimSpace(value)
+		target := current
+		if headers != nil {
+			target = headers
+			if !strings.HasPrefix(value, `"`) && !strings.HasPrefix(value, "'") {
+				return nil, fmt.Errorf("line %d: header %s must be a string", i+1, key)
+			}
+		}
+		switch {
+		case strings.HasPrefix(value, `"`):
+			s, err := strconv.Unquote(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			target[key] = s
+		case strings.HasPrefix(value, "'"):
+			target[key] = strings.Trim(value, "'")
+		case value == "true" || value == "false":
+			target[key] = value == "true"
+		default:
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				return nil, fmt.Errorf("line %d: unsupported value %q", i+1, value)
+			}
+			// Ports are strings in NetworkInfo
+			target[key] = value
+		}
+	}
+
+	var nets []NetworkInfo
+	for _, t := range tables {
+		if len(t) == 0 {
+			continue
+		}
+		buff, _ := json.Marshal(t)
+		var n NetworkInfo
+		if err := json.Unmarshal(buff, &n); err != nil {
+			return nil, err
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
+
+// tomlTable reads a [table] or [[array]] header, returning the dotted
+// name without quotes.
+func tomlTable(line string) (name string, array bool, err error) {
+	open, end := "[", "]"
+	if strings.HasPrefix(line, "[[") {
+		open, end, array = "[[", "]]", true
+	}
+	if !strings.HasSuffix(line, end) || len(line) < len(open)+len(end) {
+		return "", false, fmt.Errorf("malformed table header %s", line)
+	}
+	parts := strings.Split(line[len(open):len(line)-len(end)], ".")
+	for j, p := range parts {
+		parts[j] = strings.Trim(strings.TrimSpace(p), `"`)
+		if parts[j] == "" {
+			return "", false, fmt.Errorf("malformed table header %s", line)
+		}
+	}
+	return strings.Join(parts, "."), array, nil
+}
+
+func stripTOMLComment(line string) string {
+	inString := false
+	for i, r := range line {
+		switch r {
+		case '"':
+			inString = !inString
+		case '#':
+			if !inString {
+				return line[:i]
+			}
+		}
+	}
+	return line
+}
@@ -0,0 +1,63 @@
+package settings
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseNetworksTOML(t *testing.T) {
+	src := `
+# shared by the team
+[[networks]]
+name = "provider"
+algod_url = "https://mainnet.example"   # primary
+algod_port = 443
+
+[networks.headers]
+X-Api-Key = "secret # not a comment"
+"X-Team" = 'ops'
+
+[[networks]]
+name = "local"
+algod_url = "http://localhost"
+algod_port = "4001"
+insecure_skip_verify = true
+`
+	nets, err := parseNetworksTOML(src)
+	if err != nil {
+		t.Fatalf("parseNetworksTOML: %v", err)
+	}
+	want := []NetworkInfo{
+		{Name: "provider", AlgodURL: "https://mainnet.example", AlgodPort: "443",
+			Headers: map[string]string{"X-Api-Key": "secret # not a comment", "X-Team": "ops"}},
+		{Name: "local", AlgodURL: "http://localhost", AlgodPort: "4001", InsecureSkipVerify: true},
+	}
+	if !reflect.DeepEqual(nets, want) {
+		t.Errorf("got %+v\nwant %+v", nets, want)
+	}
+}
+
+func TestParseNetworksTOMLRefused(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		err  string
+	}{
+		{"nested table", "[network]\nname = \"a\"\n[network.tls]\nca = \"x\"\n", "not supported"},
+		{"nested array", "[[networks]]\nname = \"a\"\n[[networks.algod_fallbacks]]\nurl = \"x\"\n", "not supported"},
+		{"headers of another table", "[a]\nname = \"a\"\n[b.headers]\nX = \"y\"\n", "not supported"},
+		{"headers twice", "[a]\n[a.headers]\nX = \"1\"\n[a.headers]\nY = \"2\"\n", "not supported"},
+		{"dotted key", "[a]\nheaders.X = \"y\"\n", "dotted key"},
+		{"header not a string", "[a]\n[a.headers]\nX = 1\n", "must be a string"},
+		{"inline table", "[a]\nheaders = { X = \"y\" }\n", "unsupported value"},
+		{"malformed header", "[a\n", "malformed"},
+		{"empty name", "[]\n", "malformed"},
+	}
+	for _, tt := range tests {
+		_, err := parseNetworksTOML(tt.src)
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: error = %v, want %q", tt.name, err, tt.err)
+		}
+	}
+}
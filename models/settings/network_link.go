@@ -0,0 +1,118 @@
+package settings
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxReconnectDelay caps the backoff between reconnect attempts.
+const maxReconnectDelay = time.Minute
+
+// Connection states shown in the status bar.
+const (
+	LinkOffline      = "offline"
+	LinkConnecting   = "connecting"
+	LinkConnected    = "connected"
+	LinkReconnecting = "reconnecting"
+)
+
+// LinkState summarises the connection to the configured network.
+type LinkState struct {
+	Network string
+	Status  string
+	Round   uint64
+	Attempt int   // failed attempts in a row while reconnecting
+	Err     error // last failure while reconnecting
+}
+
+// ReconnectMsg fires when a reconnect backoff delay is over. ID ties it
+// to the retry schedule that set it, see stopReconnect.
+type ReconnectMsg struct{ ID int }
+
+// reconnectDelay is the exponential backoff before attempt n (from 0):
+// 1s, 2s, 4s ... up to maxReconnectDelay.
+func reconnectDelay(attempt int) time.Duration {
+	if attempt > 6 {
+		return maxReconnectDelay
+	}
+	return min(time.Second<<attempt, maxReconnectDelay)
+}
+
+// Link reports the connection state for the status bar.
+func (m *SettingsModel) Link() LinkState {
+	nm := m.networkManager
+	link := LinkState{Network: m.config.Network, Status: LinkOffline}
+	switch {
+	case nm.IsConnected():
+		link.Network = nm.GetCurrentNetwork().Name
+		link.Round = nm.lastSample.Round
+		link.Status = LinkConnected
+		if nm.streamErr != nil {
+			link.Status, link.Attempt, link.Err = LinkReconnecting, nm.streamFailures, nm.streamErr
+		}
+	case m.opKind == opConnect || m.opKind == opReconnect:
+		link.Status = LinkConnecting
+	case m.retryPending:
+		link.Status, link.Attempt, link.Err = LinkReconnecting, m.retryAttempt, m.retryErr
+	}
+	return link
+}
+
+// AutoConnect connects to the configured network in the background. It is
+// used at startup and after a profile switch; failures are retried with
+// backoff until a connection is made or the user picks a network.
+func (m *SettingsModel) AutoConnect() tea.Cmd {
+	info, ok := m.networkInfos[m.config.Network]
+	if !ok || m.networkManager.IsConnected() {
+		return nil
+	}
+	return m.startOp(opReconnect, "Connecting to "+info.Name, func(ctx context.Context) NetworkOpMsg {
+		conn, err := dialNetwork(ctx, info)
+		if err != nil {
+			return NetworkOpMsg{Info: info, Err: err}
+		}
+		return NetworkOpMsg{Info: info, Chain: conn.chain, conn: conn}
+	})
+}
+
+// handleAutoConnect applies the result of an AutoConnect attempt.
+func (m *SettingsModel) handleAutoConnect(msg NetworkOpMsg) tea.Cmd {
+	if msg.Err != nil {
+		m.retryErr = msg.Err
+		delay := reconnectDelay(m.retryAttempt)
+		m.retryAttempt++
+		m.retryPending = true
+		id := m.retryID
+		return tea.Tick(delay, func(time.Time) tea.Msg { return ReconnectMsg{ID: id} })
+	}
+	m.stopReconnect()
+	if m.pinOnConnect(msg.conn) {
+		if err := m.persistConfig(); err != nil {
+			m.connectionStatus = fmt.Sprintf("Failed to save the genesis pin of %s: %v", msg.Info.Name, err)
+			m.showStatus = true
+		}
+	}
+	m.networkManager.attach(msg.conn)
+	return tea.Batch(m.networkManager.WatchBlocks(0), m.networkManager.ProbeEndpoints())
+}
+
+// handleReconnect starts the next attempt once the backoff is over.
+func (m *SettingsModel) handleReconnect(msg ReconnectMsg) tea.Cmd {
+	if msg.ID != m.retryID || !m.retryPending || m.opKind != "" {
+		return nil
+	}
+	m.retryPending = false
+	return m.AutoConnect()
+}
+
+// stopReconnect abandons the retry schedule; timers already set are
+// ignored when they fire.
+func (m *SettingsModel) stopReconnect() {
+	m.retryID++
+	m.retryPending = false
+	m.retryAttempt = 0
+	m.retryErr = nil
+}
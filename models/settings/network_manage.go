@@ -0,0 +1,335 @@
+package settings
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// predefinedNetworks are the networks lazychain ships with. They can be
+// customised and reset, but not deleted or renamed.
+func predefinedNetworks() map[string]NetworkInfo {
+	return map[string]NetworkInfo{
+		"localnet": {
+			Name:         "localnet",
+			AlgodURL:     "http://localhost",
+			AlgodPort:    "4001",
+			AlgodToken:   strings.Repeat("a", 64),
+			IndexerURL:   "http://localhost",
+			IndexerPort:  "8980",
+			IndexerToken: strings.Repeat("a", 64),
+		},
+		"testnet": {
+			Name:         "testnet",
+			AlgodURL:     "https://testnet-api.4160.nodely.dev",
+			AlgodPort:    "443",
+			AlgodToken:   "",
+			IndexerURL:   "https://testnet-idx.4160.nodely.dev",
+			IndexerPort:  "443",
+			IndexerToken: "",
+			GenesisID:    TestnetGenesisID,
+			GenesisHash:  TestnetGenesisHash,
+		},
+		"mainnet": {
+			Name:         "mainnet",
+			AlgodURL:     "https://mainnet-api.4160.nodely.dev",
+			AlgodPort:    "443",
+			AlgodToken:   "",
+			IndexerURL:   "https://mainnet-idx.4160.nodely.dev",
+			IndexerPort:  "443",
+			IndexerToken: "",
+			GenesisID:    MainnetGenesisID,
+			GenesisHash:  MainnetGenesisHash,
+		},
+	}
+}
+
+func isPredefined(name string) bool {
+	_, ok := predefinedNetworks()[name]
+	return ok
+}
+
+// Pending confirmations in the network list.
+const (
+	confirmDelete = "delete"
+	confirmReset  = "reset"
+)
+
+// applyNetworkOrder sorts names by order; names not in order keep their
+// relative position after the ordered ones.
+func applyNetworkOrder(names, order []string) []string {
+	present := map[string]bool{}
+	for _, n := range names {
+		present[n] = true
+	}
+	sorted := make([]string, 0, len(names))
+	placed := map[string]bool{}
+	for _, n := range order {
+		if present[n] && !placed[n] {
+			sorted = append(sorted, n)
+			placed[n] = true
+		}
+	}
+	for _, n := range names {
+		if !placed[n] {
+			sorted = append(sorted, n)
+		}
+	}
+	return sorted
+}
+
+func (m *SettingsModel) customIndex(name string) int {
+	for i, n := range m.config.CustomNetworks {
+		if n.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// profilesUsing lists the profiles, other than the active one, that are
+// bound to a network. The active profile follows Config.Network.
+func (m *SettingsModel) profilesUsing(name string) []string {
+	var names []string
+	for _, p := range m.config.Profiles {
+		if p.Network == name && p.Name != m.config.ActiveProfile {
+			names = append(names, p.Name)
+		}
+	}
+	return names
+}
+
+// forgetNetworkSecrets drops the vault entry of a network that no longer
+// exists under that name.
+func (m *SettingsModel) forgetNetworkSecrets(name string) {
+	if m.vault.IsUnlocked() {
+		_ = m.vault.SetNetworkSecrets(name, NetworkSecrets{})
+	}
+}
+
+// checkVaultUnlocked refuses changes that move or drop the vault entry
+// of a network while the vault is locked: the entry would be left behind
+// under the old name.
+func (m *SettingsModel) checkVaultUnlocked(name string) error {
+	if m.config.VaultEnabled && m.vault.Exists() && !m.vault.IsUnlocked() {
+		return fmt.Errorf("unlock the vault (v) first, it may hold the tokens of %s", name)
+	}
+	return nil
+}
+
+// askConfirm starts a delete or reset of the selected network, after
+// checking it is allowed.
+func (m *SettingsModel) askConfirm(action string) {
+	name := m.networks[m.cursor]
+	m.showStatus = true
+	if err := m.checkVaultUnlocked(name); err != nil && (action == confirmDelete || m.customIndex(name) >= 0) {
+		m.connectionStatus = fmt.Sprintf("Cannot %s %s: %v", action, name, err)
+		return
+	}
+	switch action {
+	case confirmDelete:
+		if isPredefined(name) {
+			m.connectionStatus = fmt.Sprintf("%s is predefined and cannot be deleted, press r to reset it", name)
+			return
+		}
+		if used := m.profilesUsing(name); len(used) > 0 {
+			m.connectionStatus = fmt.Sprintf("%s is used by profile %s", name, strings.Join(used, ", "))
+			return
+		}
+		m.connectionStatus = fmt.Sprintf("Delete network %s? (y/n)", name)
+	case confirmReset:
+		if !isPredefined(name) {
+			m.connectionStatus = fmt.Sprintf("%s is not predefined, press d to delete it", name)
+			return
+		}
+		if m.customIndex(name) < 0 {
+			m.connectionStatus = fmt.Sprintf("%s already uses the defaults", name)
+			return
+		}
+		m.connectionStatus = fmt.Sprintf("Reset %s to defaults? (y/n)", name)
+	}
+	m.confirmAction = action
+	m.confirmTarget = name
+}
+
+// handleConfirm answers a pending delete or reset.
+func (m *SettingsModel) handleConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	action, name := m.confirmAction, m.confirmTarget
+	switch msg.String() {
+	case "y", "Y", "enter":
+		m.confirmAction, m.confirmTarget = "", ""
+		var err error
+		if action == confirmDelete {
+			err = m.deleteNetwork(name)
+		} else {
+			err = m.resetNetwork(name)
+		}
+		if err != nil {
+			m.connectionStatus = fmt.Sprintf("Failed to %s %s: %v", action, name, err)
+		}
+		m.showStatus = true
+	case "n", "N", "esc":
+		m.confirmAction, m.confirmTarget = "", ""
+		m.showStatus = false
+		m.connectionStatus = ""
+	}
+	return m, nil
+}
+
+// deleteNetwork removes a custom network. When it was the selected one,
+// the first remaining network takes its place.
+func (m *SettingsModel) deleteNetwork(name string) error {
+	i := m.customIndex(name)
+	if i < 0 || isPredefined(name) {
+		return fmt.Errorf("not a custom network")
+	}
+	m.config.CustomNetworks = append(m.config.CustomNetworks[:i], m.config.CustomNetworks[i+1:]...)
+	delete(m.networkInfos, name)
+	for j, n := range m.networks {
+		if n == name {
+			m.networks = append(m.networks[:j], m.networks[j+1:]...)
+			break
+		}
+	}
+	m.config.NetworkOrder = removeName(m.config.NetworkOrder, name)
+	m.forgetNetworkSecrets(name)
+
+	if m.networkManager.IsConnected() && m.networkManager.GetCurrentNetwork().Name == name {
+		m.networkManager.Disconnect()
+	}
+	if m.config.Network == name {
+		m.config.Network = m.networks[0]
+		m.overrides.Network = ""
+	}
+	if m.fileNetwork == name {
+		m.fileNetwork = m.networks[0]
+	}
+	if m.cursor >= len(m.networks) {
+		m.cursor = len(m.networks) - 1
+	}
+	if err := m.persistConfig(); err != nil {
+		return err
+	}
+	m.connectionStatus = fmt.Sprintf("Network %s deleted", name)
+	return nil
+}
+
+// resetNetwork drops the customisation of a predefined network.
+func (m *SettingsModel) resetNetwork(name string) error {
+	def, ok := predefinedNetworks()[name]
+	i := m.customIndex(name)
+	if !ok || i < 0 {
+		return fmt.Errorf("nothing to reset")
+	}
+	m.config.CustomNetworks = append(m.config.CustomNetworks[:i], m.config.CustomNetworks[i+1:]...)
+	m.networkInfos[name] = def
+	m.forgetNetworkSecrets(name)
+	if m.shadowed.Name == name {
+		m.shadowed = def
+		m.applyOverrides()
+	}
+	// The live connection still uses the old endpoints
+	if m.networkManager.IsConnected() && m.networkManager.GetCurrentNetwork().Name == name {
+		m.networkManager.Disconnect()
+	}
+	if err := m.persistConfig(); err != nil {
+		return err
+	}
+	m.connectionStatus = fmt.Sprintf("Network %s reset to defaults", name)
+	return nil
+}
+
+// checkRename tells whether the network being edited may take its new
+// name.
+func (m *SettingsModel) checkRename(from, to string) error {
+	if from == to {
+		return nil
+	}
+	if from == "" {
+		if _, exists := m.networkInfos[to]; exists {
+			return fmt.Errorf("network %s already exists, select it and press n to edit", to)
+		}
+		return nil
+	}
+	if isPredefined(from) {
+		return fmt.Errorf("predefined networks cannot be renamed, press c to create a new one")
+	}
+	if err := m.checkVaultUnlocked(from); err != nil {
+		return err
+	}
+	if _, exists := m.networkInfos[to]; exists {
+		return fmt.Errorf("network %s already exists", to)
+	}
+	return nil
+}
+
+// renameNetwork moves a custom network, and everything that refers to
+// it, to a new name. The caller persists the config.
+func (m *SettingsModel) renameNetwork(from, to string) {
+	if i := m.customIndex(from); i >= 0 {
+		m.config.CustomNetworks[i].Name = to
+	}
+	if info, ok := m.networkInfos[from]; ok {
+		info.Name = to
+		m.networkInfos[to] = info
+		delete(m.networkInfos, from)
+	}
+	for i, n := range m.networks {
+		if n == from {
+			m.networks[i] = to
+		}
+	}
+	for i, n := range m.config.NetworkOrder {
+		if n == from {
+			m.config.NetworkOrder[i] = to
+		}
+	}
+	for i, p := range m.config.Profiles {
+		if p.Network == from {
+			m.config.Profiles[i].Network = to
+		}
+	}
+	if m.config.Network == from {
+		m.config.Network = to
+	}
+	if m.fileNetwork == from {
+		m.fileNetwork = to
+	}
+	if m.overrides.Network == from {
+		m.overrides.Network = to
+	}
+	if m.shadowed.Name == from {
+		m.shadowed.Name = to
+	}
+	if m.networkManager.currentNetwork.Name == from {
+		m.networkManager.currentNetwork.Name = to
+	}
+	m.forgetNetworkSecrets(from)
+}
+
+// moveNetwork shifts the selected network up (-1) or down (+1) in the
+// list and remembers the order.
+func (m *SettingsModel) moveNetwork(delta int) {
+	j := m.cursor + delta
+	if j < 0 || j >= len(m.networks) {
+		return
+	}
+	m.networks[m.cursor], m.networks[j] = m.networks[j], m.networks[m.cursor]
+	m.cursor = j
+	m.config.NetworkOrder = append([]string(nil), m.networks...)
+	if err := m.persistConfig(); err != nil {
+		m.connectionStatus = fmt.Sprintf("Failed to save config: %v", err)
+		m.showStatus = true
+	}
+}
+
+func removeName(names []string, name string) []string {
+	kept := names[:0]
+	for _, n := range names {
+		if n != name {
+			kept = append(kept, n)
+		}
+	}
+	return kept
+}
@@ -5,16 +5,26 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/algorand/go-algorand-sdk/client/v2/algod"
-	"github.com/algorand/go-algorand-sdk/client/v2/indexer"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/indexer"
 )
 
 // NetworkManager owns the live connections and current selection.
 type NetworkManager struct {
 	algodClient    *algod.Client
 	indexerClient  *indexer.Client
+	algodPool      *endpointPool
+	indexerPool    *endpointPool
 	currentNetwork NetworkInfo
+	chain          ChainIdentity
 	connected      bool
+
+	// Block stream state; epoch changes on every (dis)connect so that
+	// messages from an old stream can be recognised and dropped.
+	epoch          uint64
+	lastSample     BlockSample
+	streamErr      error
+	streamFailures int // failed waits in a row, drives the retry backoff
 }
 
 // NewNetworkManager creates a new network manager.
@@ -23,63 +33,119 @@ func NewNetworkManager() *NetworkManager {
 }
 
 // TestNetworkConnection tests connection to a network without storing it.
-func (nm *NetworkManager) TestNetworkConnection(networkInfo NetworkInfo) error {
-	algodClient, err := createAlgodClient(networkInfo)
-	if err != nil {
-		return fmt.Errorf("failed to create algod client: %w", err)
-	}
-
+// It returns the identity of the chain served by the node, and fails if
+// the network is pinned to a different genesis.
+func (nm *NetworkManager) TestNetworkConnection(networkInfo NetworkInfo) (ChainIdentity, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
+	return testNetwork(ctx, networkInfo)
+}
+
+// testNetwork is TestNetworkConnection bounded by ctx, so callers running
+// it in the background can cancel it.
+func testNetwork(ctx context.Context, networkInfo NetworkInfo) (ChainIdentity, error) {
+	algodClient, _, err := createAlgodClient(networkInfo)
+	if err != nil {
+		return ChainIdentity{}, fmt.Errorf("failed to create algod client: %w", err)
+	}
 
 	if _, err = algodClient.Status().Do(ctx); err != nil {
-		return fmt.Errorf("algod connection test failed: %w", err)
+		return ChainIdentity{}, fmt.Errorf("algod connection test failed: %w", err)
+	}
+
+	chain, err := fetchChainIdentity(ctx, algodClient)
+	if err != nil {
+		return ChainIdentity{}, err
+	}
+	if err := chain.CheckPinned(networkInfo); err != nil {
+		return chain, err
 	}
 
 	// Indexer optional
 	if networkInfo.IndexerURL != "" {
-		indexerClient, err := createIndexerClient(networkInfo)
+		indexerClient, _, err := createIndexerClient(networkInfo)
 		if err != nil {
-			return fmt.Errorf("failed to create indexer client: %w", err)
+			return chain, fmt.Errorf("failed to create indexer client: %w", err)
 		}
 		if _, err = indexerClient.SearchForApplications().Limit(1).Do(ctx); err != nil {
-			return fmt.Errorf("indexer connection test failed: %w", err)
+			return chain, fmt.Errorf("indexer connection test failed: %w", err)
 		}
 	}
-	return nil
+	return chain, nil
 }
 
-// ConnectToNetwork establishes connection to the specified network.
-func (nm *NetworkManager) ConnectToNetwork(networkInfo NetworkInfo) error {
-	algodClient, err := createAlgodClient(networkInfo)
+// connection is a network dialled and verified, ready to be attached to
+// the manager. Dialling happens off the UI goroutine, attaching on it.
+type connection struct {
+	info          NetworkInfo
+	algodClient   *algod.Client
+	indexerClient *indexer.Client
+	algodPool     *endpointPool
+	indexerPool   *endpointPool
+	chain         ChainIdentity
+	indexerErr    error // indexer unreachable, the connection works without it
+}
+
+// dialNetwork creates the clients of a network and checks the node
+// serves the chain the network is pinned to.
+func dialNetwork(ctx context.Context, networkInfo NetworkInfo) (*connection, error) {
+	algodClient, algodPool, err := createAlgodClient(networkInfo)
 	if err != nil {
-		return fmt.Errorf("failed to connect to algod: %w", err)
+		return nil, fmt.Errorf("failed to connect to algod: %w", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
 	if _, err = algodClient.Status().Do(ctx); err != nil {
-		return fmt.Errorf("failed to get network status: %w", err)
+		return nil, fmt.Errorf("failed to get network status: %w", err)
+	}
+
+	// Never attach to a node serving another chain than the saved one.
+	chain, err := fetchChainIdentity(ctx, algodClient)
+	if err != nil {
+		return nil, err
+	}
+	if err := chain.CheckPinned(networkInfo); err != nil {
+		return nil, err
 	}
 
-	var indexerClient *indexer.Client
+	conn := &connection{info: networkInfo, algodClient: algodClient, algodPool: algodPool, chain: chain}
 	if networkInfo.IndexerURL != "" {
-		indexerClient, err = createIndexerClient(networkInfo)
-		if err == nil {
-			if _, err = indexerClient.SearchForApplications().Limit(1).Do(ctx); err != nil {
-				fmt.Printf("Warning: indexer connection test failed: %v\n", err)
-				indexerClient = nil
-			}
+		indexerClient, indexerPool, err := createIndexerClient(networkInfo)
+		if err != nil {
+			conn.indexerErr = fmt.Errorf("failed to connect to indexer: %w", err)
+		} else if _, err = indexerClient.SearchForApplications().Limit(1).Do(ctx); err != nil {
+			conn.indexerErr = fmt.Errorf("indexer connection test failed: %w", err)
 		} else {
-			fmt.Printf("Warning: failed to connect to indexer: %v\n", err)
+			conn.indexerClient, conn.indexerPool = indexerClient, indexerPool
 		}
 	}
+	return conn, nil
+}
 
-	nm.algodClient = algodClient
-	nm.indexerClient = indexerClient
-	nm.currentNetwork = networkInfo
+// attach makes a dialled connection the live one.
+func (nm *NetworkManager) attach(conn *connection) {
+	nm.algodClient = conn.algodClient
+	nm.indexerClient = conn.indexerClient
+	nm.algodPool = conn.algodPool
+	nm.indexerPool = conn.indexerPool
+	nm.currentNetwork = conn.info
+	nm.chain = conn.chain
 	nm.connected = true
+	nm.epoch++
+	nm.lastSample = BlockSample{}
+	nm.streamErr = nil
+	nm.streamFailures = 0
+}
+
+// ConnectToNetwork establishes connection to the specified network.
+func (nm *NetworkManager) ConnectToNetwork(networkInfo NetworkInfo) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	conn, err := dialNetwork(ctx, networkInfo)
+	if err != nil {
+		return err
+	}
+	nm.attach(conn)
 	return nil
 }
 
@@ -97,7 +163,7 @@ func (nm *NetworkManager) GetNetworkStatus() (NetworkStatus, error) {
 		return NetworkStatus{}, fmt.Errorf("failed to get network status: %w", err)
 	}
 
-	genesisResponse, err := nm.algodClient.GetGenesis().Do(ctx)
+	chain, err := fetchChainIdentity(ctx, nm.algodClient)
 	if err != nil {
 		return NetworkStatus{}, fmt.Errorf("failed to get genesis info: %w", err)
 	}
@@ -109,22 +175,16 @@ func (nm *NetworkManager) GetNetworkStatus() (NetworkStatus, error) {
 		indexerHealthy = (err == nil)
 	}
 
-	// Extract genesis info - original code used placeholders
-	genesisID := "unknown"
-	genesisHash := "unknown"
-	if len(genesisResponse) > 0 {
-		genesisID = "genesis-available"
-		genesisHash = nm.currentNetwork.Name
-	}
-
 	return NetworkStatus{
 		NetworkName:    nm.currentNetwork.Name,
 		AlgodURL:       nm.currentNetwork.AlgodURL,
 		IndexerURL:     nm.currentNetwork.IndexerURL,
 		Connected:      true,
 		LastRound:      status.LastRound,
-		GenesisID:      genesisID,
-		GenesisHash:    genesisHash,
+		GenesisID:      chain.GenesisID,
+		GenesisHash:    chain.GenesisHash,
+		ProtoVersion:   status.LastVersion,
+		BuildVersion:   chain.BuildVersion,
 		IndexerHealthy: indexerHealthy,
 		Timestamp:      time.Now(),
 	}, nil
@@ -132,12 +192,46 @@ func (nm *NetworkManager) GetNetworkStatus() (NetworkStatus, error) {
 
 func (nm *NetworkManager) IsConnected() bool                 { return nm.connected && nm.algodClient != nil }
 func (nm *NetworkManager) GetCurrentNetwork() NetworkInfo    { return nm.currentNetwork }
+func (nm *NetworkManager) Chain() ChainIdentity              { return nm.chain }
 func (nm *NetworkManager) GetAlgodClient() *algod.Client     { return nm.algodClient }
 func (nm *NetworkManager) GetIndexerClient() *indexer.Client { return nm.indexerClient }
 
+// LastSample returns the most recent block observed by the block stream.
+func (nm *NetworkManager) LastSample() BlockSample { return nm.lastSample }
+
+// StreamErr returns the last block stream error, nil while healthy.
+func (nm *NetworkManager) StreamErr() error { return nm.streamErr }
+
+// AlgodHealth returns the health of every algod endpoint of the
+// connected network, in configuration order.
+func (nm *NetworkManager) AlgodHealth() []EndpointHealth {
+	if nm.algodPool == nil {
+		return nil
+	}
+	return nm.algodPool.Snapshot()
+}
+
+// IndexerHealth is the indexer counterpart of AlgodHealth.
+func (nm *NetworkManager) IndexerHealth() []EndpointHealth {
+	if nm.indexerPool == nil {
+		return nil
+	}
+	return nm.indexerPool.Snapshot()
+}
+
+// Epoch identifies the current connection, see BlockMsg.
+func (nm *NetworkManager) Epoch() uint64 { return nm.epoch }
+
 func (nm *NetworkManager) Disconnect() {
 	nm.algodClient = nil
 	nm.indexerClient = nil
+	nm.algodPool = nil
+	nm.indexerPool = nil
 	nm.connected = false
 	nm.currentNetwork = NetworkInfo{}
+	nm.chain = ChainIdentity{}
+	nm.epoch++
+	nm.lastSample = BlockSample{}
+	nm.streamErr = nil
+	nm.streamFailures = 0
 }
@@ -0,0 +1,286 @@
+package settings
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// networkOpTimeout bounds every background test, connect or save.
+const networkOpTimeout = 10 * time.Second
+
+// Kinds of background network operation.
+const (
+	opTest    = "test"
+	opConnect = "connect"
+	opSave    = "save"
+	// Automatic connect at startup and its retries, see network_link.go
+	opReconnect = "reconnect"
+)
+
+// NetworkOpMsg reports the end of a background network operation. ID ties
+// it to the operation that produced it; results of cancelled or replaced
+// operations are dropped.
+type NetworkOpMsg struct {
+	ID    int
+	Kind  string
+	Info  NetworkInfo
+	From  string // save: name of the network before editing
+	Chain ChainIdentity
+	Err   error
+	conn  *connection
+}
+
+// SurveyResult is one network's row in the "test all networks" table.
+type SurveyResult struct {
+	Network        string
+	Done           bool
+	Latency        time.Duration
+	Round          uint64
+	GenesisID      string
+	Indexer        string // "ok", "none" or "down"
+	IndexerLatency time.Duration
+	Err            error
+}
+
+// NetworkSurveyMsg delivers one row of a running survey.
+type NetworkSurveyMsg struct {
+	ID     int
+	Result SurveyResult
+}
+
+func newOpSpinner() spinner.Model {
+	return spinner.New(spinner.WithSpinner(spinner.Dot))
+}
+
+// Busy reports whether a network operation or survey is running.
+func (m *SettingsModel) Busy() bool { return m.opKind != "" || m.surveyPending > 0 }
+
+// startOp runs op in the background with a spinner. A running operation
+// is cancelled first: only the last one started reports back.
+func (m *SettingsModel) startOp(kind, label string, op func(ctx context.Context) NetworkOpMsg) tea.Cmd {
+	m.cancelOp()
+	m.opID++
+	id := m.opID
+	ctx, cancel := context.WithTimeout(context.Background(), networkOpTimeout)
+	m.opKind, m.opLabel, m.opCancel = kind, label, cancel
+	if kind != opReconnect {
+		m.showStatus = false
+		m.connectionStatus = ""
+	}
+
+	return tea.Batch(m.spinner.Tick, func() tea.Msg {
+		defer cancel()
+		msg := op(ctx)
+		msg.ID, msg.Kind = id, kind
+		return msg
+	})
+}
+
+// cancelOp abandons the running operation, if any.
+func (m *SettingsModel) cancelOp() {
+	if m.opCancel != nil {
+		m.opCancel()
+	}
+	m.opKind, m.opLabel, m.opCancel = "", "", nil
+}
+
+// testNetworkCmd checks the selected network in the background.
+func (m *SettingsModel) testNetworkCmd(info NetworkInfo) tea.Cmd {
+	return m.startOp(opTest, "Testing "+info.Name, func(ctx context.Context) NetworkOpMsg {
+		chain, err := testNetwork(ctx, info)
+		return NetworkOpMsg{Info: info, Chain: chain, Err: err}
+	})
+}
+
+// connectCmd tests and dials a network in the background; the connection
+// is attached when the result arrives.
+func (m *SettingsModel) connectCmd(info NetworkInfo) tea.Cmd {
+	// An explicit choice ends automatic reconnects
+	m.stopReconnect()
+	return m.startOp(opConnect, "Connecting to "+info.Name, func(ctx context.Context) NetworkOpMsg {
+		if _, err := testNetwork(ctx, info); err != nil {
+			return NetworkOpMsg{Info: info, Err: err}
+		}
+		conn, err := dialNetwork(ctx, info)
+		if err != nil {
+			return NetworkOpMsg{Info: info, Err: fmt.Errorf("connection failed: %w", err)}
+		}
+		return NetworkOpMsg{Info: info, Chain: conn.chain, conn: conn}
+	})
+}
+
+// saveCmd tests an edited network before it is stored.
+func (m *SettingsModel) saveCmd(info NetworkInfo, from string) tea.Cmd {
+	return m.startOp(opSave, "Checking "+info.Name, func(ctx context.Context) NetworkOpMsg {
+		chain, err := testNetwork(ctx, info)
+		return NetworkOpMsg{Info: info, From: from, Chain: chain, Err: err}
+	})
+}
+
+// handleNetworkOp applies the result of a background operation.
+func (m *SettingsModel) handleNetworkOp(msg NetworkOpMsg) tea.Cmd {
+	if msg.ID != m.opID || m.opKind == "" {
+		return nil // cancelled or replaced
+	}
+	m.cancelOp()
+	if msg.Kind == opReconnect {
+		return m.handleAutoConnect(msg)
+	}
+	m.showStatus = true
+	name := msg.Info.Name
+
+	switch msg.Kind {
+	case opTest:
+		if msg.Err != nil {
+			m.connectionStatus = fmt.Sprintf("Test failed for %s: %v", name, msg.Err)
+		} else {
+			m.connectionStatus = fmt.Sprintf("Test successful for %s (%s)", name, msg.Chain.GenesisID)
+		}
+	case opConnect:
+		if msg.Err != nil {
+			m.connectionStatus = fmt.Sprintf("Failed to connect to %s: %v", name, msg.Err)
+			return nil
+		}
+		m.config.Network = name
+		m.overrides.Network = ""
+		m.pinOnConnect(msg.conn)
+		m.networkManager.attach(msg.conn)
+		m.connectionStatus = fmt.Sprintf("Successfully connected to %s", name)
+		if msg.conn.indexerErr != nil {
+			m.connectionStatus += ", indexer unavailable"
+		}
+		if err := m.persistConfig(); err != nil {
+			m.connectionStatus = fmt.Sprintf("Failed to save config: %v", err)
+		}
+		return tea.Batch(m.networkManager.WatchBlocks(0), m.networkManager.ProbeEndpoints())
+	case opSave:
+		if msg.Err != nil {
+			m.connectionStatus = fmt.Sprintf("Connection test failed: %v", msg.Err)
+			return nil
+		}
+		m.storeNetwork(msg.Info, msg.From, msg.Chain)
+	}
+	return nil
+}
+
+// pinOnConnect pins a network that is not pinned yet to the chain of its
+// first successful connection, so a node later switched to another chain
+// is refused. The caller persists the config.
+func (m *SettingsModel) pinOnConnect(conn *connection) bool {
+	if conn.info.GenesisHash != "" {
+		return false
+	}
+	conn.info = conn.chain.Pin(conn.info)
+	m.networkInfos[conn.info.Name] = conn.info
+	for i, n := range m.config.CustomNetworks {
+		if n.Name == conn.info.Name {
+			m.config.CustomNetworks[i] = conn.chain.Pin(n)
+		}
+	}
+	return true
+}
+
+// startSurvey probes every network in parallel for the comparison table.
+func (m *SettingsModel) startSurvey() tea.Cmd {
+	if m.surveyCancel != nil {
+		m.surveyCancel()
+	}
+	m.surveyID++
+	id := m.surveyID
+	ctx, cancel := context.WithTimeout(context.Background(), networkOpTimeout)
+	m.surveyCancel = cancel
+	m.surveyOpen = true
+	m.survey = make([]SurveyResult, len(m.networks))
+	m.surveyPending = len(m.networks)
+
+	cmds := []tea.Cmd{m.spinner.Tick}
+	for i, name := range m.networks {
+		m.survey[i] = SurveyResult{Network: name}
+		info, ok := m.networkInfos[name]
+		if !ok {
+			info = NetworkInfo{Name: name}
+		}
+		cmds = append(cmds, func() tea.Msg {
+			return NetworkSurveyMsg{ID: id, Result: surveyNetwork(ctx, info)}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+// closeSurvey hides the table and stops probes still running.
+func (m *SettingsModel) closeSurvey() {
+	if m.surveyCancel != nil {
+		m.surveyCancel()
+		m.surveyCancel = nil
+	}
+	m.surveyOpen = false
+	m.surveyPending = 0
+}
+
+func (m *SettingsModel) handleSurveyResult(msg NetworkSurveyMsg) {
+	if msg.ID != m.surveyID || !m.surveyOpen {
+		return
+	}
+	for i, r := range m.survey {
+		if r.Network == msg.Result.Network && !r.Done {
+			m.survey[i] = msg.Result
+			m.surveyPending--
+			break
+		}
+	}
+	if m.surveyPending == 0 && m.surveyCancel != nil {
+		m.surveyCancel()
+		m.surveyCancel = nil
+	}
+}
+
+// surveyNetwork measures one network: algod latency and round, chain,
+// and whether the indexer answers.
+func surveyNetwork(ctx context.Context, info NetworkInfo) SurveyResult {
+	res := SurveyResult{Network: info.Name, Done: true, Indexer: "none"}
+
+	algodClient, _, err := createAlgodClient(info)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	start := time.Now()
+	status, err := algodClient.Status().Do(ctx)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	res.Latency = time.Since(start)
+	res.Round = status.LastRound
+	if chain, err := fetchChainIdentity(ctx, algodClient); err == nil {
+		res.GenesisID = chain.GenesisID
+		res.Err = chain.CheckPinned(info)
+	}
+
+	if info.IndexerURL != "" {
+		res.Indexer = "down"
+		if indexerClient, _, err := createIndexerClient(info); err == nil {
+			start = time.Now()
+			if _, err := indexerClient.SearchForApplications().Limit(1).Do(ctx); err == nil {
+				res.Indexer = "ok"
+				res.IndexerLatency = time.Since(start)
+			}
+		}
+	}
+	return res
+}
+
+// handleSurvey handles keys while the survey table is shown.
+func (m *SettingsModel) handleSurvey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.closeSurvey()
+	case "r", "T":
+		return m, m.startSurvey()
+	}
+	return m, nil
+}
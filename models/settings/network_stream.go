@@ -0,0 +1,117 @@
+package settings
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// BlockSample is one observation pushed by the block stream.
+type BlockSample struct {
+	Round       uint64
+	Timestamp   time.Time // block header timestamp (second resolution)
+	TxnCounter  uint64    // cumulative counter from the block header
+	PendingTxns uint64    // transactions waiting in the node's pool
+	Status      models.NodeStatus
+	ReceivedAt  time.Time
+}
+
+// BlockMsg is delivered to the program every time a new block is observed,
+// or when the stream hits an error. Epoch ties the message to the
+// connection that produced it so stale streams can be dropped.
+type BlockMsg struct {
+	Epoch   uint64
+	Network string
+	Sample  BlockSample
+	Err     error
+}
+
+// WatchBlocks returns a command that waits for the first block after
+// afterRound and reports it as a BlockMsg. The receiver re-issues it with
+// the new round to keep the subscription running.
+func (nm *NetworkManager) WatchBlocks(afterRound uint64) tea.Cmd {
+	client := nm.algodClient
+	epoch := nm.epoch
+	network := nm.currentNetwork.Name
+	if client == nil {
+		return nil
+	}
+
+	return func() tea.Msg {
+		// algod releases status-after-block after about a minute even
+		// without a new block, so the timeout just needs to be above that.
+		ctx, cancel := context.WithTimeout(context.Background(), 90*time.Second)
+		defer cancel()
+
+		status, err := client.StatusAfterBlock(afterRound).Do(ctx)
+		if err != nil {
+			return BlockMsg{Epoch: epoch, Network: network, Err: fmt.Errorf("status after block %d: %w", afterRound, err)}
+		}
+
+		sample := BlockSample{
+			Round:      status.LastRound,
+			Status:     status,
+			ReceivedAt: time.Now(),
+		}
+
+		hctx, hcancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer hcancel()
+		if block, err := client.Block(status.LastRound).HeaderOnly(true).Do(hctx); err == nil {
+			sample.Timestamp = time.Unix(block.TimeStamp, 0)
+			sample.TxnCounter = block.TxnCounter
+		}
+		if total, _, err := client.PendingTransactions().Max(1).Do(hctx); err == nil {
+			sample.PendingTxns = total
+		}
+
+		return BlockMsg{Epoch: epoch, Network: network, Sample: sample}
+	}
+}
+
+// EndpointsProbedMsg reports that a health probe of all endpoints of the
+// connected network has finished.
+type EndpointsProbedMsg struct{ Epoch uint64 }
+
+// ProbeEndpoints pings every algod and indexer endpoint in the background
+// to refresh their health scores.
+func (nm *NetworkManager) ProbeEndpoints() tea.Cmd {
+	pools := []*endpointPool{nm.algodPool, nm.indexerPool}
+	epoch := nm.epoch
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		for _, p := range pools {
+			if p != nil {
+				p.Probe(ctx)
+			}
+		}
+		return EndpointsProbedMsg{Epoch: epoch}
+	}
+}
+
+// handleBlockMsg records a block observation and schedules the next wait.
+func (m *SettingsModel) handleBlockMsg(msg BlockMsg) tea.Cmd {
+	nm := m.networkManager
+	if msg.Epoch != nm.epoch || !nm.IsConnected() {
+		return nil // stream belongs to a previous connection
+	}
+	if msg.Err != nil {
+		// The node dropped: keep waiting on it, backing off, the
+		// endpoint pool fails over to a fallback when there is one
+		nm.streamErr = msg.Err
+		delay := reconnectDelay(nm.streamFailures)
+		nm.streamFailures++
+		next := nm.WatchBlocks(nm.lastSample.Round)
+		return func() tea.Msg {
+			time.Sleep(delay)
+			return next()
+		}
+	}
+	nm.streamErr = nil
+	nm.streamFailures = 0
+	nm.lastSample = msg.Sample
+	return nm.WatchBlocks(msg.Sample.Round)
+}
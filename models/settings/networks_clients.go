@@ -5,8 +5,8 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/algorand/go-algorand-sdk/client/v2/algod"
-	"github.com/algorand/go-algorand-sdk/client/v2/indexer"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/indexer"
 )
 
 func trimSlash(s string) string {
@@ -23,41 +23,41 @@ func hasExplicitPort(u string) bool {
 	return i > j+2
 }
 
-// createAlgodClient creates an algod client from network info
-func createAlgodClient(networkInfo NetworkInfo) (*algod.Client, error) {
-	base := strings.TrimSpace(networkInfo.AlgodURL)
-
-	if hasScheme(base) {
-		base = trimSlash(base)
-		// Append a non-default, explicit port if provided and not already present
-		if networkInfo.AlgodPort != "" && !hasExplicitPort(base) &&
-			networkInfo.AlgodPort != "443" && networkInfo.AlgodPort != "80" {
-			base = fmt.Sprintf("%s:%s", base, networkInfo.AlgodPort)
-		}
-	} else {
-		base = fmt.Sprintf("%s:%s", trimSlash(base), networkInfo.AlgodPort)
-	}
+// Token headers used by the SDK clients.
+const (
+	algodTokenHeader   = "X-Algo-API-Token"
+	indexerTokenHeader = "X-Indexer-API-Token"
+)
 
-	return algod.MakeClient(base, networkInfo.AlgodToken)
+// createAlgodClient creates an algod client from network info. Requests are
+// routed through an endpoint pool over the primary and fallback endpoints.
+func createAlgodClient(networkInfo NetworkInfo) (*algod.Client, *endpointPool, error) {
+	base, err := newTransport(networkInfo)
+	if err != nil {
+		return nil, nil, err
+	}
+	pool := newEndpointPool("algod", networkInfo.tokenHeader(algodTokenHeader), networkInfo.AlgodEndpoints(), base)
+	client, err := algod.MakeClientWithTransport(poolBaseURL, "", nil, pool)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, pool, nil
 }
 
 // createIndexerClient creates an indexer client from network info
-func createIndexerClient(networkInfo NetworkInfo) (*indexer.Client, error) {
+func createIndexerClient(networkInfo NetworkInfo) (*indexer.Client, *endpointPool, error) {
 	if networkInfo.IndexerURL == "" {
-		return nil, fmt.Errorf("indexer URL not provided")
+		return nil, nil, fmt.Errorf("indexer URL not provided")
 	}
 
-	base := strings.TrimSpace(networkInfo.IndexerURL)
-
-	if hasScheme(base) {
-		base = trimSlash(base)
-		if networkInfo.IndexerPort != "" && !hasExplicitPort(base) &&
-			networkInfo.IndexerPort != "443" && networkInfo.IndexerPort != "80" {
-			base = fmt.Sprintf("%s:%s", base, networkInfo.IndexerPort)
-		}
-	} else {
-		base = fmt.Sprintf("%s:%s", trimSlash(base), networkInfo.IndexerPort)
+	base, err := newTransport(networkInfo)
+	if err != nil {
+		return nil, nil, err
 	}
-
-	return indexer.MakeClient(base, networkInfo.IndexerToken)
+	pool := newEndpointPool("indexer", networkInfo.tokenHeader(indexerTokenHeader), networkInfo.IndexerEndpoints(), base)
+	client, err := indexer.MakeClientWithTransport(poolBaseURL, "", nil, pool)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, pool, nil
 }
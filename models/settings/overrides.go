@@ -0,0 +1,168 @@
+package settings
+
+import (
+	"lazychain/misc"
+)
+
+// Overrides are runtime values layered over the config file, from the
+// command line or the LAZYCHAIN_* environment. Precedence, highest first:
+// flags, environment, config.json, built-in defaults. Overrides are never
+// written back to config.json.
+type Overrides struct {
+	ConfigPath   string
+	Network      string
+	AlgodURL     string
+	AlgodToken   string
+	IndexerURL   string
+	IndexerToken string
+	WalletAddr   string
+	GoalBinary   string
+	DataDir      string
+}
+
+// Environment variables read by OverridesFromEnv.
+const (
+	EnvConfigPath   = "LAZYCHAIN_CONFIG"
+	EnvNetwork      = "LAZYCHAIN_NETWORK"
+	EnvAlgodURL     = "LAZYCHAIN_ALGOD_URL"
+	EnvAlgodToken   = "LAZYCHAIN_ALGOD_TOKEN"
+	EnvIndexerURL   = "LAZYCHAIN_INDEXER_URL"
+	EnvIndexerToken = "LAZYCHAIN_INDEXER_TOKEN"
+	EnvWalletAddr   = "LAZYCHAIN_WALLET"
+	EnvGoalBinary   = "LAZYCHAIN_GOAL"
+	EnvDataDir      = "LAZYCHAIN_DATA_DIR"
+)
+
+func OverridesFromEnv() Overrides {
+	return Overrides{
+		ConfigPath:   misc.LookupEnv(EnvConfigPath),
+		Network:      misc.LookupEnv(EnvNetwork),
+		AlgodURL:     misc.LookupEnv(EnvAlgodURL),
+		AlgodToken:   misc.LookupEnv(EnvAlgodToken),
+		IndexerURL:   misc.LookupEnv(EnvIndexerURL),
+		IndexerToken: misc.LookupEnv(EnvIndexerToken),
+		WalletAddr:   misc.LookupEnv(EnvWalletAddr),
+		GoalBinary:   misc.LookupEnv(EnvGoalBinary),
+		DataDir:      misc.LookupEnv(EnvDataDir),
+	}
+}
+
+// Over returns o with every field set in top replacing its own.
+func (o Overrides) Over(top Overrides) Overrides {
+	pick := func(low, high string) string {
+		if high != "" {
+			return high
+		}
+		return low
+	}
+	return Overrides{
+		ConfigPath:   pick(o.ConfigPath, top.ConfigPath),
+		Network:      pick(o.Network, top.Network),
+		AlgodURL:     pick(o.AlgodURL, top.AlgodURL),
+		AlgodToken:   pick(o.AlgodToken, top.AlgodToken),
+		IndexerURL:   pick(o.IndexerURL, top.IndexerURL),
+		IndexerToken: pick(o.IndexerToken, top.IndexerToken),
+		WalletAddr:   pick(o.WalletAddr, top.WalletAddr),
+		GoalBinary:   pick(o.GoalBinary, top.GoalBinary),
+		DataDir:      pick(o.DataDir, top.DataDir),
+	}
+}
+
+// layer applies the network and wallet overrides to a loaded config.
+func (o Overrides) layer(cfg Config) Config {
+	if o.Network != "" {
+		cfg.Network = o.Network
+	}
+	if o.WalletAddr != "" {
+		cfg.WalletAddr = o.WalletAddr
+	}
+	return cfg
+}
+
+// patchesEndpoints reports whether any endpoint or token is overridden.
+func (o Overrides) patchesEndpoints() bool {
+	return o.AlgodURL != "" || o.AlgodToken != "" || o.IndexerURL != "" || o.IndexerToken != ""
+}
+
+// overrideURL normalises an overridden URL, which carries its own port.
+func overrideURL(u string) string {
+	u = trimSlash(u)
+	if !hasScheme(u) {
+		u = "http://" + u
+	}
+	return u
+}
+
+// patch applies the endpoint overrides to a network. URLs are taken as
+// given, including any port; the pin is kept so pointing a predefined
+// network at a node of another chain is still refused.
+func (o Overrides) patch(n NetworkInfo) NetworkInfo {
+	if o.AlgodURL != "" {
+		n.AlgodURL = overrideURL(o.AlgodURL)
+		n.AlgodPort = ""
+		n.AlgodFallbacks = nil
+	}
+	if o.AlgodToken != "" {
+		n.AlgodToken = o.AlgodToken
+	}
+	if o.IndexerURL != "" {
+		n.IndexerURL = overrideURL(o.IndexerURL)
+		n.IndexerPort = ""
+		n.IndexerFallbacks = nil
+	}
+	if o.IndexerToken != "" {
+		n.IndexerToken = o.IndexerToken
+	}
+	return n
+}
+
+// applyOverrides layers the endpoint overrides over the active network.
+// It runs again whenever network info is reloaded from config or vault.
+func (m *SettingsModel) applyOverrides() {
+	o := m.overrides
+	if !o.patchesEndpoints() {
+		return
+	}
+	name := m.config.Network
+	if o.Network != "" {
+		name = o.Network
+	}
+	// Start from the configured values, never from a patched entry
+	info, ok := m.networkInfos[name]
+	if m.shadowed.Name == name {
+		info, ok = m.shadowed, true
+	}
+	for _, n := range m.config.CustomNetworks {
+		if n.Name == name {
+			info, ok = n, true
+		}
+	}
+	if !ok {
+		info = NetworkInfo{Name: name}
+	}
+	m.shadowed = info
+	m.networkInfos[name] = o.patch(info)
+}
+
+// editableInfo returns the network as configured, without overrides, so
+// the editor never saves overridden endpoints or tokens.
+func (m *SettingsModel) editableInfo(name string) (NetworkInfo, bool) {
+	if m.overrides.patchesEndpoints() && m.shadowed.Name == name {
+		return m.shadowed, true
+	}
+	info, ok := m.networkInfos[name]
+	return info, ok
+}
+
+// persistableConfig returns the config with overridden values replaced by
+// the ones read from the file.
+func (m *SettingsModel) persistableConfig() Config {
+	cfg := m.config
+	if m.overrides.Network != "" {
+		cfg.Network = m.fileNetwork
+	}
+	if m.overrides.WalletAddr != "" {
+		cfg.WalletAddr = m.fileWallet
+	}
+	return syncActiveProfile(cfg)
+}
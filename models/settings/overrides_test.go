@@ -0,0 +1,83 @@
+package settings
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOverridesOver(t *testing.T) {
+	env := Overrides{Network: "testnet", AlgodURL: "http://env:4001", WalletAddr: "ENV"}
+	flags := Overrides{Network: "mainnet", AlgodToken: "flag-token"}
+	want := Overrides{Network: "mainnet", AlgodURL: "http://env:4001", AlgodToken: "flag-token", WalletAddr: "ENV"}
+	if got := env.Over(flags); got != want {
+		t.Errorf("Over = %+v, want %+v", got, want)
+	}
+}
+
+func TestOverridesFromEnv(t *testing.T) {
+	t.Setenv(EnvNetwork, "betanet")
+	t.Setenv(EnvAlgodToken, "secret")
+	t.Setenv(EnvIndexerURL, "")
+	o := OverridesFromEnv()
+	if o.Network != "betanet" || o.AlgodToken != "secret" || o.IndexerURL != "" {
+		t.Errorf("OverridesFromEnv = %+v", o)
+	}
+}
+
+func TestOverridesPatch(t *testing.T) {
+	base := NetworkInfo{
+		Name: "mainnet", AlgodURL: "https://mainnet-api.example", AlgodPort: "443", AlgodToken: "file-token",
+		AlgodFallbacks: []Endpoint{{URL: "https://backup.example"}},
+		IndexerURL:     "https://mainnet-idx.example", IndexerPort: "443",
+		GenesisID: "mainnet-v1.0", GenesisHash: "hash",
+	}
+	tests := []struct {
+		name string
+		o    Overrides
+		want func(n NetworkInfo) NetworkInfo
+	}{
+		{"nothing", Overrides{}, func(n NetworkInfo) NetworkInfo { return n }},
+		{
+			"algod url, port included",
+			Overrides{AlgodURL: "localhost:4001/"},
+			func(n NetworkInfo) NetworkInfo {
+				n.AlgodURL, n.AlgodPort, n.AlgodFallbacks = "http://localhost:4001", "", nil
+				return n
+			},
+		},
+		{
+			"tokens only",
+			Overrides{AlgodToken: "a", IndexerToken: "i"},
+			func(n NetworkInfo) NetworkInfo {
+				n.AlgodToken, n.IndexerToken = "a", "i"
+				return n
+			},
+		},
+		{
+			"indexer url keeps the pin",
+			Overrides{IndexerURL: "https://other.example"},
+			func(n NetworkInfo) NetworkInfo {
+				n.IndexerURL, n.IndexerPort = "https://other.example", ""
+				return n
+			},
+		},
+	}
+	for _, tt := range tests {
+		if got, want := tt.o.patch(base), tt.want(base); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: patch = %+v, want %+v", tt.name, got, want)
+		}
+	}
+}
+
+func TestOverridesLayer(t *testing.T) {
+	cfg := Config{Network: "testnet", WalletAddr: "FILE"}
+	if got := (Overrides{}).layer(cfg); got.Network != "testnet" || got.WalletAddr != "FILE" {
+		t.Errorf("empty overrides changed the config: %+v", got)
+	}
+	if got := (Overrides{Network: "mainnet", WalletAddr: "FLAG"}).layer(cfg); got.Network != "mainnet" || got.WalletAddr != "FLAG" {
+		t.Errorf("layer = %+v", got)
+	}
+	if cfg.Network != "testnet" {
+		t.Error("layer changed the loaded config")
+	}
+}
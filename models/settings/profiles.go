@@ -0,0 +1,227 @@
+package settings
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// Contact is a labelled address in a profile's address book.
+type Contact struct {
+	Label   string `json:"label"`
+	Address string `json:"address"`
+}
+
+// Profile is a named working context: which network, which account and
+// which node a session talks to.
+type Profile struct {
+	Name        string    `json:"name"`
+	Network     string    `json:"network"`
+	WalletAddr  string    `json:"wallet_addr"`
+	GoalDataDir string    `json:"goal_data_dir,omitempty"`
+	KmdWallet   string    `json:"kmd_wallet,omitempty"`
+	AddressBook []Contact `json:"address_book,omitempty"`
+
+	// How the GOAL screen executes: "goal", "native", or "" to use goal
+	// when it is installed and has a data dir.
+	Backend string `json:"backend,omitempty"`
+}
+
+// LabelFor returns the address book label of addr, if any.
+func (p Profile) LabelFor(addr string) (string, bool) {
+	for _, c := range p.AddressBook {
+		if c.Address == addr {
+			return c.Label, true
+		}
+	}
+	return "", false
+}
+
+func (c Config) profileIndex(name string) int {
+	for i, p := range c.Profiles {
+		if p.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Profiles lists the profile names in configuration order.
+func (m *SettingsModel) Profiles() []string {
+	names := make([]string, 0, len(m.config.Profiles))
+	for _, p := range m.config.Profiles {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// Profile returns a profile by name.
+func (m *SettingsModel) Profile(name string) (Profile, bool) {
+	if i := m.config.profileIndex(name); i >= 0 {
+		return m.config.Profiles[i], true
+	}
+	return Profile{}, false
+}
+
+// ActiveProfile returns the active profile; ok is false when the
+// configuration has no profiles yet.
+func (m *SettingsModel) ActiveProfile() (Profile, bool) {
+	return m.Profile(m.config.ActiveProfile)
+}
+
+// Config returns a copy of the configuration in use, overrides included.
+func (m *SettingsModel) Config() Config { return m.config }
+
+// NetworkInfo returns a configured network by name.
+func (m *SettingsModel) NetworkInfo(name string) (NetworkInfo, bool) {
+	info, ok := m.networkInfos[name]
+	return info, ok
+}
+
+// CurrentNetwork is the network selected in the configuration.
+func (m *SettingsModel) CurrentNetwork() string { return m.config.Network }
+
+// WalletAddr is the configured default account.
+func (m *SettingsModel) WalletAddr() string { return m.config.WalletAddr }
+
+// SaveProfile adds or replaces a profile and persists the config.
+func (m *SettingsModel) SaveProfile(p Profile) error {
+	p.Name = strings.TrimSpace(p.Name)
+	if p.Name == "" {
+		return fmt.Errorf("profile name is required")
+	}
+	if _, ok := m.networkInfos[p.Network]; !ok {
+		return fmt.Errorf("unknown network %q", p.Network)
+	}
+	if i := m.config.profileIndex(p.Name); i >= 0 {
+		m.config.Profiles[i] = p
+	} else {
+		m.config.Profiles = append(m.config.Profiles, p)
+	}
+	if m.config.ActiveProfile == "" {
+		m.config.ActiveProfile = p.Name
+	}
+	return m.persistConfig()
+}
+
+// DeleteProfile removes a profile other than the active one.
+func (m *SettingsModel) DeleteProfile(name string) error {
+	i := m.config.profileIndex(name)
+	if i < 0 {
+		return fmt.Errorf("profile %q not found", name)
+	}
+	if name == m.config.ActiveProfile {
+		return fmt.Errorf("cannot delete the active profile, switch first")
+	}
+	m.config.Profiles = append(m.config.Profiles[:i], m.config.Profiles[i+1:]...)
+	return m.persistConfig()
+}
+
+// SwitchProfile makes name the active profile: its network and account
+// replace the current ones and the live connection is dropped, as it
+// belongs to the previous context.
+func (m *SettingsModel) SwitchProfile(name string) (Profile, error) {
+	p, ok := m.Profile(name)
+	if !ok {
+		return Profile{}, fmt.Errorf("profile %q not found", name)
+	}
+	if _, ok := m.networkInfos[p.Network]; !ok {
+		return Profile{}, fmt.Errorf("profile %s uses unknown network %q", name, p.Network)
+	}
+
+	m.ResetEditingState()
+	m.cancelOp()
+	m.stopReconnect()
+	m.networkManager.Disconnect()
+	m.config.ActiveProfile = name
+	m.config.Network = p.Network
+	m.config.WalletAddr = p.WalletAddr
+	// An explicit switch beats the values given at startup
+	m.overrides.Network = ""
+	m.overrides.WalletAddr = ""
+	m.applyOverrides()
+	for i, n := range m.networks {
+		if n == p.Network {
+			m.cursor = i
+		}
+	}
+	m.connectionStatus = fmt.Sprintf("Switched to profile %s", name)
+	m.showStatus = true
+	return p, m.persistConfig()
+}
+
+// UpdateActiveProfile edits the active profile in place and persists it,
+// creating the "default" profile when there is none yet.
+func (m *SettingsModel) UpdateActiveProfile(edit func(*Profile)) error {
+	i := m.ensureProfile()
+	edit(&m.config.Profiles[i])
+	return m.persistConfig()
+}
+
+// ensureProfile creates a "default" profile from the current settings so
+// per-profile data always has a home.
+func (m *SettingsModel) ensureProfile() int {
+	if i := m.config.profileIndex(m.config.ActiveProfile); i >= 0 {
+		return i
+	}
+	m.config.Profiles = append(m.config.Profiles, Profile{
+		Name:       "default",
+		Network:    m.config.Network,
+		WalletAddr: m.config.WalletAddr,
+	})
+	m.config.ActiveProfile = "default"
+	return len(m.config.Profiles) - 1
+}
+
+// AddContact stores a labelled address in the active profile.
+func (m *SettingsModel) AddContact(label, addr string) error {
+	label, addr = strings.TrimSpace(label), strings.TrimSpace(addr)
+	if label == "" {
+		return fmt.Errorf("label is required")
+	}
+	if _, err := types.DecodeAddress(addr); err != nil {
+		return fmt.Errorf("invalid address: %w", err)
+	}
+	i := m.ensureProfile()
+	p := &m.config.Profiles[i]
+	for j, c := range p.AddressBook {
+		if c.Address == addr {
+			p.AddressBook[j].Label = label
+			return m.persistConfig()
+		}
+	}
+	p.AddressBook = append(p.AddressBook, Contact{Label: label, Address: addr})
+	return m.persistConfig()
+}
+
+// RemoveContact deletes an address from the active profile's book.
+func (m *SettingsModel) RemoveContact(addr string) error {
+	i := m.config.profileIndex(m.config.ActiveProfile)
+	if i < 0 {
+		return nil
+	}
+	p := &m.config.Profiles[i]
+	for j, c := range p.AddressBook {
+		if c.Address == addr {
+			p.AddressBook = append(p.AddressBook[:j], p.AddressBook[j+1:]...)
+			break
+		}
+	}
+	return m.persistConfig()
+}
+
+// syncActiveProfile mirrors network and account into the active profile,
+// so choices made in Settings stick to the profile they were made in.
+func syncActiveProfile(cfg Config) Config {
+	i := cfg.profileIndex(cfg.ActiveProfile)
+	if i < 0 {
+		return cfg
+	}
+	profiles := make([]Profile, len(cfg.Profiles))
+	copy(profiles, cfg.Profiles)
+	profiles[i].Network = cfg.Network
+	profiles[i].WalletAddr = cfg.WalletAddr
+	cfg.Profiles = profiles
+	return cfg
+}
@@ -0,0 +1,63 @@
+package settings
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func (m *SettingsModel) renderAddressBookSection() string {
+	var content []string
+
+	profile := "default"
+	if p, ok := m.ActiveProfile(); ok {
+		profile = p.Name
+	}
+	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#cba6f7")).Render("Address Book")
+	content = append(content, title, lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086")).Render("Profile: "+profile), "")
+
+	inputStyle := lipgloss.NewStyle().
+		Width(37).
+		Background(lipgloss.Color("#1e1e2e")).
+		Foreground(lipgloss.Color("#f9e2af")).
+		Padding(0, 1)
+	hint := lipgloss.NewStyle().Italic(true).Foreground(lipgloss.Color("#6c7086"))
+
+	switch m.bookMode {
+	case bookModeLabel:
+		content = append(content, "Label:", "", inputStyle.Render(m.bookInput+"_"))
+	case bookModeAddress:
+		content = append(content, "Address for "+m.bookPending+":", "", inputStyle.Render(m.bookInput+"_"))
+	default:
+		contacts := m.contacts()
+		if len(contacts) == 0 {
+			content = append(content, hint.Render("No contacts yet"))
+		}
+		for i, c := range contacts {
+			cursor := "  "
+			style := lipgloss.NewStyle()
+			if i == m.bookCursor {
+				cursor = "> "
+				style = style.Foreground(lipgloss.Color("#ef9f76"))
+			}
+			line := c.Label + "  " + c.Address[:6] + "..." + c.Address[len(c.Address)-4:]
+			content = append(content, cursor+style.Render(line))
+		}
+	}
+
+	if m.bookMsg != "" {
+		content = append(content, "", lipgloss.NewStyle().Foreground(lipgloss.Color("#f9e2af")).Width(41).Render(m.bookMsg))
+	}
+
+	for len(content) < 10 {
+		content = append(content, "")
+	}
+
+	panel := strings.Join(content, "\n")
+	return lipgloss.NewStyle().
+		Width(45).
+		Padding(2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#cba6f7")).
+		Render(panel)
+}
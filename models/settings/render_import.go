@@ -0,0 +1,138 @@
+package settings
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func (m *SettingsModel) renderImportSection() string {
+	var content []string
+
+	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#cba6f7")).Render("Import Network")
+	content = append(content, title, "")
+
+	inputStyle := lipgloss.NewStyle().
+		Width(37).
+		Background(lipgloss.Color("#1e1e2e")).
+		Foreground(lipgloss.Color("#f9e2af")).
+		Padding(0, 1)
+	hint := lipgloss.NewStyle().Italic(true).Foreground(lipgloss.Color("#6c7086"))
+	detail := lipgloss.NewStyle().Foreground(lipgloss.Color("#cdd6f4"))
+
+	switch m.importMode {
+	case importModeMenu:
+		for i, src := range importSources {
+			cursor := "  "
+			style := lipgloss.NewStyle()
+			if i == m.importCursor {
+				cursor = "> "
+				style = style.Foreground(lipgloss.Color("#ef9f76"))
+			}
+			content = append(content, cursor+style.Render(string(rune('1'+i))+") "+src))
+		}
+
+	case importModePath:
+		prompt := "Node data directory:"
+		if m.importSource == 2 {
+			prompt = "Path to .json or .toml file:"
+		}
+		content = append(content, prompt, "", inputStyle.Render(m.importInput+"_"))
+		if m.importSource == 1 {
+			content = append(content, "", hint.Render("Reads algod.net, algod.token and kmd"))
+		}
+
+	case importModeRename:
+		content = append(content, "Save as:", "", inputStyle.Render(m.importInput+"_"))
+
+	case importModeReview:
+		for i, n := range m.importFound {
+			cursor := "  "
+			style := lipgloss.NewStyle()
+			if i == m.importCursor {
+				cursor = "> "
+				style = style.Foreground(lipgloss.Color("#ef9f76"))
+			}
+			name := n.Name
+			switch {
+			case isPredefined(n.Name):
+				name += " (predefined, rename)"
+			case m.hasNetwork(n.Name):
+				name += " (replaces)"
+			}
+			content = append(content, cursor+style.Render(name))
+		}
+		if m.importCursor < len(m.importFound) {
+			n := m.importFound[m.importCursor]
+			content = append(content, "",
+				detail.Render("Algod: "+truncate(Endpoint{URL: n.AlgodURL, Port: n.AlgodPort}.BaseURL(), 33)),
+				detail.Render("Token: "+maskToken(n.AlgodToken)))
+			if n.IndexerURL != "" {
+				content = append(content, detail.Render("Indexer: "+truncate(Endpoint{URL: n.IndexerURL, Port: n.IndexerPort}.BaseURL(), 31)))
+			}
+			if n.KmdURL != "" {
+				content = append(content, detail.Render("Kmd: "+truncate(n.KmdURL, 35)))
+			}
+			if n.DataDir != "" {
+				content = append(content, detail.Render("Data dir: "+truncate(n.DataDir, 30)))
+			}
+			// Transport settings decide where requests and tokens go
+			warn := lipgloss.NewStyle().Foreground(lipgloss.Color("#f38ba8"))
+			if n.Proxy != "" {
+				content = append(content, warn.Render("Proxy: "+truncate(n.Proxy, 33)))
+			}
+			if n.InsecureSkipVerify {
+				content = append(content, warn.Render("TLS: certificates NOT verified"))
+			}
+			if n.CAFile != "" {
+				content = append(content, detail.Render("CA file: "+truncate(n.CAFile, 31)))
+			}
+			if n.TokenHeader != "" {
+				content = append(content, detail.Render("Token header: "+truncate(n.TokenHeader, 26)))
+			}
+			headers := make([]string, 0, len(n.Headers))
+			for k := range n.Headers {
+				headers = append(headers, k)
+			}
+			sort.Strings(headers)
+			for _, k := range headers {
+				content = append(content, detail.Render("Header "+truncate(k, 16)+": "+maskToken(n.Headers[k])))
+			}
+		}
+	}
+
+	if m.importMsg != "" {
+		content = append(content, "", lipgloss.NewStyle().Foreground(lipgloss.Color("#f38ba8")).Width(41).Render(m.importMsg))
+	}
+
+	for len(content) < 10 {
+		content = append(content, "")
+	}
+
+	panel := strings.Join(content, "\n")
+	return lipgloss.NewStyle().
+		Width(45).
+		Padding(2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#cba6f7")).
+		Render(panel)
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n-3] + "..."
+}
+
+func maskToken(t string) string {
+	switch {
+	case t == "":
+		return "(none)"
+	case len(t) > 16:
+		return t[:6] + "..." + t[len(t)-6:]
+	default:
+		return strings.Repeat("•", len(t))
+	}
+}
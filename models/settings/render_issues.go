@@ -0,0 +1,45 @@
+package settings
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxIssuesShown keeps the panel within the usual panel height.
+const maxIssuesShown = 6
+
+func (m *SettingsModel) renderIssuesSection() string {
+	var content []string
+
+	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#f9e2af")).Render("Config Issues")
+	content = append(content, title, "")
+
+	if len(m.configIssues) == 0 {
+		content = append(content, lipgloss.NewStyle().Foreground(lipgloss.Color("#a6e3a1")).Render("config.json looks fine"))
+	}
+	style := lipgloss.NewStyle().Width(41).Foreground(lipgloss.Color("#cdd6f4"))
+	for i, issue := range m.configIssues {
+		if i == maxIssuesShown {
+			content = append(content, fmt.Sprintf("... and %d more", len(m.configIssues)-maxIssuesShown))
+			break
+		}
+		content = append(content, style.Render("• "+issue.String()))
+	}
+
+	content = append(content, "",
+		lipgloss.NewStyle().Italic(true).Foreground(lipgloss.Color("#6c7086")).Render(fmt.Sprintf("Schema v%d · %s", ConfigVersion, ConfigPath())))
+
+	for len(content) < 10 {
+		content = append(content, "")
+	}
+
+	panel := strings.Join(content, "\n")
+	return lipgloss.NewStyle().
+		Width(45).
+		Padding(2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#f9e2af")).
+		Render(panel)
+}
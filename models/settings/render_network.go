@@ -86,6 +86,10 @@ func (m *SettingsModel) renderNetworkSection() string {
 				content = append(content, algodStyle.Render(fmt.Sprintf("Algod: %s:%s", algodURL, info.AlgodPort)))
 			}
 
+			if n := len(info.AlgodFallbacks) + len(info.IndexerFallbacks); n > 0 {
+				content = append(content, algodStyle.Render(fmt.Sprintf("Fallbacks: %d", n)))
+			}
+
 			if info.AlgodToken != "" {
 				td := info.AlgodToken
 				if len(td) > 16 {
@@ -95,6 +99,23 @@ func (m *SettingsModel) renderNetworkSection() string {
 			} else {
 				content = append(content, algodStyle.Render("Token: (none)"))
 			}
+			if info.TokenHeader != "" {
+				content = append(content, algodStyle.Render("Token header: "+info.TokenHeader))
+			}
+			if len(info.Headers) > 0 {
+				content = append(content, algodStyle.Render(fmt.Sprintf("Headers: %d custom", len(info.Headers))))
+			}
+			if info.Proxy != "" {
+				content = append(content, algodStyle.Render("Via proxy"))
+			}
+			if info.DataDir != "" {
+				content = append(content, algodStyle.Render("Goal: "+truncate(info.DataDir, 20)))
+			}
+			if info.InsecureSkipVerify {
+				content = append(content, lipgloss.NewStyle().Foreground(lipgloss.Color("#f38ba8")).Render("TLS not verified"))
+			} else if info.CAFile != "" {
+				content = append(content, algodStyle.Render("Custom CA"))
+			}
 			content = append(content, "")
 
 			// Connection status
@@ -115,6 +136,13 @@ func (m *SettingsModel) renderNetworkSection() string {
 	}
 	content = append(content, "")
 
+	// Running operation
+	if m.opKind != "" {
+		busy := lipgloss.NewStyle().Foreground(lipgloss.Color("#89b4fa"))
+		content = append(content, m.spinner.View()+busy.Render(truncate(m.opLabel, 20)))
+		content = append(content, lipgloss.NewStyle().Italic(true).Foreground(lipgloss.Color("#6c7086")).Render("ESC: cancel"))
+	}
+
 	// Status messages (wrapped)
 	if m.showStatus && m.connectionStatus != "" {
 		statusStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#f9e2af")).Italic(true)
@@ -167,17 +195,19 @@ func (m *SettingsModel) renderNetworkEditor() string {
 	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#f9e2af")).Render("Edit Network")
 	content = append(content, title, "")
 
-	fields := []struct {
-		key   string
-		label string
-	}{
-		{"name", "Name"},
-		{"algod_url", "Algod URL"},
-		{"algod_port", "Algod Port"},
-		{"algod_token", "Algod Token"},
-		{"indexer_url", "Indexer URL"},
-		{"indexer_port", "Indexer Port"},
-		{"indexer_token", "Indexer Token"},
+	// Only a window of fields fits the panel, it follows the focus
+	const visible = 7
+	start := editorFieldIndex(m.editField) - visible/2
+	if start > len(networkEditorFields)-visible {
+		start = len(networkEditorFields) - visible
+	}
+	if start < 0 {
+		start = 0
+	}
+	fields := networkEditorFields[start:min(start+visible, len(networkEditorFields))]
+	more := lipgloss.NewStyle().Foreground(lipgloss.Color("#45475a"))
+	if start > 0 {
+		content = append(content, more.Render(fmt.Sprintf("  ↑ %d more", start)))
 	}
 
 	for i, f := range fields {
@@ -210,6 +240,17 @@ func (m *SettingsModel) renderNetworkEditor() string {
 			content = append(content, "")
 		}
 	}
+	if rest := len(networkEditorFields) - start - len(fields); rest > 0 {
+		content = append(content, more.Render(fmt.Sprintf("  ↓ %d more", rest)))
+	}
+
+	content = append(content, "")
+	pinStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086"))
+	if m.networkBuffer.GenesisID != "" {
+		content = append(content, pinStyle.Render("Pinned: "+m.networkBuffer.GenesisID))
+	} else {
+		content = append(content, pinStyle.Italic(true).Render("Genesis pinned on save"))
+	}
 
 	content = append(content, "")
 	instr := lipgloss.NewStyle().Italic(true).Foreground(lipgloss.Color("#6c7086"))
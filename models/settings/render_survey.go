@@ -0,0 +1,74 @@
+package settings
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func (m *SettingsModel) renderSurveySection() string {
+	var content []string
+
+	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#89b4fa")).Render("All Networks")
+	content = append(content, title, "")
+
+	head := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#6c7086"))
+	content = append(content, head.Render(fmt.Sprintf("%-12s %7s %10s %8s", "Network", "Algod", "Round", "Indexer")))
+
+	ok := lipgloss.NewStyle().Foreground(lipgloss.Color("#a6e3a1"))
+	bad := lipgloss.NewStyle().Foreground(lipgloss.Color("#f38ba8"))
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086"))
+	var failures []string
+	for _, r := range m.survey {
+		name := truncate(r.Network, 12)
+		switch {
+		case !r.Done:
+			content = append(content, fmt.Sprintf("%-12s ", name)+m.spinner.View())
+		case r.Round == 0 && r.Err != nil:
+			content = append(content, fmt.Sprintf("%-12s ", name)+bad.Render(fmt.Sprintf("%7s", "down")))
+			failures = append(failures, name+": "+r.Err.Error())
+		default:
+			algod := fmt.Sprintf("%7s", fmt.Sprintf("%dms", r.Latency.Milliseconds()))
+			var indexer string
+			switch r.Indexer {
+			case "ok":
+				indexer = ok.Render(fmt.Sprintf("%8s", fmt.Sprintf("%dms", r.IndexerLatency.Milliseconds())))
+			case "down":
+				indexer = bad.Render(fmt.Sprintf("%8s", "down"))
+			default:
+				indexer = dim.Render(fmt.Sprintf("%8s", "-"))
+			}
+			line := fmt.Sprintf("%-12s ", name) + ok.Render(algod) + fmt.Sprintf(" %10d ", r.Round) + indexer
+			content = append(content, line)
+			if r.Err != nil {
+				// Answers, but not as the chain the network is pinned to
+				failures = append(failures, name+": "+r.Err.Error())
+			}
+		}
+	}
+
+	if len(failures) > 0 {
+		content = append(content, "")
+		errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#f9e2af"))
+		for i, f := range failures {
+			if i == 3 {
+				content = append(content, dim.Render(fmt.Sprintf("... and %d more", len(failures)-3)))
+				break
+			}
+			content = append(content, errStyle.Render(truncate(f, 41)))
+		}
+	}
+
+	for len(content) < 10 {
+		content = append(content, "")
+	}
+
+	panel := strings.Join(content, "\n")
+	return lipgloss.NewStyle().
+		Width(45).
+		Padding(2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#89b4fa")).
+		Render(panel)
+}
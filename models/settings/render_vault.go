@@ -0,0 +1,79 @@
+package settings
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func (m *SettingsModel) renderVaultSection() string {
+	var content []string
+
+	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#cba6f7")).Render("Secrets Vault")
+	content = append(content, title, "")
+
+	inputStyle := lipgloss.NewStyle().
+		Width(37).
+		Background(lipgloss.Color("#1e1e2e")).
+		Foreground(lipgloss.Color("#f9e2af")).
+		Padding(0, 1)
+	hint := lipgloss.NewStyle().Italic(true).Foreground(lipgloss.Color("#6c7086"))
+	masked := strings.Repeat("•", len([]rune(m.vaultInput))) + "_"
+
+	switch m.vaultMode {
+	case vaultModeNewPassphrase:
+		content = append(content,
+			"No vault yet. Choose a passphrase:", "",
+			inputStyle.Render(masked), "",
+			hint.Render("Tokens will be moved out of config.json"),
+			hint.Render("and encrypted with this passphrase."))
+	case vaultModeConfirm:
+		content = append(content, "Repeat the passphrase:", "", inputStyle.Render(masked))
+	case vaultModePassphrase:
+		content = append(content, "Vault is locked. Passphrase:", "", inputStyle.Render(masked))
+	case vaultModeLabel:
+		content = append(content, "Label for the account:", "", inputStyle.Render(m.vaultInput+"_"), "",
+			hint.Render("Empty uses the address prefix"))
+	case vaultModeMnemonic:
+		content = append(content, "25-word mnemonic:", "", inputStyle.Render(masked), "",
+			hint.Render("Paste the words, they are never shown"))
+	default:
+		content = append(content, lipgloss.NewStyle().Foreground(lipgloss.Color("#a6e3a1")).Render("Unlocked"), "")
+		accounts := m.vault.Accounts()
+		if len(accounts) == 0 {
+			content = append(content, hint.Render("No imported accounts"))
+		}
+		for i, a := range accounts {
+			cursor := "  "
+			style := lipgloss.NewStyle()
+			if i == m.vaultCursor {
+				cursor = "> "
+				style = style.Foreground(lipgloss.Color("#ef9f76"))
+			}
+			line := a.Label + "  " + a.Address[:6] + "..." + a.Address[len(a.Address)-4:]
+			if a.Address == m.config.WalletAddr {
+				line += " (wallet)"
+			}
+			content = append(content, cursor+style.Render(line))
+		}
+		content = append(content, "",
+			hint.Render("m: import mnemonic  d: delete"),
+			hint.Render("Enter: use as wallet  L: lock"))
+	}
+
+	if m.vaultMsg != "" {
+		content = append(content, "", lipgloss.NewStyle().Foreground(lipgloss.Color("#f9e2af")).Width(41).Render(m.vaultMsg))
+	}
+
+	for len(content) < 10 {
+		content = append(content, "")
+	}
+
+	panel := strings.Join(content, "\n")
+	return lipgloss.NewStyle().
+		Width(45).
+		Padding(2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#cba6f7")).
+		Render(panel)
+}
@@ -42,18 +42,36 @@ func (m *SettingsModel) renderWalletSection() string {
 		content = append(content, addr, "")
 
 		if m.networkManager.IsConnected() {
-			if status, err := m.networkManager.GetNetworkStatus(); err == nil {
-				content = append(content, lipgloss.NewStyle().Bold(true).Render("Network Status:"))
-				content = append(content, lipgloss.NewStyle().Foreground(lipgloss.Color("#a6e3a1")).
-					Render(fmt.Sprintf("Connected to %s", status.NetworkName)))
-				content = append(content, fmt.Sprintf("Round: %d", status.LastRound))
-
-				if status.IndexerHealthy {
-					content = append(content, lipgloss.NewStyle().Foreground(lipgloss.Color("#a6e3a1")).Render("Indexer: Online"))
-				} else if status.IndexerURL != "" {
-					content = append(content, lipgloss.NewStyle().Foreground(lipgloss.Color("#f38ba8")).Render("Indexer: Offline"))
-				}
+			// Fed by the block stream, rendering never touches the network.
+			sample := m.networkManager.LastSample()
+			current := m.networkManager.GetCurrentNetwork()
+			content = append(content, lipgloss.NewStyle().Bold(true).Render("Network Status:"))
+			content = append(content, lipgloss.NewStyle().Foreground(lipgloss.Color("#a6e3a1")).
+				Render(fmt.Sprintf("Connected to %s", current.Name)))
+			if sample.Round > 0 {
+				content = append(content, fmt.Sprintf("Round: %d", sample.Round))
+			} else {
+				content = append(content, lipgloss.NewStyle().Faint(true).Render("Round: waiting for block..."))
+			}
+			if chain := m.networkManager.Chain(); chain.GenesisID != "" {
+				content = append(content, "Genesis: "+chain.GenesisID)
+				content = append(content, lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086")).
+					Render("  "+chain.GenesisHash))
+				content = append(content, "Node: "+chain.BuildVersion)
 			}
+			if err := m.networkManager.StreamErr(); err != nil {
+				content = append(content, lipgloss.NewStyle().Foreground(lipgloss.Color("#f9e2af")).Render("Block stream: retrying"))
+			}
+
+			if m.networkManager.GetIndexerClient() != nil {
+				content = append(content, renderIndexerState(m.networkManager.IndexerHealth()))
+			} else if current.IndexerURL != "" {
+				content = append(content, lipgloss.NewStyle().Foreground(lipgloss.Color("#f38ba8")).Render("Indexer: Offline"))
+			}
+
+			content = append(content, "", lipgloss.NewStyle().Bold(true).Render("Endpoints:"))
+			content = append(content, renderEndpointHealth("algod", m.networkManager.AlgodHealth())...)
+			content = append(content, renderEndpointHealth("idx", m.networkManager.IndexerHealth())...)
 		} else {
 			content = append(content, lipgloss.NewStyle().Bold(true).Render("Network Status:"))
 			content = append(content, lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086")).Render("Not connected"))
@@ -62,6 +80,19 @@ func (m *SettingsModel) renderWalletSection() string {
 		content = append(content, "")
 		editInstr := lipgloss.NewStyle().Italic(true).Foreground(lipgloss.Color("#cdd6f4")).Render("Press 'e' to edit wallet")
 		content = append(content, editInstr)
+
+		vaultState := "Vault: not set up (v)"
+		if m.vault.IsUnlocked() {
+			vaultState = "Vault: unlocked (v)"
+		} else if m.vault.Exists() {
+			vaultState = "Vault: locked (v to unlock)"
+		}
+		content = append(content, lipgloss.NewStyle().Italic(true).Foreground(lipgloss.Color("#6c7086")).Render(vaultState))
+
+		if n := len(m.configIssues); n > 0 {
+			warn := fmt.Sprintf("⚠ %d config issue(s), press ! to review", n)
+			content = append(content, lipgloss.NewStyle().Foreground(lipgloss.Color("#f9e2af")).Render(warn))
+		}
 	}
 
 	for len(content) < 10 {
@@ -77,15 +108,103 @@ func (m *SettingsModel) renderWalletSection() string {
 		Render(panel)
 }
 
+// renderIndexerState tells from the last answers of the indexer
+// endpoints whether any of them is reachable right now.
+func renderIndexerState(health []EndpointHealth) string {
+	tested, up := 0, 0
+	for _, h := range health {
+		if h.Requests > 0 {
+			tested++
+			if h.Failures == 0 {
+				up++
+			}
+		}
+	}
+	switch {
+	case up > 0:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("#a6e3a1")).Render("Indexer: Online")
+	case tested > 0:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("#f38ba8")).Render("Indexer: Not responding")
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086")).Render("Indexer: Not checked yet (h)")
+}
+
+// renderEndpointHealth lists endpoints with a health dot, latency and
+// error counts. The endpoint currently preferred by the pool is marked.
+func renderEndpointHealth(kind string, health []EndpointHealth) []string {
+	if len(health) == 0 {
+		return nil
+	}
+	best := 0
+	for i, h := range health {
+		if h.Score() < health[best].Score() {
+			best = i
+		}
+	}
+
+	var lines []string
+	for i, h := range health {
+		dot := lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086")).Render("○")
+		switch {
+		case h.Down():
+			dot = lipgloss.NewStyle().Foreground(lipgloss.Color("#f38ba8")).Render("●")
+		case h.Failures > 0:
+			dot = lipgloss.NewStyle().Foreground(lipgloss.Color("#f9e2af")).Render("●")
+		case h.Requests > 0:
+			dot = lipgloss.NewStyle().Foreground(lipgloss.Color("#a6e3a1")).Render("●")
+		}
+
+		host := strings.TrimPrefix(strings.TrimPrefix(h.Endpoint.BaseURL(), "https://"), "http://")
+		if len(host) > 16 {
+			host = host[:13] + "..."
+		}
+		detail := "untested"
+		if h.Requests > 0 {
+			detail = fmt.Sprintf("%dms %d/%d err", h.Latency.Milliseconds(), h.Errors, h.Requests)
+		}
+		marker := " "
+		if i == best {
+			marker = ">"
+		}
+		lines = append(lines, fmt.Sprintf("%s%s %-5s %-16s %s", marker, dot, kind, host,
+			lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086")).Render(detail)))
+	}
+	return lines
+}
+
 func (m *SettingsModel) renderFooter() string {
 	var instructions []string
 
 	if m.editingNetwork {
-		instructions = []string{"Tab: Next field", "Shift+Tab: Prev field", "Enter: Save network", "ESC: Cancel"}
+		instructions = []string{"Tab: Next field", "Shift+Tab: Prev field", "Enter: Save network", "Ctrl+U: Unpin genesis", "ESC: Cancel"}
 	} else if m.editingAddr {
 		instructions = []string{"Enter: Save address", "ESC: Cancel editing"}
+	} else if m.vaultMode != "" && m.vaultMode != vaultModeList {
+		instructions = []string{"Enter: Confirm", "ESC: Cancel"}
+	} else if m.importMode == importModeMenu {
+		instructions = []string{"Up/Down or 1-3: Choose source", "Enter: Read", "ESC: Close"}
+	} else if m.importMode == importModeReview {
+		instructions = []string{"Up/Down: Select", "r: Rename", "Enter: Import all", "ESC: Back"}
+	} else if m.importMode != "" {
+		instructions = []string{"Enter: Confirm", "ESC: Back"}
+	} else if m.showIssues {
+		instructions = []string{"x: Dismiss issues", "ESC: Close"}
+	} else if m.bookMode == bookModeList {
+		instructions = []string{"Up/Down: Select", "a: Add contact", "d: Delete", "ESC: Close"}
+	} else if m.bookMode != "" {
+		instructions = []string{"Enter: Confirm", "ESC: Cancel"}
+	} else if m.surveyOpen {
+		instructions = []string{"r: Re-run", "ESC: Close"}
+	} else if m.opKind != "" {
+		instructions = []string{"Up/Down: Navigate", "ESC: Cancel " + m.opKind}
+	} else if m.confirmAction != "" {
+		instructions = []string{"y: Confirm " + m.confirmAction, "n/ESC: Cancel"}
+	} else if m.vaultRemove != "" {
+		instructions = []string{"y: Confirm remove", "n/ESC: Cancel"}
+	} else if m.vaultMode == vaultModeList {
+		instructions = []string{"Up/Down: Select account", "m: Import", "d: Delete", "Enter: Use as wallet", "L: Lock", "ESC: Close vault"}
 	} else {
-		instructions = []string{"Up/Down: Navigate", "Enter: Connect to network", "t: Test connection", "e: Edit wallet", "n: Edit network", "c: Create network", "ESC: Back"}
+		instructions = []string{"Up/Down: Navigate", "Enter: Connect to network", "t: Test connection", "T: Test all", "h: Probe endpoints", "e: Edit wallet", "v: Vault", "b: Address book", "n: Edit network", "c: Create network", "d: Delete network", "r: Reset network", "Shift+Up/Down: Reorder", "i: Import network", "ESC: Back"}
 		if m.showStatus {
 			instructions = append(instructions, "Space: Hide status")
 		}
@@ -96,6 +215,12 @@ func (m *SettingsModel) renderFooter() string {
 		modeIndicator = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#f9e2af")).Render("[EDITING NETWORK] ")
 	} else if m.editingAddr {
 		modeIndicator = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#f9e2af")).Render("[EDITING WALLET] ")
+	} else if m.importMode != "" {
+		modeIndicator = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#cba6f7")).Render("[IMPORT] ")
+	} else if m.bookMode != "" {
+		modeIndicator = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#cba6f7")).Render("[ADDRESS BOOK] ")
+	} else if m.vaultMode != "" {
+		modeIndicator = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#cba6f7")).Render("[VAULT] ")
 	}
 
 	instructionText := strings.Join(instructions, " | ")
@@ -1,8 +1,10 @@
 package settings
 
 import (
-	"strings"
+	"context"
+	"fmt"
 
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -19,48 +21,82 @@ type SettingsModel struct {
 
 	// Network editing state
 	editingNetwork bool
-	editField      string      // Which field is being edited (url, port, token)
-	networkBuffer  NetworkInfo // Buffer for network editing
+	editField      string            // Which field is being edited (url, port, token)
+	networkBuffer  NetworkInfo       // Buffer for network editing
+	rawFields      map[string]string // Raw text of list fields while typing
+	editingName    string            // Name of the network being edited, "" when creating
+	confirmAction  string            // Pending delete/reset of confirmTarget
+	confirmTarget  string
+
+	// Background network operations, see network_ops.go
+	spinner       spinner.Model
+	opID          int
+	opKind        string // "" when idle
+	opLabel       string
+	opCancel      context.CancelFunc
+	survey        []SurveyResult
+	surveyOpen    bool
+	surveyPending int
+	surveyID      int
+	surveyCancel  context.CancelFunc
+
+	// Automatic reconnect schedule, see network_link.go
+	retryID      int
+	retryPending bool
+	retryAttempt int
+	retryErr     error
 
 	// Network management
 	networkManager   *NetworkManager
 	connectionStatus string // Status message for network connections
 	showStatus       bool   // Whether to show connection status
+
+	// Secrets vault
+	vault        *Vault
+	vaultMode    string // "" when the vault panel is closed
+	vaultInput   string
+	vaultPending string // first passphrase or label awaiting the next step
+	vaultCursor  int
+	vaultMsg     string
+	vaultRemove  string // address whose removal awaits y/n
+
+	// Address book of the active profile
+	bookMode    string // "" when the panel is closed
+	bookInput   string
+	bookPending string // label awaiting its address
+	bookCursor  int
+	bookMsg     string
+
+	// Network import panel
+	importMode   string // "" when the panel is closed
+	importSource int    // index in importSources
+	importCursor int
+	importInput  string
+	importFound  []NetworkInfo
+	importMsg    string
+	// importReplace lists the networks an import overwrites, awaiting y/n
+	importReplace []string
+
+	// Problems found in config.json, shown until dismissed
+	configIssues []ConfigIssue
+	showIssues   bool
+
+	// Runtime overrides from flags and environment
+	overrides   Overrides
+	shadowed    NetworkInfo // overridden network as configured
+	fileNetwork string
+	fileWallet  string
 }
 
-func NewSettingsModel(networks []string) *SettingsModel {
-	cfg, _ := LoadConfig()
-
-	// Predefined networks
-	networkInfos := map[string]NetworkInfo{
-		"localnet": {
-			Name:         "localnet",
-			AlgodURL:     "http://localhost",
-			AlgodPort:    "4001",
-			AlgodToken:   strings.Repeat("a", 64),
-			IndexerURL:   "http://localhost",
-			IndexerPort:  "8980",
-			IndexerToken: strings.Repeat("a", 64),
-		},
-		"testnet": {
-			Name:         "testnet",
-			AlgodURL:     "https://testnet-api.4160.nodely.dev",
-			AlgodPort:    "443",
-			AlgodToken:   "",
-			IndexerURL:   "https://testnet-idx.4160.nodely.dev",
-			IndexerPort:  "443",
-			IndexerToken: "",
-		},
-		"mainnet": {
-			Name:         "mainnet",
-			AlgodURL:     "https://mainnet-api.4160.nodely.dev",
-			AlgodPort:    "443",
-			AlgodToken:   "",
-			IndexerURL:   "https://mainnet-idx.4160.nodely.dev",
-			IndexerPort:  "443",
-			IndexerToken: "",
-		},
+func NewSettingsModel(networks []string, overrides Overrides) *SettingsModel {
+	cfg, issues, err := LoadConfig()
+	if err != nil {
+		issues = append(issues, ConfigIssue{Message: fmt.Sprintf("failed to read config, using defaults: %v", err)})
 	}
+	fileNetwork, fileWallet := cfg.Network, cfg.WalletAddr
+	cfg = overrides.layer(cfg)
+
+	networkInfos := predefinedNetworks()
 
 	// Copy networks slice
 	availableNetworks := make([]string, len(networks))
@@ -82,6 +118,15 @@ func NewSettingsModel(networks []string) *SettingsModel {
 		}
 	}
 
+	// A network named only on the command line still needs an entry
+	if overrides.Network != "" && overrides.patchesEndpoints() {
+		if _, ok := networkInfos[overrides.Network]; !ok {
+			availableNetworks = append(availableNetworks, overrides.Network)
+		}
+	}
+
+	availableNetworks = applyNetworkOrder(availableNetworks, cfg.NetworkOrder)
+
 	// Cursor on current network
 	cursor := 0
 	for i, n := range availableNetworks {
@@ -91,7 +136,7 @@ func NewSettingsModel(networks []string) *SettingsModel {
 		}
 	}
 
-	return &SettingsModel{
+	m := &SettingsModel{
 		config:           cfg,
 		networks:         availableNetworks,
 		networkInfos:     networkInfos,
@@ -104,7 +149,15 @@ func NewSettingsModel(networks []string) *SettingsModel {
 		networkManager:   NewNetworkManager(),
 		connectionStatus: "",
 		showStatus:       false,
+		vault:            OpenVault(),
+		overrides:        overrides,
+		fileNetwork:      fileNetwork,
+		fileWallet:       fileWallet,
+		configIssues:     issues,
+		spinner:          newOpSpinner(),
 	}
+	m.applyOverrides()
+	return m
 }
 
 func (m *SettingsModel) Init() tea.Cmd { return nil }
@@ -113,6 +166,21 @@ func (m *SettingsModel) Init() tea.Cmd { return nil }
 func (m *SettingsModel) View() string {
 	leftColumn := m.renderNetworkSection()
 	rightColumn := m.renderWalletSection()
+	if m.showIssues {
+		rightColumn = m.renderIssuesSection()
+	}
+	if m.bookMode != "" {
+		rightColumn = m.renderAddressBookSection()
+	}
+	if m.importMode != "" {
+		rightColumn = m.renderImportSection()
+	}
+	if m.surveyOpen {
+		rightColumn = m.renderSurveySection()
+	}
+	if m.vaultMode != "" {
+		rightColumn = m.renderVaultSection()
+	}
 
 	mainContent := lipgloss.JoinHorizontal(
 		lipgloss.Top,
@@ -133,7 +201,9 @@ func (m *SettingsModel) View() string {
 // Public helpers used by other components.
 
 func (m *SettingsModel) GetNetworkManager() *NetworkManager { return m.networkManager }
-func (m *SettingsModel) IsEditingAddr() bool                { return m.editingAddr || m.editingNetwork }
+func (m *SettingsModel) IsEditingAddr() bool {
+	return m.editingAddr || m.editingNetwork || m.vaultMode != "" || m.bookMode != "" || m.importMode != "" || m.showIssues || m.confirmAction != "" || m.surveyOpen || m.opKind != ""
+}
 
 // ResetEditingState ensures the settings model is not in editing mode.
 func (m *SettingsModel) ResetEditingState() {
@@ -141,8 +211,24 @@ func (m *SettingsModel) ResetEditingState() {
 	m.inputBuffer = ""
 	m.editingNetwork = false
 	m.networkBuffer = NetworkInfo{}
+	m.rawFields = nil
+	m.editingName = ""
+	m.confirmAction = ""
+	m.confirmTarget = ""
+	m.closeSurvey()
 	m.showStatus = false
 	m.connectionStatus = ""
+	m.vaultMode = ""
+	m.vaultInput = ""
+	m.vaultPending = ""
+	m.vaultRemove = ""
+	m.bookMode = ""
+	m.bookInput = ""
+	m.bookPending = ""
+	m.showIssues = false
+	m.importMode = ""
+	m.importFound = nil
+	m.importReplace = nil
 }
 
 // refreshNetworkInfo ensures network info is up-to-date from config.
@@ -157,38 +243,8 @@ func (m *SettingsModel) refreshNetworkInfo(networkName string) {
 
 	// Re-add predefined if missing
 	if _, exists := m.networkInfos[networkName]; !exists {
-		switch networkName {
-		case "localnet":
-			m.networkInfos[networkName] = NetworkInfo{
-				Name:         "localnet",
-				AlgodURL:     "http://localhost",
-				AlgodPort:    "4001",
-				AlgodToken:   strings.Repeat("a", 64),
-				IndexerURL:   "http://localhost",
-				IndexerPort:  "8980",
-				IndexerToken: strings.Repeat("a", 64),
-			}
-		case "testnet":
-			// Mirrors original file's fallback (nodely).
-			m.networkInfos[networkName] = NetworkInfo{
-				Name:         "testnet",
-				AlgodURL:     "https://testnet-api.4160.nodely.dev",
-				AlgodPort:    "443",
-				AlgodToken:   "",
-				IndexerURL:   "https://testnet-idx.4160.nodely.dev",
-				IndexerPort:  "443",
-				IndexerToken: "",
-			}
-		case "mainnet":
-			m.networkInfos[networkName] = NetworkInfo{
-				Name:         "mainnet",
-				AlgodURL:     "https://mainnet-api.4160.nodely.dev",
-				AlgodPort:    "443",
-				AlgodToken:   "",
-				IndexerURL:   "https://mainnet-idx.4160.nodely.dev",
-				IndexerPort:  "443",
-				IndexerToken: "",
-			}
+		if info, ok := predefinedNetworks()[networkName]; ok {
+			m.networkInfos[networkName] = info
 		}
 	}
 }
@@ -3,12 +3,38 @@ package settings
 import (
 	"fmt"
 
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 // Update handles key input, editing flows, and connection actions.
 func (m *SettingsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case BlockMsg:
+		return m, m.handleBlockMsg(msg)
+
+	case EndpointsProbedMsg:
+		// Health table is read at render time, nothing to store
+		return m, nil
+
+	case NetworkOpMsg:
+		return m, m.handleNetworkOp(msg)
+
+	case ReconnectMsg:
+		return m, m.handleReconnect(msg)
+
+	case NetworkSurveyMsg:
+		m.handleSurveyResult(msg)
+		return m, nil
+
+	case spinner.TickMsg:
+		if !m.Busy() {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
 	case tea.KeyMsg:
 		// 1) Network editing has highest priority
 		if m.editingNetwork {
@@ -20,8 +46,12 @@ func (m *SettingsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			switch msg.String() {
 			case "enter":
 				m.config.WalletAddr = m.inputBuffer
+				m.overrides.WalletAddr = "" // explicit choice, persist it
 				m.editingAddr = false
-				SaveConfig(m.config)
+				if err := m.persistConfig(); err != nil {
+					m.connectionStatus = fmt.Sprintf("Failed to save config: %v", err)
+					m.showStatus = true
+				}
 				return m, nil
 			case "esc":
 				m.editingAddr = false
@@ -40,7 +70,55 @@ func (m *SettingsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
-		// 3) Normal mode
+		// 3) Vault panel
+		if m.vaultMode != "" {
+			return m.handleVault(msg)
+		}
+
+		// 4) Address book panel
+		if m.bookMode != "" {
+			return m.handleAddressBook(msg)
+		}
+
+		// 5) Import panel
+		if m.importMode != "" {
+			return m.handleImport(msg)
+		}
+
+		// 6) Config issues panel
+		if m.showIssues {
+			switch msg.String() {
+			case "esc", "!":
+				m.showIssues = false
+				return m, nil
+			case "x":
+				// Dismiss, they come back on the next load if unfixed
+				m.configIssues = nil
+				m.showIssues = false
+				return m, nil
+			}
+		}
+
+		// 7) Pending delete/reset confirmation
+		if m.confirmAction != "" {
+			return m.handleConfirm(msg)
+		}
+
+		// 8) Survey table
+		if m.surveyOpen {
+			return m.handleSurvey(msg)
+		}
+
+		// 9) A running test/connect/save is cancelled with esc
+		if m.opKind != "" && msg.String() == "esc" {
+			m.cancelOp()
+			m.stopReconnect()
+			m.connectionStatus = "Cancelled"
+			m.showStatus = true
+			return m, nil
+		}
+
+		// 10) Normal mode
 		switch msg.String() {
 		case "up":
 			if m.cursor > 0 {
@@ -54,40 +132,52 @@ func (m *SettingsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.showStatus = false
 				m.connectionStatus = ""
 			}
+		case "shift+up", "K":
+			m.moveNetwork(-1)
+		case "shift+down", "J":
+			m.moveNetwork(1)
+		case "d":
+			// Delete selected custom network
+			m.askConfirm(confirmDelete)
+		case "r":
+			// Reset selected predefined network
+			m.askConfirm(confirmReset)
 		case "enter":
-			// Connect to selected network
+			// Connect to selected network, in the background
 			selectedNetwork := m.networks[m.cursor]
 			if networkInfo, exists := m.networkInfos[selectedNetwork]; exists {
-				if err := m.networkManager.TestNetworkConnection(networkInfo); err != nil {
-					m.connectionStatus = fmt.Sprintf("Failed to connect to %s: %v", selectedNetwork, err)
-					m.showStatus = true
-				} else {
-					m.config.Network = selectedNetwork
-					SaveConfig(m.config)
-					if err := m.networkManager.ConnectToNetwork(networkInfo); err != nil {
-						m.connectionStatus = fmt.Sprintf("Connection failed: %v", err)
-					} else {
-						m.connectionStatus = fmt.Sprintf("Successfully connected to %s", selectedNetwork)
-					}
-					m.showStatus = true
-				}
+				return m, m.connectCmd(networkInfo)
 			}
 		case "t":
 			// Test selected network
 			selectedNetwork := m.networks[m.cursor]
 			if networkInfo, exists := m.networkInfos[selectedNetwork]; exists {
-				if err := m.networkManager.TestNetworkConnection(networkInfo); err != nil {
-					m.connectionStatus = fmt.Sprintf("Test failed for %s: %v", selectedNetwork, err)
-				} else {
-					m.connectionStatus = fmt.Sprintf("Test successful for %s", selectedNetwork)
-				}
-				m.showStatus = true
+				return m, m.testNetworkCmd(networkInfo)
+			}
+		case "T":
+			// Test all networks side by side
+			return m, m.startSurvey()
+		case "h":
+			// Re-probe endpoint health of the connected network
+			if m.networkManager.IsConnected() {
+				return m, m.networkManager.ProbeEndpoints()
 			}
 		case " ":
 			if m.showStatus {
 				m.showStatus = false
 				m.connectionStatus = ""
 			}
+		case "v":
+			// Open the secrets vault
+			m.openVaultPanel()
+		case "b":
+			// Address book of the active profile
+			m.openAddressBook()
+		case "!":
+			m.showIssues = true
+		case "i":
+			// Import network definitions
+			m.openImportPanel()
 		case "e":
 			// Start editing wallet
 			m.editingAddr = true
@@ -95,9 +185,10 @@ func (m *SettingsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "n":
 			// Edit selected network
 			currentNetworkName := m.networks[m.cursor]
-			if info, exists := m.networkInfos[currentNetworkName]; exists {
+			if info, exists := m.editableInfo(currentNetworkName); exists {
 				m.editingNetwork = true
 				m.networkBuffer = info
+				m.editingName = currentNetworkName
 				m.editField = "algod_url"
 				m.showStatus = false
 				m.connectionStatus = ""
@@ -105,6 +196,7 @@ func (m *SettingsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "c":
 			// Create new custom network
 			m.editingNetwork = true
+			m.editingName = ""
 			m.networkBuffer = NetworkInfo{
 				Name:         "custom",
 				AlgodURL:     "http://localhost",
@@ -129,17 +221,40 @@ func (m *SettingsModel) handleNetworkEditing(msg tea.KeyMsg) (tea.Model, tea.Cmd
 	case "shift+tab":
 		m.editField = m.getPrevField(m.editField)
 	case "enter":
-		m.saveNetworkConfig()
+		cmd := m.saveNetworkConfig()
 		m.editingNetwork = false
-		return m, nil
+		return m, cmd
 	case "esc":
 		m.editingNetwork = false
 		m.networkBuffer = NetworkInfo{}
+		m.rawFields = nil
+		m.editingName = ""
+		return m, nil
+	case " ":
+		if m.editField == "insecure_tls" {
+			m.networkBuffer.InsecureSkipVerify = !m.networkBuffer.InsecureSkipVerify
+			return m, nil
+		}
+	case "ctrl+u":
+		// Unpin the genesis so the network can be pointed at another chain
+		m.networkBuffer.GenesisID = ""
+		m.networkBuffer.GenesisHash = ""
+		return m, nil
+	}
+
+	if m.editField == "insecure_tls" {
+		// Boolean field: y/n or space
+		switch msg.String() {
+		case "y":
+			m.networkBuffer.InsecureSkipVerify = true
+		case "n":
+			m.networkBuffer.InsecureSkipVerify = false
+		}
 		return m, nil
 	}
 
 	switch msg.Type {
-	case tea.KeyRunes:
+	case tea.KeyRunes, tea.KeySpace:
 		m.setNetworkField(m.editField, m.getNetworkField(m.editField)+string(msg.Runes))
 	case tea.KeyBackspace:
 		current := m.getNetworkField(m.editField)
@@ -150,30 +265,50 @@ func (m *SettingsModel) handleNetworkEditing(msg tea.KeyMsg) (tea.Model, tea.Cmd
 	return m, nil
 }
 
-func (m *SettingsModel) getNextField(current string) string {
-	fields := []string{"name", "algod_url", "algod_port", "algod_token", "indexer_url", "indexer_port", "indexer_token"}
-	for i, f := range fields {
-		if f == current {
-			if i < len(fields)-1 {
-				return fields[i+1]
-			}
-			return fields[0]
+// networkEditorFields are the fields of the network editor, in tab order.
+var networkEditorFields = []struct {
+	key   string
+	label string
+}{
+	{"name", "Name"},
+	{"algod_url", "Algod URL"},
+	{"algod_port", "Algod Port"},
+	{"algod_token", "Algod Token"},
+	{"algod_fallbacks", "Algod Fallbacks (url|token|headers, ...)"},
+	{"indexer_url", "Indexer URL"},
+	{"indexer_port", "Indexer Port"},
+	{"indexer_token", "Indexer Token"},
+	{"indexer_fallbacks", "Indexer Fallbacks (url|token|headers, ...)"},
+	{"token_header", "Token Header"},
+	{"headers", "Primary Headers (Name: value; ...)"},
+	{"proxy", "Proxy URL"},
+	{"ca_file", "CA File"},
+	{"insecure_tls", "Skip TLS Verify (y/n)"},
+	{"data_dir", "Goal Data Dir (-d)"},
+	{"kmd_dir", "Kmd Dir (-k)"},
+	{"kmd_wallet", "Kmd Wallet (-w)"},
+}
+
+func editorFieldIndex(key string) int {
+	for i, f := range networkEditorFields {
+		if f.key == key {
+			return i
 		}
 	}
-	return fields[0]
+	return -1
+}
+
+func (m *SettingsModel) getNextField(current string) string {
+	i := editorFieldIndex(current)
+	return networkEditorFields[(i+1)%len(networkEditorFields)].key
 }
 
 func (m *SettingsModel) getPrevField(current string) string {
-	fields := []string{"name", "algod_url", "algod_port", "algod_token", "indexer_url", "indexer_port", "indexer_token"}
-	for i, f := range fields {
-		if f == current {
-			if i > 0 {
-				return fields[i-1]
-			}
-			return fields[len(fields)-1]
-		}
+	i := editorFieldIndex(current)
+	if i <= 0 {
+		return networkEditorFields[len(networkEditorFields)-1].key
 	}
-	return fields[len(fields)-1]
+	return networkEditorFields[i-1].key
 }
 
 func (m *SettingsModel) getNetworkField(field string) string {
@@ -192,6 +327,28 @@ func (m *SettingsModel) getNetworkField(field string) string {
 		return m.networkBuffer.IndexerPort
 	case "indexer_token":
 		return m.networkBuffer.IndexerToken
+	case "algod_fallbacks", "indexer_fallbacks", "headers":
+		if m.rawFields != nil {
+			return m.rawFields[field]
+		}
+		return m.formatRawField(field)
+	case "token_header":
+		return m.networkBuffer.TokenHeader
+	case "proxy":
+		return m.networkBuffer.Proxy
+	case "ca_file":
+		return m.networkBuffer.CAFile
+	case "data_dir":
+		return m.networkBuffer.DataDir
+	case "kmd_dir":
+		return m.networkBuffer.KmdDir
+	case "kmd_wallet":
+		return m.networkBuffer.KmdWallet
+	case "insecure_tls":
+		if m.networkBuffer.InsecureSkipVerify {
+			return "yes"
+		}
+		return "no"
 	default:
 		return ""
 	}
@@ -213,32 +370,113 @@ func (m *SettingsModel) setNetworkField(field, value string) {
 		m.networkBuffer.IndexerPort = value
 	case "indexer_token":
 		m.networkBuffer.IndexerToken = value
+	case "token_header":
+		m.networkBuffer.TokenHeader = value
+	case "proxy":
+		m.networkBuffer.Proxy = value
+	case "ca_file":
+		m.networkBuffer.CAFile = value
+	case "data_dir":
+		m.networkBuffer.DataDir = value
+	case "kmd_dir":
+		m.networkBuffer.KmdDir = value
+	case "kmd_wallet":
+		m.networkBuffer.KmdWallet = value
+	case "algod_fallbacks", "indexer_fallbacks", "headers":
+		// Kept as raw text while typing, parsed on save
+		if m.rawFields == nil {
+			m.rawFields = map[string]string{}
+			for _, f := range []string{"algod_fallbacks", "indexer_fallbacks", "headers"} {
+				m.rawFields[f] = m.formatRawField(f)
+			}
+		}
+		m.rawFields[field] = value
 	}
 }
 
-func (m *SettingsModel) saveNetworkConfig() {
+func (m *SettingsModel) formatRawField(field string) string {
+	switch field {
+	case "algod_fallbacks":
+		return FormatEndpoints(m.networkBuffer.AlgodFallbacks)
+	case "indexer_fallbacks":
+		return FormatEndpoints(m.networkBuffer.IndexerFallbacks)
+	case "headers":
+		return FormatHeaders(m.networkBuffer.Headers)
+	}
+	return ""
+}
+
+func (m *SettingsModel) saveNetworkConfig() tea.Cmd {
+	if m.rawFields != nil {
+		headers, err := ParseHeaders(m.rawFields["headers"])
+		if err != nil {
+			m.connectionStatus = fmt.Sprintf("Validation failed: %v", err)
+			m.showStatus = true
+			return nil
+		}
+		algodFallbacks, err := ParseEndpoints(m.rawFields["algod_fallbacks"], m.networkBuffer.AlgodFallbacks)
+		if err != nil {
+			m.connectionStatus = fmt.Sprintf("Validation failed: algod fallbacks: %v", err)
+			m.showStatus = true
+			return nil
+		}
+		indexerFallbacks, err := ParseEndpoints(m.rawFields["indexer_fallbacks"], m.networkBuffer.IndexerFallbacks)
+		if err != nil {
+			m.connectionStatus = fmt.Sprintf("Validation failed: indexer fallbacks: %v", err)
+			m.showStatus = true
+			return nil
+		}
+		m.networkBuffer.Headers = headers
+		m.networkBuffer.AlgodFallbacks = algodFallbacks
+		m.networkBuffer.IndexerFallbacks = indexerFallbacks
+		m.rawFields = nil
+	}
+
 	// Validate
 	if err := ValidateNetworkConfig(m.networkBuffer); err != nil {
 		m.connectionStatus = fmt.Sprintf("Validation failed: %v", err)
 		m.showStatus = true
-		return
+		return nil
 	}
-
-	// Test before saving
-	if err := m.networkManager.TestNetworkConnection(m.networkBuffer); err != nil {
-		m.connectionStatus = fmt.Sprintf("Connection test failed: %v", err)
+	if err := m.checkRename(m.editingName, m.networkBuffer.Name); err != nil {
+		m.connectionStatus = fmt.Sprintf("Validation failed: %v", err)
 		m.showStatus = true
-		return
+		return nil
+	}
+
+	// Test before saving, the network is stored when the test passes
+	from := m.editingName
+	m.editingName = ""
+	return m.saveCmd(m.networkBuffer, from)
+}
+
+// storeNetwork saves a tested network, renaming it when its name changed
+// in the editor.
+func (m *SettingsModel) storeNetwork(info NetworkInfo, from string, chain ChainIdentity) {
+	// Pin the network to the chain it was configured against
+	if info.GenesisHash == "" {
+		info = chain.Pin(info)
+	}
+
+	// A rename moves the entry instead of adding a second one; the vault
+	// may have been locked while the network was being tested
+	if from != "" && from != info.Name {
+		if err := m.checkVaultUnlocked(from); err != nil {
+			m.connectionStatus = fmt.Sprintf("%s not renamed: %v", from, err)
+			m.showStatus = true
+			return
+		}
+		m.renameNetwork(from, info.Name)
 	}
 
 	// Update map immediately for UI
-	m.networkInfos[m.networkBuffer.Name] = m.networkBuffer
+	m.networkInfos[info.Name] = info
 
 	// Detect new vs existing
 	isNew := true
 	existingIndex := -1
 	for i, n := range m.networks {
-		if n == m.networkBuffer.Name {
+		if n == info.Name {
 			isNew = false
 			existingIndex = i
 			break
@@ -248,42 +486,47 @@ func (m *SettingsModel) saveNetworkConfig() {
 	// Upsert into CustomNetworks
 	found := false
 	for i, net := range m.config.CustomNetworks {
-		if net.Name == m.networkBuffer.Name {
-			m.config.CustomNetworks[i] = m.networkBuffer
+		if net.Name == info.Name {
+			m.config.CustomNetworks[i] = info
 			found = true
 			break
 		}
 	}
 	if !found {
-		m.config.CustomNetworks = append(m.config.CustomNetworks, m.networkBuffer)
+		m.config.CustomNetworks = append(m.config.CustomNetworks, info)
 	}
 
 	// Update networks list + cursor
 	if isNew {
-		m.networks = append(m.networks, m.networkBuffer.Name)
+		m.networks = append(m.networks, info.Name)
 		m.cursor = len(m.networks) - 1
 	} else {
 		m.cursor = existingIndex
 	}
 
 	// Save and reload
-	if err := SaveConfig(m.config); err != nil {
+	if err := m.persistConfig(); err != nil {
 		m.connectionStatus = fmt.Sprintf("Failed to save config: %v", err)
 		m.showStatus = true
 		return
 	}
 
-	reloaded, err := LoadConfig()
+	reloaded, issues, err := LoadConfig()
+	m.configIssues = issues
 	if err != nil {
 		m.connectionStatus = fmt.Sprintf("Warning: Failed to reload config: %v", err)
 		m.showStatus = true
 	} else {
-		m.config = reloaded
+		m.fileNetwork, m.fileWallet = reloaded.Network, reloaded.WalletAddr
+		m.config = m.overrides.layer(reloaded)
 		for _, customNet := range m.config.CustomNetworks {
 			m.networkInfos[customNet.Name] = customNet
 		}
+		// Tokens are not in the file when the vault is enabled
+		m.applyVaultSecrets()
+		m.applyOverrides()
 	}
 
-	m.connectionStatus = fmt.Sprintf("Network %s saved successfully", m.networkBuffer.Name)
+	m.connectionStatus = fmt.Sprintf("Network %s saved successfully", info.Name)
 	m.showStatus = true
 }
@@ -0,0 +1,143 @@
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Template is a builder's field values saved under a name, e.g. "weekly
+// payroll to X". Values may hold {{placeholders}}, filled in when the
+// template is loaded.
+type Template struct {
+	Name string `json:"name"`
+	// Builder is the title of the builder the values belong to
+	Builder string            `json:"builder"`
+	Values  map[string]string `json:"values"`
+}
+
+// BuilderStore holds the builder templates and the drafts left unsent,
+// in templates.json next to the config.
+type BuilderStore struct {
+	Templates []Template `json:"templates,omitempty"`
+	// Drafts are the last unsent values, by builder title
+	Drafts map[string]map[string]string `json:"drafts,omitempty"`
+}
+
+func TemplatesPath() string {
+	return filepath.Join(filepath.Dir(ConfigPath()), "templates.json")
+}
+
+// LoadBuilderStore reads the templates and drafts; a missing file is an
+// empty store.
+func LoadBuilderStore() (BuilderStore, error) {
+	var s BuilderStore
+	buff, err := os.ReadFile(TemplatesPath())
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(buff, &s); err != nil {
+		return BuilderStore{}, fmt.Errorf("templates.json is not valid JSON: %w", err)
+	}
+	return s, nil
+}
+
+// SaveBuilderStore writes the store owner-only: drafts may hold notes and
+// addresses the user has not sent yet.
+func SaveBuilderStore(s BuilderStore) error {
+	buff, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal templates: %w", err)
+	}
+	if err := writePrivateFile(TemplatesPath(), buff); err != nil {
+		return fmt.Errorf("failed to write templates: %w", err)
+	}
+	return nil
+}
+
+// PutTemplate adds t, replacing the template with the same name.
+func (s *BuilderStore) PutTemplate(t Template) error {
+	t.Name = strings.TrimSpace(t.Name)
+	if t.Name == "" {
+		return fmt.Errorf("template name cannot be empty")
+	}
+	for i := range s.Templates {
+		if s.Templates[i].Name == t.Name {
+			s.Templates[i] = t
+			return nil
+		}
+	}
+	s.Templates = append(s.Templates, t)
+	sort.SliceStable(s.Templates, func(i, j int) bool {
+		return strings.ToLower(s.Templates[i].Name) < strings.ToLower(s.Templates[j].Name)
+	})
+	return nil
+}
+
+// DeleteTemplate removes the template name, if any.
+func (s *BuilderStore) DeleteTemplate(name string) {
+	for i := range s.Templates {
+		if s.Templates[i].Name == name {
+			s.Templates = append(s.Templates[:i], s.Templates[i+1:]...)
+			return
+		}
+	}
+}
+
+// SetDraft records the unsent values of builder; empty values drop it.
+func (s *BuilderStore) SetDraft(builder string, values map[string]string) {
+	if len(values) == 0 {
+		delete(s.Drafts, builder)
+		return
+	}
+	if s.Drafts == nil {
+		s.Drafts = map[string]map[string]string{}
+	}
+	s.Drafts[builder] = values
+}
+
+var placeholder = regexp.MustCompile(`\{\{\s*([^{}]+?)\s*\}\}`)
+
+// Placeholders lists the {{names}} used in the template's values, each
+// once.
+func (t Template) Placeholders() []string {
+	keys := make([]string, 0, len(t.Values))
+	for k := range t.Values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var names []string
+	seen := map[string]bool{}
+	for _, k := range keys {
+		for _, m := range placeholder.FindAllStringSubmatch(t.Values[k], -1) {
+			if !seen[m[1]] {
+				seen[m[1]] = true
+				names = append(names, m[1])
+			}
+		}
+	}
+	return names
+}
+
+// Fill returns the values with each {{name}} replaced from fills.
+func (t Template) Fill(fills map[string]string) map[string]string {
+	out := make(map[string]string, len(t.Values))
+	for k, v := range t.Values {
+		out[k] = placeholder.ReplaceAllStringFunc(v, func(s string) string {
+			name := placeholder.FindStringSubmatch(s)[1]
+			if fill, ok := fills[name]; ok {
+				return fill
+			}
+			return s
+		})
+	}
+	return out
+}
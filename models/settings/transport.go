@@ -0,0 +1,94 @@
+package settings
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+)
+
+// newTransport builds the HTTP transport shared by the clients of a
+// network from its proxy and TLS options.
+func newTransport(info NetworkInfo) (http.RoundTripper, error) {
+	base := http.DefaultTransport.(*http.Transport).Clone()
+
+	if info.Proxy != "" {
+		proxy, err := url.Parse(info.Proxy)
+		if err != nil || proxy.Host == "" {
+			return nil, fmt.Errorf("invalid proxy %q", info.Proxy)
+		}
+		base.Proxy = http.ProxyURL(proxy)
+	}
+
+	if info.CAFile != "" || info.InsecureSkipVerify {
+		tlsConfig := &tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			InsecureSkipVerify: info.InsecureSkipVerify, // opt-in, local development only
+		}
+		if info.CAFile != "" {
+			pem, err := os.ReadFile(expandHome(info.CAFile))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read CA file: %w", err)
+			}
+			roots, err := x509.SystemCertPool()
+			if err != nil {
+				roots = x509.NewCertPool()
+			}
+			if !roots.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no PEM certificates found in %s", info.CAFile)
+			}
+			tlsConfig.RootCAs = roots
+		}
+		base.TLSClientConfig = tlsConfig
+	}
+	return base, nil
+}
+
+// tokenHeader returns the header the API token is sent in, def unless
+// the network names another one (e.g. X-API-Key for hosted providers).
+func (n NetworkInfo) tokenHeader(def string) string {
+	if h := strings.TrimSpace(n.TokenHeader); h != "" {
+		return http.CanonicalHeaderKey(h)
+	}
+	return def
+}
+
+// FormatHeaders renders headers as the "Name: value; Name: value" list
+// used by the network editor.
+func FormatHeaders(headers map[string]string) string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, name+": "+headers[name])
+	}
+	return strings.Join(parts, "; ")
+}
+
+// ParseHeaders is the inverse of FormatHeaders.
+func ParseHeaders(s string) (map[string]string, error) {
+	headers := map[string]string{}
+	for _, part := range strings.Split(s, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(part, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || strings.ContainsAny(name, " \t") {
+			return nil, fmt.Errorf("invalid header %q, expected Name: value", part)
+		}
+		headers[http.CanonicalHeaderKey(name)] = strings.TrimSpace(value)
+	}
+	if len(headers) == 0 {
+		return nil, nil
+	}
+	return headers, nil
+}
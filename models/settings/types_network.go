@@ -11,6 +11,8 @@ type NetworkStatus struct {
 	LastRound      uint64    `json:"last_round"`
 	GenesisID      string    `json:"genesis_id"`
 	GenesisHash    string    `json:"genesis_hash"`
+	ProtoVersion   string    `json:"protocol_version"`
+	BuildVersion   string    `json:"build_version"`
 	IndexerHealthy bool      `json:"indexer_healthy"`
 	Timestamp      time.Time `json:"timestamp"`
 }
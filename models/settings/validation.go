@@ -16,5 +16,13 @@ func ValidateNetworkConfig(networkInfo NetworkInfo) error {
 	if networkInfo.IndexerURL != "" && networkInfo.IndexerPort == "" {
 		return fmt.Errorf("indexer port required when indexer URL is provided")
 	}
+	for _, e := range append(append([]Endpoint{}, networkInfo.AlgodFallbacks...), networkInfo.IndexerFallbacks...) {
+		if err := validateEndpoint(e); err != nil {
+			return fmt.Errorf("fallback: %w", err)
+		}
+	}
+	if _, err := newTransport(networkInfo); err != nil {
+		return err
+	}
 	return nil
 }
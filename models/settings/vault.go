@@ -0,0 +1,342 @@
+package settings
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/mnemonic"
+	"golang.org/x/crypto/argon2"
+)
+
+// Argon2id parameters for new vaults. They are stored in the vault file
+// so they can be raised later without breaking existing vaults.
+const (
+	vaultKDFTime    = 3
+	vaultKDFMemory  = 64 * 1024 // KiB
+	vaultKDFThreads = 4
+	vaultKeyLen     = 32
+)
+
+// Bounds for the Argon2id parameters read from a vault file: argon2
+// panics without threads, and a forged file must not exhaust the memory.
+const (
+	vaultKDFMaxTime    = 16
+	vaultKDFMaxMemory  = 4 * 1024 * 1024 // KiB, 4 GiB
+	vaultKDFMaxThreads = 64
+)
+
+var (
+	ErrVaultLocked     = errors.New("vault is locked")
+	ErrVaultMissing    = errors.New("vault has not been created")
+	ErrWrongPassphrase = errors.New("wrong passphrase")
+)
+
+// NetworkSecrets are the API tokens of a network kept in the vault.
+// Fallback tokens and headers are keyed by endpoint base URL. Custom
+// headers are kept here whole, as providers put their API keys in them.
+type NetworkSecrets struct {
+	AlgodToken      string                       `json:"algod_token,omitempty"`
+	IndexerToken    string                       `json:"indexer_token,omitempty"`
+	KmdToken        string                       `json:"kmd_token,omitempty"`
+	Headers         map[string]string            `json:"headers,omitempty"` // often carry API keys
+	FallbackTokens  map[string]string            `json:"fallback_tokens,omitempty"`
+	FallbackHeaders map[string]map[string]string `json:"fallback_headers,omitempty"`
+}
+
+// StoredAccount is a mnemonic imported into the vault.
+type StoredAccount struct {
+	Label    string `json:"label"`
+	Address  string `json:"address"`
+	Mnemonic string `json:"mnemonic"`
+}
+
+// vaultData is the plaintext content of the vault.
+type vaultData struct {
+	Networks map[string]NetworkSecrets `json:"networks"`
+	Accounts []StoredAccount           `json:"accounts"`
+}
+
+// vaultFile is the on-disk envelope.
+type vaultFile struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Salt       []byte `json:"salt"`
+	Time       uint32 `json:"time"`
+	Memory     uint32 `json:"memory"`
+	Threads    uint8  `json:"threads"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// Vault is an encrypted local store for API tokens and imported mnemonics,
+// protected by a passphrase through Argon2id and sealed with AES-256-GCM.
+type Vault struct {
+	path string
+	env  vaultFile
+	key  []byte // nil while locked
+	data vaultData
+}
+
+func VaultPath() string {
+	return filepath.Join(filepath.Dir(ConfigPath()), "vault.json")
+}
+
+// OpenVault loads the vault envelope, if any. The vault starts locked.
+func OpenVault() *Vault {
+	v := &Vault{path: VaultPath()}
+	if buff, err := os.ReadFile(v.path); err == nil {
+		_ = json.Unmarshal(buff, &v.env)
+	}
+	return v
+}
+
+func (v *Vault) Exists() bool     { return len(v.env.Ciphertext) > 0 }
+func (v *Vault) IsUnlocked() bool { return v.key != nil }
+
+func (v *Vault) deriveKey(passphrase string) ([]byte, error) {
+	e := v.env
+	switch {
+	case e.Time < 1 || e.Time > vaultKDFMaxTime:
+		return nil, fmt.Errorf("corrupted vault: argon2 time %d out of range 1-%d", e.Time, vaultKDFMaxTime)
+	case e.Threads < 1 || e.Threads > vaultKDFMaxThreads:
+		return nil, fmt.Errorf("corrupted vault: argon2 threads %d out of range 1-%d", e.Threads, vaultKDFMaxThreads)
+	case e.Memory < 8*uint32(e.Threads) || e.Memory > vaultKDFMaxMemory:
+		return nil, fmt.Errorf("corrupted vault: argon2 memory %d KiB out of range %d-%d", e.Memory, 8*uint32(e.Threads), vaultKDFMaxMemory)
+	case len(e.Salt) < 8:
+		return nil, errors.New("corrupted vault: salt too short")
+	}
+	return argon2.IDKey([]byte(passphrase), e.Salt, e.Time, e.Memory, e.Threads, vaultKeyLen), nil
+}
+
+// Create initialises a new, empty vault protected by passphrase and
+// leaves it unlocked.
+func (v *Vault) Create(passphrase string) error {
+	if len(passphrase) < 8 {
+		return errors.New("passphrase must be at least 8 characters")
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	v.env = vaultFile{
+		Version: 1,
+		KDF:     "argon2id",
+		Salt:    salt,
+		Time:    vaultKDFTime,
+		Memory:  vaultKDFMemory,
+		Threads: vaultKDFThreads,
+	}
+	key, err := v.deriveKey(passphrase)
+	if err != nil {
+		return err
+	}
+	v.key = key
+	v.data = vaultData{Networks: map[string]NetworkSecrets{}}
+	return v.Save()
+}
+
+// Unlock derives the key and decrypts the vault content.
+func (v *Vault) Unlock(passphrase string) error {
+	if !v.Exists() {
+		return ErrVaultMissing
+	}
+	key, err := v.deriveKey(passphrase)
+	if err != nil {
+		return err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	plain, err := gcm.Open(nil, v.env.Nonce, v.env.Ciphertext, nil)
+	if err != nil {
+		return ErrWrongPassphrase
+	}
+	var data vaultData
+	if err := json.Unmarshal(plain, &data); err != nil {
+		return fmt.Errorf("corrupted vault: %w", err)
+	}
+	if data.Networks == nil {
+		data.Networks = map[string]NetworkSecrets{}
+	}
+	v.key = key
+	v.data = data
+	return nil
+}
+
+// Lock forgets the key and the decrypted content.
+func (v *Vault) Lock() {
+	for i := range v.key {
+		v.key[i] = 0
+	}
+	v.key = nil
+	v.data = vaultData{}
+}
+
+// Save encrypts the content with a fresh nonce and writes it owner-only.
+func (v *Vault) Save() error {
+	if !v.IsUnlocked() {
+		return ErrVaultLocked
+	}
+	plain, err := json.Marshal(v.data)
+	if err != nil {
+		return err
+	}
+	gcm, err := newGCM(v.key)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	v.env.Nonce = nonce
+	v.env.Ciphertext = gcm.Seal(nil, nonce, plain, nil)
+
+	buff, err := json.MarshalIndent(v.env, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writePrivateFile(v.path, buff)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// NetworkSecrets returns the stored tokens of a network.
+func (v *Vault) NetworkSecrets(name string) (NetworkSecrets, bool) {
+	s, ok := v.data.Networks[name]
+	return s, ok
+}
+
+// SetNetworkSecrets stores (or with empty secrets, removes) network tokens.
+func (v *Vault) SetNetworkSecrets(name string, s NetworkSecrets) error {
+	if !v.IsUnlocked() {
+		return ErrVaultLocked
+	}
+	if s.AlgodToken == "" && s.IndexerToken == "" && s.KmdToken == "" && len(s.FallbackTokens) == 0 && len(s.Headers) == 0 &&
+		len(s.FallbackHeaders) == 0 {
+		delete(v.data.Networks, name)
+	} else {
+		v.data.Networks[name] = s
+	}
+	return nil
+}
+
+// Accounts lists the imported accounts.
+func (v *Vault) Accounts() []StoredAccount { return v.data.Accounts }
+
+// AddAccount validates a 25-word mnemonic and stores it under label.
+func (v *Vault) AddAccount(label, words string) (StoredAccount, error) {
+	if !v.IsUnlocked() {
+		return StoredAccount{}, ErrVaultLocked
+	}
+	words = strings.Join(strings.Fields(words), " ")
+	sk, err := mnemonic.ToPrivateKey(words)
+	if err != nil {
+		return StoredAccount{}, fmt.Errorf("invalid mnemonic: %w", err)
+	}
+	acct, err := crypto.AccountFromPrivateKey(sk)
+	if err != nil {
+		return StoredAccount{}, fmt.Errorf("invalid mnemonic: %w", err)
+	}
+	addr := acct.Address.String()
+	for _, a := range v.data.Accounts {
+		if a.Address == addr {
+			return StoredAccount{}, fmt.Errorf("account %s already imported as %q", addr, a.Label)
+		}
+	}
+	if strings.TrimSpace(label) == "" {
+		label = addr[:8]
+	}
+	stored := StoredAccount{Label: strings.TrimSpace(label), Address: addr, Mnemonic: words}
+	v.data.Accounts = append(v.data.Accounts, stored)
+	return stored, nil
+}
+
+// RemoveAccount deletes an imported account by address.
+func (v *Vault) RemoveAccount(address string) error {
+	if !v.IsUnlocked() {
+		return ErrVaultLocked
+	}
+	for i, a := range v.data.Accounts {
+		if a.Address == address {
+			v.data.Accounts = append(v.data.Accounts[:i], v.data.Accounts[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("account %s not found", address)
+}
+
+// MnemonicFor returns the mnemonic of an imported account.
+func (v *Vault) MnemonicFor(address string) (string, error) {
+	if !v.IsUnlocked() {
+		return "", ErrVaultLocked
+	}
+	for _, a := range v.data.Accounts {
+		if a.Address == address {
+			return a.Mnemonic, nil
+		}
+	}
+	return "", fmt.Errorf("no mnemonic stored for %s", address)
+}
+
+// SecretsOf extracts the tokens of a network, see NetworkSecrets.
+func SecretsOf(n NetworkInfo) NetworkSecrets {
+	s := NetworkSecrets{AlgodToken: n.AlgodToken, IndexerToken: n.IndexerToken, KmdToken: n.KmdToken, Headers: n.Headers}
+	for _, e := range append(append([]Endpoint{}, n.AlgodFallbacks...), n.IndexerFallbacks...) {
+		if e.Token != "" {
+			if s.FallbackTokens == nil {
+				s.FallbackTokens = map[string]string{}
+			}
+			s.FallbackTokens[e.BaseURL()] = e.Token
+		}
+		if len(e.Headers) > 0 {
+			if s.FallbackHeaders == nil {
+				s.FallbackHeaders = map[string]map[string]string{}
+			}
+			s.FallbackHeaders[e.BaseURL()] = e.Headers
+		}
+	}
+	return s
+}
+
+// WithSecrets returns n with the given tokens filled in.
+func (n NetworkInfo) WithSecrets(s NetworkSecrets) NetworkInfo {
+	n.AlgodToken = s.AlgodToken
+	n.IndexerToken = s.IndexerToken
+	n.KmdToken = s.KmdToken
+	n.Headers = s.Headers
+	fill := func(eps []Endpoint) []Endpoint {
+		out := make([]Endpoint, len(eps))
+		for i, e := range eps {
+			e.Token = s.FallbackTokens[e.BaseURL()]
+			e.Headers = s.FallbackHeaders[e.BaseURL()]
+			out[i] = e
+		}
+		return out
+	}
+	n.AlgodFallbacks = fill(n.AlgodFallbacks)
+	n.IndexerFallbacks = fill(n.IndexerFallbacks)
+	return n
+}
+
+// HasSecrets reports whether any token is set on n.
+func (n NetworkInfo) HasSecrets() bool {
+	s := SecretsOf(n)
+	return s.AlgodToken != "" || s.IndexerToken != "" || s.KmdToken != "" || len(s.FallbackTokens) > 0 || len(s.Headers) > 0 ||
+		len(s.FallbackHeaders) > 0
+}
@@ -0,0 +1,127 @@
+package settings
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/mnemonic"
+)
+
+// openVaultAt loads the vault at path the way OpenVault does.
+func openVaultAt(t *testing.T, path string) *Vault {
+	t.Helper()
+	v := &Vault{path: path}
+	buff, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(buff, &v.env); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestVaultRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vault.json")
+	acct := crypto.GenerateAccount()
+	words, err := mnemonic.FromPrivateKey(acct.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secrets := NetworkSecrets{AlgodToken: "algod-secret", Headers: map[string]string{"X-Api-Key": "header-secret"},
+		FallbackTokens: map[string]string{"https://backup.example": "fallback-secret"}}
+
+	v := &Vault{path: path}
+	if err := v.Create("short"); err == nil {
+		t.Error("Create with a short passphrase: want an error")
+	}
+	if err := v.Create("correct horse battery"); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := v.SetNetworkSecrets("mainnet", secrets); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := v.AddAccount("main", words); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, _ := os.ReadFile(path)
+	for _, secret := range []string{"algod-secret", "header-secret", "fallback-secret", strings.Fields(words)[0] + " "} {
+		if strings.Contains(string(raw), secret) {
+			t.Errorf("vault file holds %q in clear", secret)
+		}
+	}
+	if info, err := os.Stat(path); err == nil && info.Mode().Perm() != 0600 {
+		t.Errorf("vault file mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	reopened := openVaultAt(t, path)
+	if reopened.IsUnlocked() {
+		t.Fatal("a reopened vault must start locked")
+	}
+	if err := reopened.Unlock("correct horse battery!"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Fatalf("Unlock with the wrong passphrase: error = %v, want ErrWrongPassphrase", err)
+	}
+	if reopened.IsUnlocked() {
+		t.Fatal("vault unlocked by a wrong passphrase")
+	}
+	if _, err := reopened.MnemonicFor(acct.Address.String()); !errors.Is(err, ErrVaultLocked) {
+		t.Errorf("MnemonicFor while locked: error = %v, want ErrVaultLocked", err)
+	}
+
+	if err := reopened.Unlock("correct horse battery"); err != nil {
+		t.Fatalf("Unlock: %v", err)
+	}
+	if got, ok := reopened.NetworkSecrets("mainnet"); !ok || !reflect.DeepEqual(got, secrets) {
+		t.Errorf("NetworkSecrets = %+v, %v, want %+v", got, ok, secrets)
+	}
+	if got, err := reopened.MnemonicFor(acct.Address.String()); err != nil || got != words {
+		t.Errorf("MnemonicFor = %q, %v", got, err)
+	}
+
+	reopened.Lock()
+	if _, ok := reopened.NetworkSecrets("mainnet"); ok || reopened.IsUnlocked() {
+		t.Error("Lock left the secrets readable")
+	}
+}
+
+func TestVaultTampered(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vault.json")
+	v := &Vault{path: path}
+	if err := v.Create("correct horse battery"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		tamper func(f *vaultFile)
+		want   string
+	}{
+		{"ciphertext", func(f *vaultFile) { f.Ciphertext[0] ^= 1 }, "wrong passphrase"},
+		{"nonce", func(f *vaultFile) { f.Nonce[0] ^= 1 }, "wrong passphrase"},
+		{"no threads", func(f *vaultFile) { f.Threads = 0 }, "threads"},
+		{"huge memory", func(f *vaultFile) { f.Memory = vaultKDFMaxMemory + 1 }, "memory"},
+		{"short salt", func(f *vaultFile) { f.Salt = f.Salt[:4] }, "salt"},
+	}
+	for _, tt := range tests {
+		forged := openVaultAt(t, path)
+		tt.tamper(&forged.env)
+		err := forged.Unlock("correct horse battery")
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: Unlock error = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+
+	if err := (&Vault{path: filepath.Join(t.TempDir(), "none.json")}).Unlock("x"); !errors.Is(err, ErrVaultMissing) {
+		t.Errorf("Unlock without a vault: error = %v, want ErrVaultMissing", err)
+	}
+}
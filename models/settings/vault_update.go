@@ -0,0 +1,264 @@
+package settings
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Vault panel modes.
+const (
+	vaultModeList          = "list"
+	vaultModePassphrase    = "passphrase"
+	vaultModeNewPassphrase = "new_passphrase"
+	vaultModeConfirm       = "confirm_passphrase"
+	vaultModeLabel         = "label"
+	vaultModeMnemonic      = "mnemonic"
+)
+
+// Vault returns the secrets vault, used to restore signers.
+func (m *SettingsModel) Vault() *Vault { return m.vault }
+
+// openVaultPanel shows the vault panel in the state matching the vault.
+func (m *SettingsModel) openVaultPanel() {
+	m.vaultInput = ""
+	m.vaultPending = ""
+	m.vaultMsg = ""
+	switch {
+	case m.vault.IsUnlocked():
+		m.vaultMode = vaultModeList
+	case m.vault.Exists():
+		m.vaultMode = vaultModePassphrase
+	default:
+		m.vaultMode = vaultModeNewPassphrase
+	}
+}
+
+func (m *SettingsModel) handleVault(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.vaultMode == vaultModeList {
+		return m.handleVaultList(msg)
+	}
+
+	switch msg.String() {
+	case "esc":
+		if m.vaultMode == vaultModeLabel || m.vaultMode == vaultModeMnemonic {
+			m.vaultMode = vaultModeList
+		} else {
+			m.vaultMode = ""
+		}
+		m.vaultInput = ""
+		m.vaultPending = ""
+		return m, nil
+	case "enter":
+		m.submitVaultInput()
+		return m, nil
+	}
+
+	switch msg.Type {
+	case tea.KeyRunes, tea.KeySpace:
+		m.vaultInput += string(msg.Runes)
+	case tea.KeyBackspace:
+		if r := []rune(m.vaultInput); len(r) > 0 {
+			m.vaultInput = string(r[:len(r)-1])
+		}
+	}
+	return m, nil
+}
+
+func (m *SettingsModel) submitVaultInput() {
+	input := m.vaultInput
+	m.vaultInput = ""
+
+	switch m.vaultMode {
+	case vaultModePassphrase:
+		if err := m.vault.Unlock(input); err != nil {
+			m.vaultMsg = err.Error()
+			return
+		}
+		m.applyVaultSecrets()
+		m.applyOverrides()
+		m.vaultMode = vaultModeList
+		m.vaultMsg = "Vault unlocked"
+
+	case vaultModeNewPassphrase:
+		if len(input) < 8 {
+			m.vaultMsg = "Passphrase must be at least 8 characters"
+			return
+		}
+		m.vaultPending = input
+		m.vaultMode = vaultModeConfirm
+		m.vaultMsg = ""
+
+	case vaultModeConfirm:
+		if input != m.vaultPending {
+			m.vaultPending = ""
+			m.vaultMode = vaultModeNewPassphrase
+			m.vaultMsg = "Passphrases do not match, try again"
+			return
+		}
+		m.vaultPending = ""
+		if err := m.vault.Create(input); err != nil {
+			m.vaultMsg = fmt.Sprintf("Failed to create vault: %v", err)
+			return
+		}
+		// Move the tokens out of the config file
+		m.config.VaultEnabled = true
+		if err := m.persistConfig(); err != nil {
+			m.vaultMsg = fmt.Sprintf("Vault created but config not saved: %v", err)
+		} else {
+			m.vaultMsg = "Vault created, tokens moved out of config.json"
+		}
+		m.vaultMode = vaultModeList
+
+	case vaultModeLabel:
+		m.vaultPending = input
+		m.vaultMode = vaultModeMnemonic
+
+	case vaultModeMnemonic:
+		acct, err := m.vault.AddAccount(m.vaultPending, input)
+		m.vaultPending = ""
+		m.vaultMode = vaultModeList
+		if err != nil {
+			m.vaultMsg = err.Error()
+			return
+		}
+		if err := m.vault.Save(); err != nil {
+			m.vaultMsg = fmt.Sprintf("Failed to save vault: %v", err)
+			return
+		}
+		m.vaultCursor = len(m.vault.Accounts()) - 1
+		m.vaultMsg = fmt.Sprintf("Imported %s", acct.Label)
+	}
+}
+
+func (m *SettingsModel) handleVaultList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.vaultRemove != "" {
+		return m.handleVaultRemove(msg)
+	}
+	accounts := m.vault.Accounts()
+	switch msg.String() {
+	case "esc":
+		m.vaultMode = ""
+		m.vaultMsg = ""
+	case "up":
+		if m.vaultCursor > 0 {
+			m.vaultCursor--
+		}
+	case "down":
+		if m.vaultCursor < len(accounts)-1 {
+			m.vaultCursor++
+		}
+	case "m":
+		m.vaultMode = vaultModeLabel
+		m.vaultInput = ""
+		m.vaultMsg = ""
+	case "d":
+		if m.vaultCursor < len(accounts) {
+			acct := accounts[m.vaultCursor]
+			m.vaultRemove = acct.Address
+			m.vaultMsg = fmt.Sprintf("Remove %s and its mnemonic from the vault? (y/n)", acct.Label)
+		}
+	case "enter":
+		// Use the imported account as wallet address
+		if m.vaultCursor < len(accounts) {
+			m.config.WalletAddr = accounts[m.vaultCursor].Address
+			m.overrides.WalletAddr = ""
+			if err := m.persistConfig(); err != nil {
+				m.vaultMsg = err.Error()
+			} else {
+				m.vaultMsg = fmt.Sprintf("Wallet set to %s", accounts[m.vaultCursor].Label)
+			}
+		}
+	case "L":
+		m.lockVault()
+		m.vaultMode = ""
+	}
+	return m, nil
+}
+
+// handleVaultRemove answers a pending removal: the mnemonic is gone for
+// good once the vault is saved.
+func (m *SettingsModel) handleVaultRemove(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	addr := m.vaultRemove
+	switch msg.String() {
+	case "y", "Y", "enter":
+		m.vaultRemove = ""
+		label := addr
+		for _, a := range m.vault.Accounts() {
+			if a.Address == addr {
+				label = a.Label
+			}
+		}
+		if err := m.vault.RemoveAccount(addr); err != nil {
+			m.vaultMsg = err.Error()
+			return m, nil
+		}
+		if err := m.vault.Save(); err != nil {
+			m.vaultMsg = fmt.Sprintf("Failed to save vault: %v", err)
+		} else {
+			m.vaultMsg = fmt.Sprintf("Removed %s", label)
+		}
+		if m.vaultCursor > 0 && m.vaultCursor >= len(m.vault.Accounts()) {
+			m.vaultCursor--
+		}
+	case "n", "N", "esc":
+		m.vaultRemove = ""
+		m.vaultMsg = ""
+	}
+	return m, nil
+}
+
+// applyVaultSecrets fills network tokens from the unlocked vault.
+func (m *SettingsModel) applyVaultSecrets() {
+	if !m.vault.IsUnlocked() {
+		return
+	}
+	for i, n := range m.config.CustomNetworks {
+		if s, ok := m.vault.NetworkSecrets(n.Name); ok {
+			m.config.CustomNetworks[i] = n.WithSecrets(s)
+			m.networkInfos[n.Name] = m.config.CustomNetworks[i]
+		}
+	}
+}
+
+// lockVault locks the vault and drops the tokens it provided from memory.
+// Live connections keep working until the next reconnect.
+func (m *SettingsModel) lockVault() {
+	m.vault.Lock()
+	if !m.config.VaultEnabled {
+		return
+	}
+	for i, n := range m.config.CustomNetworks {
+		m.config.CustomNetworks[i] = n.WithSecrets(NetworkSecrets{})
+		m.networkInfos[n.Name] = m.config.CustomNetworks[i]
+	}
+}
+
+// persistConfig saves the config, routing tokens to the vault when it is
+// enabled. Saving new tokens requires the vault to be unlocked.
+func (m *SettingsModel) persistConfig() error {
+	if m.config.VaultEnabled {
+		if m.vault.IsUnlocked() {
+			for _, n := range m.config.CustomNetworks {
+				if err := m.vault.SetNetworkSecrets(n.Name, SecretsOf(n)); err != nil {
+					return err
+				}
+			}
+			if err := m.vault.Save(); err != nil {
+				return fmt.Errorf("failed to save vault: %w", err)
+			}
+		} else {
+			for _, n := range m.config.CustomNetworks {
+				if n.HasSecrets() {
+					return fmt.Errorf("unlock the vault (v) to save tokens for %s", n.Name)
+				}
+			}
+		}
+	}
+	cfg := m.persistableConfig()
+	if err := SaveConfig(cfg); err != nil {
+		return err
+	}
+	m.config.Profiles = cfg.Profiles
+	return nil
+}
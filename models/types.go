@@ -9,4 +9,6 @@ const (
 	ApplicationsView
 	CmdGoalsView
 	ExploreView
+	NodeView
+	DashboardView
 )
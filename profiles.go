@@ -0,0 +1,222 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"lazychain/layout"
+	. "lazychain/models/settings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// configureRunner points goal at the node of the selected network. The
+// active profile refines it, flags and environment still win.
+func (m *MainModel) configureRunner() {
+	runner := m.CmdGoalsModel.Runner()
+	runner.DataDirs, runner.KmdDir, runner.Wallet = nil, "", ""
+	if info, ok := m.SettingsModel.NetworkInfo(m.SettingsModel.CurrentNetwork()); ok {
+		if info.DataDir != "" {
+			runner.DataDirs = []string{info.DataDir}
+		}
+		runner.KmdDir = info.KmdDir
+		runner.Wallet = info.KmdWallet
+	}
+	if p, ok := m.SettingsModel.ActiveProfile(); ok {
+		if p.GoalDataDir != "" {
+			runner.DataDirs = []string{p.GoalDataDir}
+		}
+		if p.KmdWallet != "" {
+			runner.Wallet = p.KmdWallet
+		}
+	}
+	if m.overrides.GoalBinary != "" {
+		runner.Binary = m.overrides.GoalBinary
+	}
+	if m.overrides.DataDir != "" {
+		runner.DataDirs = []string{m.overrides.DataDir}
+	}
+}
+
+// syncProfiles refreshes the switcher with the configured profiles.
+func (m *MainModel) syncProfiles() {
+	m.ProjectModel.Profiles = m.SettingsModel.Profiles()
+	m.ProjectModel.ActiveProfile = ""
+	if p, ok := m.SettingsModel.ActiveProfile(); ok {
+		m.ProjectModel.ActiveProfile = p.Name
+	}
+}
+
+// rememberNode stores the data dir chosen in the node view in the
+// active profile.
+func (m *MainModel) rememberNode() {
+	runner := m.CmdGoalsModel.Runner()
+	dataDir := ""
+	if len(runner.DataDirs) > 0 {
+		dataDir = runner.DataDirs[0]
+	}
+	// The network's own node needs no per-profile copy
+	if info, ok := m.SettingsModel.NetworkInfo(m.SettingsModel.CurrentNetwork()); ok && info.DataDir == dataDir {
+		dataDir = ""
+	}
+	_ = m.SettingsModel.UpdateActiveProfile(func(p *Profile) {
+		p.GoalDataDir = dataDir
+	})
+}
+
+func (m *MainModel) switchProfile(name string) tea.Cmd {
+	if _, err := m.SettingsModel.SwitchProfile(name); err != nil {
+		m.ProjectModel.ProfileErr = err.Error()
+		m.ProjectModel.OpenProfiles()
+		return nil
+	}
+	m.configureRunner()
+	m.syncProfiles()
+	// Connect to the profile's network right away
+	return m.SettingsModel.AutoConnect()
+}
+
+func (m *MainModel) createProfile(name string) {
+	defer m.ProjectModel.OpenProfiles()
+	if _, exists := m.SettingsModel.Profile(name); exists {
+		m.ProjectModel.ProfileErr = fmt.Sprintf("Profile %s already exists", name)
+		return
+	}
+	runner := m.CmdGoalsModel.Runner()
+	p := Profile{
+		Name:       name,
+		Network:    m.SettingsModel.CurrentNetwork(),
+		WalletAddr: m.SettingsModel.WalletAddr(),
+		KmdWallet:  runner.Wallet,
+	}
+	if len(runner.DataDirs) > 0 {
+		p.GoalDataDir = runner.DataDirs[0]
+	}
+	if err := m.SettingsModel.SaveProfile(p); err != nil {
+		m.ProjectModel.ProfileErr = err.Error()
+	}
+	m.syncProfiles()
+}
+
+func (m *MainModel) deleteProfile(name string) {
+	if err := m.SettingsModel.DeleteProfile(name); err != nil {
+		m.ProjectModel.ProfileErr = err.Error()
+	} else {
+		m.ProjectModel.ProfileErr = ""
+	}
+	m.syncProfiles()
+	m.ProjectModel.OpenProfiles()
+}
+
+// profilePreview describes a switcher entry.
+func (m *MainModel) profilePreview(item string) layout.PreviewContent {
+	instructions := "Enter: switch\nd: delete\nESC: back to menu"
+	var desc []string
+	if m.ProjectModel.ProfileErr != "" {
+		desc = append(desc, "! "+m.ProjectModel.ProfileErr, "")
+	}
+
+	name := strings.TrimSuffix(item, " *")
+	p, ok := m.SettingsModel.Profile(name)
+	if !ok {
+		if m.ProjectModel.IsNaming() {
+			desc = append(desc, "Name: "+m.ProjectModel.NameInput()+"_")
+			instructions = "Enter: create\nESC: cancel"
+		} else {
+			desc = append(desc, "Save the current network, account, node data dir and kmd wallet as a new profile")
+			instructions = "Enter: name the profile\nESC: back to menu"
+		}
+		return layout.PreviewContent{Title: item, Description: strings.Join(desc, "\n"), Instructions: instructions}
+	}
+
+	wallet := p.WalletAddr
+	if wallet == "" {
+		wallet = "not set"
+	} else if len(wallet) > 12 {
+		wallet = wallet[:6] + "..." + wallet[len(wallet)-4:]
+	}
+	dataDir := p.GoalDataDir
+	if dataDir == "" {
+		dataDir = "$ALGORAND_DATA"
+	}
+	desc = append(desc,
+		"Network: "+p.Network,
+		"Wallet: "+wallet,
+		"Data dir: "+dataDir,
+	)
+	if p.KmdWallet != "" {
+		desc = append(desc, "Kmd wallet: "+p.KmdWallet)
+	}
+	desc = append(desc, fmt.Sprintf("Contacts: %d", len(p.AddressBook)))
+	return layout.PreviewContent{Title: p.Name, Description: strings.Join(desc, "\n"), Instructions: instructions}
+}
+
+// renderHeader shows the active profile and network above every screen.
+func (m *MainModel) renderHeader() string {
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086"))
+	sep := dim.Render("  │  ")
+
+	profile := dim.Render("no profile")
+	if p, ok := m.SettingsModel.ActiveProfile(); ok {
+		profile = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#cba6f7")).Render(p.Name)
+	}
+
+	// Connection state, kept up to date by the background connect
+	link := m.SettingsModel.Link()
+	network := link.Network
+	state := dim.Render(" (offline)")
+	switch link.Status {
+	case LinkConnected:
+		detail := " (connected)"
+		if link.Round > 0 {
+			detail = fmt.Sprintf(" (round %d)", link.Round)
+		}
+		state = lipgloss.NewStyle().Foreground(lipgloss.Color("#a6e3a1")).Render(detail)
+	case LinkConnecting:
+		state = lipgloss.NewStyle().Foreground(lipgloss.Color("#f9e2af")).Render(" (connecting...)")
+	case LinkReconnecting:
+		state = lipgloss.NewStyle().Foreground(lipgloss.Color("#fab387")).Render(fmt.Sprintf(" (reconnecting, attempt %d)", link.Attempt))
+	}
+
+	wallet := m.SettingsModel.WalletAddr()
+	if wallet == "" {
+		wallet = "no wallet"
+	} else if len(wallet) > 12 {
+		wallet = wallet[:6] + "..." + wallet[len(wallet)-4:]
+	}
+
+	return " Profile: " + profile + sep + "Network: " + network + state + sep + "Wallet: " + wallet + " "
+}
+
+// withHeader draws the header centered over the first line of a full
+// screen view, like a title on its top border, so every view keeps its
+// full height and minimum size.
+func (m *MainModel) withHeader(view string) string {
+	lines := strings.SplitN(view, "\n", 2)
+	header := m.renderHeader()
+	width := ansi.StringWidth(lines[0])
+	hw := ansi.StringWidth(header)
+	if hw > width {
+		return view
+	}
+	left := (width - hw) / 2
+	lines[0] = ansi.Truncate(lines[0], left, "") + header + ansi.TruncateLeft(lines[0], left+hw, "")
+	return strings.Join(lines, "\n")
+}
+
+// menuState returns the current menu items and cursor of the project view.
+func (m *MainModel) menuState() ([]string, int, string, func(string) layout.PreviewContent) {
+	items, cursor := m.ProjectModel.MenuItems()
+	if m.ProjectModel.IsPicking() {
+		return items, cursor, "Profiles", m.profilePreview
+	}
+	return items, cursor, "Main Menu", func(option string) layout.PreviewContent {
+		return layout.PreviewContent{
+			Title:        option,
+			Description:  subtitleFor(option),
+			Instructions: "Press ENTER to select\nPress ESC to go back",
+		}
+	}
+}